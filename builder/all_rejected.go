@@ -0,0 +1,25 @@
+package builder
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// onAllRelaysRejected escalates the submissions all relays rejected, which are likely a systemic issue such as a bad
+// builder key or signing domain rather than a relay's. The submission is posted to the alerting webhook and the
+// builder paused for allRejectedPause if set, so that the resubmissions don't loop on rejections.
+func (b *Builder) onAllRelaysRejected(submissionID string, req *boostTypes.BuilderSubmitBlockRequest, result SubmissionResult, err error) {
+	allRelaysRejectedCounter.Inc(1)
+	log.Error("all relays rejected the submission", "submission_id", submissionID, "slot", req.Message.Slot, "result", result)
+
+	if b.allRejectedWebhook != nil {
+		b.allRejectedWebhook.Notify(newSubmissionEvent(submissionID, req, result, err))
+	}
+	if b.allRejectedPause > 0 {
+		until := time.Now().Add(b.allRejectedPause)
+		b.pause.pauseRejected(until)
+		log.Warn("pausing the builder after all relays rejected a submission", "slot", req.Message.Slot, "until", until)
+	}
+}
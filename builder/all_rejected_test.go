@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllRelaysRejected(t *testing.T) {
+	srv := newWebhookServer(t, 0)
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{
+		SingleShot:               true,
+		AllRelaysRejectedPause:   time.Hour,
+		AllRelaysRejectedWebhook: NewWebhookNotifier(srv.URL, 0),
+	})
+
+	// Not a rejection, the first escalated submission is slot 25's
+	testRelay.submitErr = errors.New("connection refused")
+	builder.OnPayloadAttribute(newTestAttributes(24))
+
+	testRelay.submitErr = &RelayResponseError{StatusCode: 400, Body: "invalid signature"}
+	builder.OnPayloadAttribute(newTestAttributes(25))
+	require.Eventually(t, func() bool { return len(srv.received()) == 1 }, time.Second, 10*time.Millisecond)
+
+	event := srv.received()[0]
+	require.Equal(t, uint64(25), event.Slot)
+	require.True(t, event.AllRelaysRejected)
+	require.Equal(t, "HTTP error response: 400 / invalid signature", event.Error)
+
+	// The builder pauses rather than loop on the rejections
+	require.Equal(t, "all relays rejected", builder.Status().PauseReason)
+	testRelay.submitErr = nil
+	testRelay.submittedMsg = nil
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(26)))
+	require.Nil(t, testRelay.submittedMsg)
+}
+
+func TestSubmissionResultRejected(t *testing.T) {
+	rejected := fmt.Errorf("relay b: %w", &RelayResponseError{StatusCode: 400, Body: "invalid signature"})
+	require.False(t, SubmissionResult{}.Rejected())
+	require.False(t, SubmissionResult{{Relay: "a"}, {Relay: "b", Err: rejected}}.Rejected())
+	require.False(t, SubmissionResult{{Relay: "a", Skipped: true, Err: ErrRelayDisabled}}.Rejected())
+	require.True(t, SubmissionResult{{Relay: "a", Skipped: true, Err: ErrRelayDisabled}, {Relay: "b", Err: rejected}}.Rejected())
+
+	// The network errors, 5xx and 429 are not rejections
+	for _, err := range []error{
+		errors.New("connection refused"),
+		context.DeadlineExceeded,
+		&RelayResponseError{StatusCode: 502},
+		&RelayResponseError{StatusCode: 429},
+	} {
+		require.False(t, SubmissionResult{{Relay: "a", Err: err}}.Rejected())
+		require.False(t, SubmissionResult{{Relay: "a", Err: err}, {Relay: "b", Err: rejected}}.Rejected())
+	}
+}
@@ -0,0 +1,98 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+const (
+	// archiveQueueSize is the number of submissions queued for the archive sink, later submissions are dropped
+	archiveQueueSize = 256
+	archiveTimeout   = 10 * time.Second
+)
+
+// ArchiveSink durably records the submissions of the builder, such as a filesystem or an S3 compatible store.
+type ArchiveSink interface {
+	Archive(ctx context.Context, req *boostTypes.BuilderSubmitBlockRequest) error
+}
+
+// FileArchive archives the submissions to a directory, each as <slot>/<block hash>.json with the JSON encoded
+// BuilderSubmitBlockRequest. Unlike the block dumps the archived submissions are never removed.
+type FileArchive struct {
+	dir string
+}
+
+func NewFileArchive(dir string) (*FileArchive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileArchive{dir: dir}, nil
+}
+
+// Archive writes the submission, replacing the previous submission of the block if any. The file is written to a
+// temporary file first so that it is never partially written.
+func (a *FileArchive) Archive(ctx context.Context, req *boostTypes.BuilderSubmitBlockRequest) error {
+	slotDir := filepath.Join(a.dir, strconv.FormatUint(req.Message.Slot, 10))
+	if err := os.MkdirAll(slotDir, 0o755); err != nil {
+		return err
+	}
+
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(slotDir, "archive-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(reqJSON); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(slotDir, req.Message.BlockHash.String()+".json"))
+}
+
+// archiver archives the submissions in the background, so that the submissions never wait for the sink.
+type archiver struct {
+	sink  ArchiveSink
+	queue chan *boostTypes.BuilderSubmitBlockRequest
+}
+
+func newArchiver(sink ArchiveSink) *archiver {
+	a := &archiver{sink: sink, queue: make(chan *boostTypes.BuilderSubmitBlockRequest, archiveQueueSize)}
+	go a.run()
+	return a
+}
+
+// archive queues the submission, dropping it if the queue is full.
+func (a *archiver) archive(req *boostTypes.BuilderSubmitBlockRequest) {
+	select {
+	case a.queue <- req:
+	default:
+		archiveFailedCounter.Inc(1)
+		log.Warn("archive queue full, dropping submission", "slot", req.Message.Slot, "block_hash", req.Message.BlockHash)
+	}
+}
+
+func (a *archiver) run() {
+	for req := range a.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), archiveTimeout)
+		err := a.sink.Archive(ctx, req)
+		cancel()
+		if err != nil {
+			archiveFailedCounter.Inc(1)
+			log.Warn("could not archive submission", "err", err, "slot", req.Message.Slot, "block_hash", req.Message.BlockHash)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileArchive(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := NewFileArchive(dir)
+	require.NoError(t, err)
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SingleShot: true, ArchiveSink: archive})
+
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	path := filepath.Join(dir, "25", testRelay.submittedMsg.Message.BlockHash.String()+".json")
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var archived boostTypes.BuilderSubmitBlockRequest
+	require.NoError(t, json.Unmarshal(data, &archived))
+	require.Equal(t, testRelay.submittedMsg.Message, archived.Message)
+	require.Equal(t, testRelay.submittedMsg.Signature, archived.Signature)
+
+	// The temporary files don't remain
+	entries, err := os.ReadDir(filepath.Join(dir, "25"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+type failingArchive struct {
+	archived chan uint64
+}
+
+func (a *failingArchive) Archive(ctx context.Context, req *boostTypes.BuilderSubmitBlockRequest) error {
+	a.archived <- req.Message.Slot
+	return errors.New("store unavailable")
+}
+
+func TestArchiveBestEffort(t *testing.T) {
+	// The failed archives don't fail the submissions, which don't wait for them
+	archive := &failingArchive{archived: make(chan uint64)}
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SingleShot: true, ArchiveSink: archive})
+
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.NotNil(t, testRelay.submittedMsg)
+	require.Equal(t, uint64(25), <-archive.archived)
+}
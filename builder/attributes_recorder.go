@@ -0,0 +1,88 @@
+package builder
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type recordedAttributes struct {
+	ReceivedAt time.Time                 `json:"receivedAt"`
+	Attributes *BuilderPayloadAttributes `json:"attributes"`
+}
+
+// AttributesRecorder appends every received payload attribute to a JSONL file, so that it can be replayed with ReplayFile.
+type AttributesRecorder struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+func NewAttributesRecorder(path string) (*AttributesRecorder, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttributesRecorder{
+		file: file,
+		enc:  json.NewEncoder(file),
+	}, nil
+}
+
+func (r *AttributesRecorder) Record(attrs *BuilderPayloadAttributes) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.enc.Encode(recordedAttributes{ReceivedAt: time.Now(), Attributes: attrs})
+}
+
+func (r *AttributesRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file.Close()
+}
+
+// ReplayFile feeds the attributes recorded in path to the builder.
+// The delays between attributes are the recorded ones multiplied by timeScale, zero replays without delays.
+// Builder errors are logged and do not stop the replay.
+func ReplayFile(path string, builder IBuilder, timeScale float64) error {
+	if timeScale < 0 {
+		return errors.New("negative time scale")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var lastReceivedAt time.Time
+	scanner := bufio.NewScanner(file)
+	// Attributes lines are small, but leave room for build params
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry recordedAttributes
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Error("could not decode recorded attributes", "line", line, "err", err)
+			return err
+		}
+
+		if !lastReceivedAt.IsZero() && timeScale > 0 {
+			time.Sleep(time.Duration(float64(entry.ReceivedAt.Sub(lastReceivedAt)) * timeScale))
+		}
+		lastReceivedAt = entry.ReceivedAt
+
+		if err := builder.OnPayloadAttribute(entry.Attributes); err != nil {
+			log.Info("replayed attributes failed", "line", line, "slot", entry.Attributes.Slot, "err", err)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,68 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingBuilder struct {
+	attrs      []*BuilderPayloadAttributes
+	receivedAt []time.Time
+}
+
+func (b *recordingBuilder) OnPayloadAttribute(attrs *BuilderPayloadAttributes) error {
+	b.attrs = append(b.attrs, attrs)
+	b.receivedAt = append(b.receivedAt, time.Now())
+	return nil
+}
+
+func (b *recordingBuilder) GetPayload(blindedBlock *boostTypes.SignedBlindedBeaconBlock) (*boostTypes.ExecutionPayload, error) {
+	return nil, ErrPayloadNotFound
+}
+
+func TestAttributesRecorderReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attributes.jsonl")
+
+	recorder, err := NewAttributesRecorder(path)
+	require.NoError(t, err)
+
+	recorded := []*BuilderPayloadAttributes{
+		{Timestamp: hexutil.Uint64(104), Random: common.Hash{0x05}, Slot: 25, HeadHash: common.Hash{0x01}},
+		{Timestamp: hexutil.Uint64(116), Random: common.Hash{0x06}, Slot: 26, HeadHash: common.Hash{0x02}, BuildParams: &BuildParams{Iterations: 2}},
+	}
+	require.NoError(t, recorder.Record(recorded[0]))
+	time.Sleep(200 * time.Millisecond)
+	require.NoError(t, recorder.Record(recorded[1]))
+	require.NoError(t, recorder.Close())
+
+	// Original timing
+	b := &recordingBuilder{}
+	require.NoError(t, ReplayFile(path, b, 1))
+	require.Equal(t, recorded, b.attrs)
+	require.GreaterOrEqual(t, b.receivedAt[1].Sub(b.receivedAt[0]), 200*time.Millisecond)
+
+	// Compressed timing
+	b = &recordingBuilder{}
+	require.NoError(t, ReplayFile(path, b, 0))
+	require.Equal(t, recorded, b.attrs)
+	require.Less(t, b.receivedAt[1].Sub(b.receivedAt[0]), 100*time.Millisecond)
+
+	require.Error(t, ReplayFile(filepath.Join(t.TempDir(), "missing.jsonl"), b, 0))
+}
+
+func TestAttributesRecorderClosedOnStop(t *testing.T) {
+	recorder, err := NewAttributesRecorder(filepath.Join(t.TempDir(), "attributes.jsonl"))
+	require.NoError(t, err)
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{AttributesRecorder: recorder})
+
+	service := &Service{builder: builder}
+	require.NoError(t, service.Stop())
+	require.ErrorIs(t, recorder.Record(newTestAttributes(25)), os.ErrClosed)
+}
@@ -1,6 +1,7 @@
 package builder
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,34 +10,88 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
 )
 
 type testBeaconClient struct {
-	validator *ValidatorPrivateData
-	slot      uint64
+	validator      *ValidatorPrivateData
+	slot           uint64
+	genesisTime    uint64
+	secondsPerSlot uint64
+	randao         common.Hash
+	// randaoErr is returned for the randao mix if set
+	randaoErr      error
+	validatorIndex uint64
+	// proposerErr is returned for the proposer duties if set
+	proposerErr error
+	// genesisForkVersion is the fork version served if set, GetGenesisForkVersion fails otherwise
+	genesisForkVersion *boostTypes.ForkVersion
+}
+
+func (b *testBeaconClient) GetRandao(ctx context.Context) (common.Hash, error) {
+	if b.randaoErr != nil {
+		return common.Hash{}, b.randaoErr
+	}
+	return b.randao, nil
+}
+
+func (b *testBeaconClient) GetGenesis(ctx context.Context) (uint64, error) {
+	return b.genesisTime, nil
+}
+
+func (b *testBeaconClient) GetSpec(ctx context.Context) (uint64, error) {
+	if b.secondsPerSlot == 0 {
+		return defaultSecondsPerSlot, nil
+	}
+	return b.secondsPerSlot, nil
+}
+
+func (b *testBeaconClient) GetGenesisForkVersion(ctx context.Context) (boostTypes.ForkVersion, error) {
+	if b.genesisForkVersion == nil {
+		return boostTypes.ForkVersion{}, errors.New("genesis fork version not set")
+	}
+	return *b.genesisForkVersion, nil
 }
 
 func (b *testBeaconClient) isValidator(pubkey PubkeyHex) bool {
 	return true
 }
 func (b *testBeaconClient) getProposerForSlot(requestedSlot uint64) (PubkeyHex, error) {
+	if b.proposerErr != nil {
+		return "", b.proposerErr
+	}
 	return PubkeyHex(hexutil.Encode(b.validator.Pk)), nil
 }
+func (b *testBeaconClient) getProposerIndexForSlot(requestedSlot uint64) (uint64, error) {
+	return b.validatorIndex, nil
+}
+
+// proposerDuty is the validator proposing a slot.
+type proposerDuty struct {
+	Pubkey         PubkeyHex
+	ValidatorIndex uint64
+}
 
 type BeaconClient struct {
 	endpoint string
 
 	mu              sync.Mutex
 	currentEpoch    uint64
-	slotProposerMap map[uint64]PubkeyHex
+	slotProposerMap map[uint64]proposerDuty
+
+	// genesis and spec never change, they are only fetched once
+	genesisMu      sync.Mutex
+	genesisTime    uint64
+	secondsPerSlot uint64
 }
 
 func NewBeaconClient(endpoint string) *BeaconClient {
 	return &BeaconClient{
 		endpoint:        endpoint,
-		slotProposerMap: make(map[uint64]PubkeyHex),
+		slotProposerMap: make(map[uint64]proposerDuty),
 	}
 }
 
@@ -45,6 +100,23 @@ func (b *BeaconClient) isValidator(pubkey PubkeyHex) bool {
 }
 
 func (b *BeaconClient) getProposerForSlot(requestedSlot uint64) (PubkeyHex, error) {
+	duty, err := b.getProposerDuty(requestedSlot)
+	if err != nil {
+		return PubkeyHex(""), err
+	}
+	return duty.Pubkey, nil
+}
+
+// getProposerIndexForSlot returns the validator index of the slot's proposer, from the same duties as the pubkey.
+func (b *BeaconClient) getProposerIndexForSlot(requestedSlot uint64) (uint64, error) {
+	duty, err := b.getProposerDuty(requestedSlot)
+	if err != nil {
+		return 0, err
+	}
+	return duty.ValidatorIndex, nil
+}
+
+func (b *BeaconClient) getProposerDuty(requestedSlot uint64) (proposerDuty, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -52,7 +124,7 @@ func (b *BeaconClient) getProposerForSlot(requestedSlot uint64) (PubkeyHex, erro
 	if requestedEpoch != b.currentEpoch {
 		slotProposerMap, err := fetchEpochProposersMap(b.endpoint, requestedEpoch)
 		if err != nil {
-			return PubkeyHex(""), err
+			return proposerDuty{}, err
 		}
 
 		b.currentEpoch = requestedEpoch
@@ -61,17 +133,111 @@ func (b *BeaconClient) getProposerForSlot(requestedSlot uint64) (PubkeyHex, erro
 
 	nextSlotProposer, found := b.slotProposerMap[requestedSlot]
 	if !found {
-		return PubkeyHex(""), errors.New("no validator for requested slot")
+		return proposerDuty{}, errors.New("no validator for requested slot")
 	}
 
 	return nextSlotProposer, nil
 }
 
-func fetchEpochProposersMap(endpoint string, epoch uint64) (map[uint64]PubkeyHex, error) {
+// GetGenesis returns the genesis time of the beacon chain in unix seconds.
+func (b *BeaconClient) GetGenesis(ctx context.Context) (uint64, error) {
+	b.genesisMu.Lock()
+	defer b.genesisMu.Unlock()
+
+	if b.genesisTime != 0 {
+		return b.genesisTime, nil
+	}
+
+	genesisResponse := &struct {
+		Data struct {
+			GenesisTime uint64 `json:"genesis_time,string"`
+		} `json:"data"`
+	}{}
+
+	err := fetchBeaconWithContext(ctx, b.endpoint+"/eth/v1/beacon/genesis", genesisResponse)
+	if err != nil {
+		return 0, err
+	}
+
+	if genesisResponse.Data.GenesisTime == 0 {
+		return 0, errors.New("genesis time not set")
+	}
+
+	b.genesisTime = genesisResponse.Data.GenesisTime
+	return b.genesisTime, nil
+}
+
+// GetGenesisForkVersion returns the genesis fork version of the beacon chain. It is not cached, to catch a beacon
+// node switched to another network.
+func (b *BeaconClient) GetGenesisForkVersion(ctx context.Context) (boostTypes.ForkVersion, error) {
+	genesisResponse := &struct {
+		Data struct {
+			GenesisForkVersion hexutil.Bytes `json:"genesis_fork_version"`
+		} `json:"data"`
+	}{}
+
+	err := fetchBeaconWithContext(ctx, b.endpoint+"/eth/v1/beacon/genesis", genesisResponse)
+	if err != nil {
+		return boostTypes.ForkVersion{}, err
+	}
+
+	var forkVersion boostTypes.ForkVersion
+	if len(genesisResponse.Data.GenesisForkVersion) != len(forkVersion) {
+		return boostTypes.ForkVersion{}, fmt.Errorf("invalid genesis fork version %s", genesisResponse.Data.GenesisForkVersion)
+	}
+	copy(forkVersion[:], genesisResponse.Data.GenesisForkVersion)
+	return forkVersion, nil
+}
+
+// GetSpec returns the slot duration of the beacon chain in seconds.
+func (b *BeaconClient) GetSpec(ctx context.Context) (uint64, error) {
+	b.genesisMu.Lock()
+	defer b.genesisMu.Unlock()
+
+	if b.secondsPerSlot != 0 {
+		return b.secondsPerSlot, nil
+	}
+
+	specResponse := &struct {
+		Data struct {
+			SecondsPerSlot uint64 `json:"SECONDS_PER_SLOT,string"`
+		} `json:"data"`
+	}{}
+
+	err := fetchBeaconWithContext(ctx, b.endpoint+"/eth/v1/config/spec", specResponse)
+	if err != nil {
+		return 0, err
+	}
+
+	if specResponse.Data.SecondsPerSlot == 0 {
+		return 0, errors.New("seconds per slot not set")
+	}
+
+	b.secondsPerSlot = specResponse.Data.SecondsPerSlot
+	return b.secondsPerSlot, nil
+}
+
+// GetRandao returns the head state's randao mix, the prevRandao of the block following the head.
+func (b *BeaconClient) GetRandao(ctx context.Context) (common.Hash, error) {
+	randaoResponse := &struct {
+		Data struct {
+			Randao common.Hash `json:"randao"`
+		} `json:"data"`
+	}{}
+
+	err := fetchBeaconWithContext(ctx, b.endpoint+"/eth/v1/beacon/states/head/randao", randaoResponse)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return randaoResponse.Data.Randao, nil
+}
+
+func fetchEpochProposersMap(endpoint string, epoch uint64) (map[uint64]proposerDuty, error) {
 	proposerDutiesResponse := &struct {
 		Data []struct {
-			PubkeyHex string `json:"pubkey"`
-			Slot      string `json:"slot"`
+			PubkeyHex      string `json:"pubkey"`
+			ValidatorIndex string `json:"validator_index"`
+			Slot           string `json:"slot"`
 		} `json:"data"`
 	}{}
 
@@ -80,20 +246,29 @@ func fetchEpochProposersMap(endpoint string, epoch uint64) (map[uint64]PubkeyHex
 		return nil, err
 	}
 
-	proposersMap := make(map[uint64]PubkeyHex)
-	for _, proposerDuty := range proposerDutiesResponse.Data {
-		slot, err := strconv.Atoi(proposerDuty.Slot)
+	proposersMap := make(map[uint64]proposerDuty)
+	for _, duty := range proposerDutiesResponse.Data {
+		slot, err := strconv.Atoi(duty.Slot)
+		if err != nil {
+			log.Error("could not parse slot", "slot", duty.Slot, "err", err)
+			continue
+		}
+		validatorIndex, err := strconv.ParseUint(duty.ValidatorIndex, 10, 64)
 		if err != nil {
-			log.Error("could not parse slot", "Slot", proposerDuty.Slot, "err", err)
+			log.Error("could not parse validator index", "slot", duty.Slot, "validator_index", duty.ValidatorIndex, "err", err)
 			continue
 		}
-		proposersMap[uint64(slot)] = PubkeyHex(proposerDuty.PubkeyHex)
+		proposersMap[uint64(slot)] = proposerDuty{Pubkey: PubkeyHex(duty.PubkeyHex), ValidatorIndex: validatorIndex}
 	}
 	return proposersMap, nil
 }
 
 func fetchBeacon(url string, dst any) error {
-	req, err := http.NewRequest("GET", url, nil)
+	return fetchBeaconWithContext(context.Background(), url, dst)
+}
+
+func fetchBeaconWithContext(ctx context.Context, url string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		log.Error("invalid request", "url", url, "err", err)
 		return err
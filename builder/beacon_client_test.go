@@ -1,11 +1,14 @@
 package builder
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
 )
@@ -17,6 +20,9 @@ type mockBeaconNode struct {
 	forkResp       map[int][]byte
 	headersCode    int
 	headersResp    []byte
+	genesisResp    []byte
+	specResp       []byte
+	randaoResp     []byte
 }
 
 func newMockBeaconNode() *mockBeaconNode {
@@ -55,6 +61,21 @@ func newMockBeaconNode() *mockBeaconNode {
 		w.Write(resp)
 	})
 
+	r.HandleFunc("/eth/v1/beacon/genesis", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(mbn.genesisResp)
+	})
+
+	r.HandleFunc("/eth/v1/config/spec", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(mbn.specResp)
+	})
+
+	r.HandleFunc("/eth/v1/beacon/states/head/randao", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(mbn.randaoResp)
+	})
+
 	r.HandleFunc("/eth/v1/beacon/headers", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(mbn.headersCode)
@@ -125,8 +146,9 @@ func TestFetchEpochProposersMap(t *testing.T) {
 	proposersMap, err := fetchEpochProposersMap(mbn.srv.URL, 10)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(proposersMap))
-	require.Equal(t, PubkeyHex("0x93247f2209abcacf57b75a51dafae777f9dd38bc7053d1af526f220a7489a6d3a2753e5f3e8b1cfe39b56f43611df74a"), proposersMap[1])
-	require.Equal(t, PubkeyHex("0x93247f2209abcacf57b75a51dafae777f9dd38bc7053d1af526f220a7489a6d3a2753e5f3e8b1cfe39b56f43611df74b"), proposersMap[2])
+	require.Equal(t, PubkeyHex("0x93247f2209abcacf57b75a51dafae777f9dd38bc7053d1af526f220a7489a6d3a2753e5f3e8b1cfe39b56f43611df74a"), proposersMap[1].Pubkey)
+	require.Equal(t, PubkeyHex("0x93247f2209abcacf57b75a51dafae777f9dd38bc7053d1af526f220a7489a6d3a2753e5f3e8b1cfe39b56f43611df74b"), proposersMap[2].Pubkey)
+	require.Equal(t, uint64(2), proposersMap[2].ValidatorIndex)
 }
 
 func TestGetProposerForSlot(t *testing.T) {
@@ -201,8 +223,74 @@ func TestGetProposerForSlot(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, PubkeyHex("0x93247f2209abcacf57b75a51dafae777f9dd38bc7053d1af526f220a7489a6d3a2753e5f3e8b1cfe39b56f43611df74d"), pubkeyHex)
 
+	validatorIndex, err := bc.getProposerIndexForSlot(64)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), validatorIndex)
+
 	// Check proposers map error is routed out
 	mbn.headersResp = []byte(`{ "data": [ { "header": { "message": { "slot": "65", "proposer_index": "1" } } } ] }`)
 	_, err = bc.getProposerForSlot(65)
 	require.EqualError(t, err, "no validator for requested slot")
+	_, err = bc.getProposerIndexForSlot(65)
+	require.EqualError(t, err, "no validator for requested slot")
+}
+
+func TestGetGenesisAndSpec(t *testing.T) {
+	mbn := newMockBeaconNode()
+	defer mbn.srv.Close()
+
+	mbn.genesisResp = []byte(`{ "data": { "genesis_time": "1606824023", "genesis_validators_root": "0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95", "genesis_fork_version": "0x00000000" } }`)
+	mbn.specResp = []byte(`{ "data": { "SECONDS_PER_SLOT": "6", "SLOTS_PER_EPOCH": "32" } }`)
+
+	bc := NewBeaconClient(mbn.srv.URL)
+	genesisTime, err := bc.GetGenesis(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1606824023), genesisTime)
+
+	secondsPerSlot, err := bc.GetSpec(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), secondsPerSlot)
+
+	// Values are cached
+	mbn.genesisResp = []byte(`{ "data": { "genesis_time": "1" } }`)
+	mbn.specResp = []byte(`{ "data": { "SECONDS_PER_SLOT": "12" } }`)
+
+	genesisTime, err = bc.GetGenesis(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1606824023), genesisTime)
+
+	secondsPerSlot, err = bc.GetSpec(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), secondsPerSlot)
+
+	mbn.genesisResp = []byte(`{ "data": {} }`)
+	_, err = NewBeaconClient(mbn.srv.URL).GetGenesis(context.Background())
+	require.Error(t, err)
+}
+
+func TestGetGenesisForkVersion(t *testing.T) {
+	mbn := newMockBeaconNode()
+	defer mbn.srv.Close()
+
+	mbn.genesisResp = []byte(`{ "data": { "genesis_time": "1606824023", "genesis_fork_version": "0x01017000" } }`)
+	bc := NewBeaconClient(mbn.srv.URL)
+	forkVersion, err := bc.GetGenesisForkVersion(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, boostTypes.ForkVersion{0x01, 0x01, 0x70, 0x00}, forkVersion)
+
+	// Not cached
+	mbn.genesisResp = []byte(`{ "data": { "genesis_time": "1606824023", "genesis_fork_version": "0x0102" } }`)
+	_, err = bc.GetGenesisForkVersion(context.Background())
+	require.ErrorContains(t, err, "invalid genesis fork version")
+}
+
+func TestGetRandao(t *testing.T) {
+	mbn := newMockBeaconNode()
+	defer mbn.srv.Close()
+
+	mbn.randaoResp = []byte(`{ "data": { "randao": "0x0102030000000000000000000000000000000000000000000000000000000000" } }`)
+
+	randao, err := NewBeaconClient(mbn.srv.URL).GetRandao(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{0x01, 0x02, 0x03}, randao)
 }
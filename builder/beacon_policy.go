@@ -0,0 +1,44 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var ErrBeaconUnavailable = errors.New("beacon node unavailable")
+
+// BeaconPolicy selects what the builder does when a validation depending on the beacon node can't be done, as the
+// beacon node is unreachable or doesn't have the data yet.
+type BeaconPolicy string
+
+const (
+	// BeaconLenient logs the skipped validation and builds with the relay's data
+	BeaconLenient BeaconPolicy = "lenient"
+	// BeaconStrict drops the slot
+	BeaconStrict BeaconPolicy = "strict"
+)
+
+func ParseBeaconPolicy(policy string) (BeaconPolicy, error) {
+	switch BeaconPolicy(policy) {
+	case "":
+		return BeaconLenient, nil
+	case BeaconLenient, BeaconStrict:
+		return BeaconPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unknown beacon policy %q", policy)
+	}
+}
+
+// beaconValidationFailed applies the beacon policy to the validation that could not be done because of err,
+// returning the error to drop the slot with if strict.
+func (b *Builder) beaconValidationFailed(validation string, slot uint64, err error) error {
+	beaconDegradedCounter.Inc(1)
+	if b.beaconPolicy == BeaconStrict {
+		log.Info("dropping slot, the beacon node validation failed", "validation", validation, "err", err, "slot", slot)
+		return fmt.Errorf("%w: %s: %v", ErrBeaconUnavailable, validation, err)
+	}
+	log.Warn("skipping the beacon node validation, building with the relay's data", "validation", validation, "err", err, "slot", slot)
+	return nil
+}
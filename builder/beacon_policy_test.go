@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeaconPolicy(t *testing.T) {
+	// Lenient by default, the slot is built with the relay's data
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	builder.beaconClient.(*testBeaconClient).proposerErr = errors.New("connection refused")
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.NotNil(t, testRelay.submittedMsg)
+
+	builder, testRelay = newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{BeaconPolicy: BeaconStrict})
+	builder.beaconClient.(*testBeaconClient).proposerErr = errors.New("connection refused")
+	require.ErrorIs(t, builder.OnPayloadAttribute(newTestAttributes(25)), ErrBeaconUnavailable)
+	require.Nil(t, testRelay.submittedMsg)
+
+	// The beacon node being available, strict builds
+	builder.beaconClient.(*testBeaconClient).proposerErr = nil
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(26)))
+	require.NotNil(t, testRelay.submittedMsg)
+}
+
+func TestParseBeaconPolicy(t *testing.T) {
+	policy, err := ParseBeaconPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, BeaconLenient, policy)
+
+	policy, err = ParseBeaconPolicy("strict")
+	require.NoError(t, err)
+	require.Equal(t, BeaconStrict, policy)
+
+	_, err = ParseBeaconPolicy("ignore")
+	require.Error(t, err)
+}
+
+func TestBeaconPolicyPrevRandao(t *testing.T) {
+	head := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(9), MixDigest: common.Hash{0x04}})
+
+	// Lenient falls back to the head's prevRandao
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	builder.beaconClient.(*testBeaconClient).randaoErr = errors.New("connection refused")
+	prevRandao, err := builder.prevRandao(context.Background(), 25, head)
+	require.NoError(t, err)
+	require.Equal(t, head.MixDigest(), prevRandao)
+
+	builder, _ = newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{BeaconPolicy: BeaconStrict})
+	builder.beaconClient.(*testBeaconClient).randaoErr = errors.New("connection refused")
+	_, err = builder.prevRandao(context.Background(), 25, head)
+	require.ErrorIs(t, err, ErrBeaconUnavailable)
+}
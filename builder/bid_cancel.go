@@ -0,0 +1,9 @@
+package builder
+
+// BidCanceller is implemented by relays which accept cancellable submissions. The relays take the builder's latest
+// submission for a slot as its bid, even when it is lower than the earlier ones, which lets the builder lower or
+// replace its bid during the slot. Without cancellations, the relays keep the builder's highest submission.
+type BidCanceller interface {
+	// CancelsBids reports whether the submissions to the relay are cancellable
+	CancelsBids() bool
+}
@@ -0,0 +1,82 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+var (
+	ErrBidValueExceedsProfit = errors.New("bid value exceeds block profit")
+	// ErrBlockValueOverflow is returned for bid values which don't fit the relay API's uint256
+	ErrBlockValueOverflow = errors.New("block value overflows uint256")
+)
+
+// BidValueStrategy computes the bid value advertised to the relay from the block profit.
+// The builder rejects values above the profit, since the block could not pay them.
+type BidValueStrategy interface {
+	BidValue(profit *big.Int) *big.Int
+}
+
+// FullProfitBidValue bids the whole block profit.
+type FullProfitBidValue struct{}
+
+func (FullProfitBidValue) BidValue(profit *big.Int) *big.Int {
+	return new(big.Int).Set(profit)
+}
+
+// ReserveBidValue bids the block profit minus a fixed reserve kept by the builder, but not less than zero.
+type ReserveBidValue struct {
+	Reserve *big.Int
+}
+
+func (s ReserveBidValue) BidValue(profit *big.Int) *big.Int {
+	value := new(big.Int).Set(profit)
+	if s.Reserve != nil {
+		value.Sub(value, s.Reserve)
+	}
+	if value.Sign() < 0 {
+		value.SetInt64(0)
+	}
+	return value
+}
+
+// computeBidValue applies the strategy and makes sure the block still pays at least the advertised value.
+func computeBidValue(strategy BidValueStrategy, profit *big.Int) (*big.Int, error) {
+	if profit == nil {
+		return nil, errors.New("block profit not set")
+	}
+
+	if strategy == nil {
+		strategy = FullProfitBidValue{}
+	}
+
+	value := strategy.BidValue(profit)
+	if value == nil || value.Sign() < 0 {
+		return nil, errors.New("invalid bid value")
+	}
+	if value.Cmp(profit) > 0 {
+		return nil, ErrBidValueExceedsProfit
+	}
+
+	return value, nil
+}
+
+// bidValueToU256 converts the bid value to the relay API's uint256, rejecting negative values and values
+// above 2^256-1.
+func bidValueToU256(value *big.Int) (*boostTypes.U256Str, error) {
+	if value.Sign() < 0 {
+		return nil, fmt.Errorf("negative block value %s", value)
+	}
+	if value.BitLen() > 256 {
+		return nil, fmt.Errorf("%w: value of %d bits", ErrBlockValueOverflow, value.BitLen())
+	}
+
+	u256 := new(boostTypes.U256Str)
+	if err := u256.FromBig(value); err != nil {
+		return nil, err
+	}
+	return u256, nil
+}
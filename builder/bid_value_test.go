@@ -0,0 +1,64 @@
+package builder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type overbiddingBidValue struct{}
+
+func (overbiddingBidValue) BidValue(profit *big.Int) *big.Int {
+	return new(big.Int).Add(profit, big.NewInt(1))
+}
+
+func TestComputeBidValue(t *testing.T) {
+	strategies := []BidValueStrategy{
+		nil,
+		FullProfitBidValue{},
+		ReserveBidValue{},
+		ReserveBidValue{Reserve: big.NewInt(0)},
+		ReserveBidValue{Reserve: big.NewInt(7)},
+		ReserveBidValue{Reserve: big.NewInt(1_000_000)},
+	}
+	profits := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(7), big.NewInt(10), big.NewInt(1_000_000_000)}
+
+	for _, strategy := range strategies {
+		for _, profit := range profits {
+			value, err := computeBidValue(strategy, profit)
+			require.NoError(t, err)
+			require.True(t, value.Cmp(profit) <= 0, "bid value %s exceeds profit %s", value, profit)
+			require.True(t, value.Sign() >= 0)
+		}
+	}
+
+	value, err := computeBidValue(ReserveBidValue{Reserve: big.NewInt(7)}, big.NewInt(10))
+	require.NoError(t, err)
+	require.Equal(t, int64(3), value.Int64())
+
+	value, err = computeBidValue(ReserveBidValue{Reserve: big.NewInt(7)}, big.NewInt(5))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), value.Int64())
+
+	// Strategy output is not trusted
+	_, err = computeBidValue(overbiddingBidValue{}, big.NewInt(10))
+	require.ErrorIs(t, err, ErrBidValueExceedsProfit)
+
+	_, err = computeBidValue(nil, nil)
+	require.Error(t, err)
+}
+
+func TestBidValueToU256(t *testing.T) {
+	maxU256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	value, err := bidValueToU256(maxU256)
+	require.NoError(t, err)
+	require.Equal(t, maxU256, value.BigInt())
+
+	_, err = bidValueToU256(new(big.Int).Add(maxU256, big.NewInt(1)))
+	require.ErrorIs(t, err, ErrBlockValueOverflow)
+	require.ErrorContains(t, err, "257 bits")
+
+	_, err = bidValueToU256(big.NewInt(-1))
+	require.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// defaultMaxFailedDumps is the number of failed submissions kept without recent slots to keep, the oldest are removed
+const defaultMaxFailedDumps = 1024
+
+// BlockDumper writes the submissions and their blocks to a directory, as artifacts of rejected submissions to share
+// with relay operators. Each submission is written as <slot>_<block hash>.json with the BuilderSubmitBlockRequest
+// and <slot>_<block hash>.rlp with the RLP encoded block.
+type BlockDumper struct {
+	dir string
+	// slots is the number of most recent slots whose submissions are all kept, only the failed submissions are
+	// dumped if zero, up to maxFailed
+	slots     uint64
+	maxFailed int
+
+	mu         sync.Mutex
+	latestSlot uint64
+}
+
+func NewBlockDumper(dir string, slots uint64) (*BlockDumper, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &BlockDumper{dir: dir, slots: slots, maxFailed: defaultMaxFailedDumps}, nil
+}
+
+// Dump writes the submission if it failed or the recent slots are kept, pruning the submissions of older slots, or
+// the oldest failed submissions past the maximum kept.
+func (d *BlockDumper) Dump(req *boostTypes.BuilderSubmitBlockRequest, block *types.Block, failed bool) error {
+	if !failed && d.slots == 0 {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	slot := req.Message.Slot
+	name := fmt.Sprintf("%d_%s", slot, req.Message.BlockHash.String())
+
+	reqJSON, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(d.dir, name+".json"), reqJSON, 0o644); err != nil {
+		return err
+	}
+	blockRLP, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(d.dir, name+".rlp"), blockRLP, 0o644); err != nil {
+		return err
+	}
+
+	if d.slots == 0 {
+		d.pruneFailed()
+	} else if slot > d.latestSlot {
+		d.latestSlot = slot
+		d.prune()
+	}
+	return nil
+}
+
+// dumpedSlot returns the slot of the dump file's name.
+func dumpedSlot(name string) (uint64, bool) {
+	prefix, _, found := strings.Cut(name, "_")
+	if !found {
+		return 0, false
+	}
+	slot, err := strconv.ParseUint(prefix, 10, 64)
+	return slot, err == nil
+}
+
+// pruneFailed removes the oldest failed submissions, by slot, past the maximum kept.
+func (d *BlockDumper) pruneFailed() {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		log.Warn("could not list dumped blocks", "err", err, "dir", d.dir)
+		return
+	}
+	type dump struct {
+		slot uint64
+		name string
+	}
+	var dumps []dump
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if slot, ok := dumpedSlot(name); ok {
+			dumps = append(dumps, dump{slot, name})
+		}
+	}
+	if len(dumps) <= d.maxFailed {
+		return
+	}
+	sort.Slice(dumps, func(i, j int) bool {
+		if dumps[i].slot != dumps[j].slot {
+			return dumps[i].slot < dumps[j].slot
+		}
+		return dumps[i].name < dumps[j].name
+	})
+	for _, dump := range dumps[:len(dumps)-d.maxFailed] {
+		for _, file := range []string{dump.name + ".json", dump.name + ".rlp"} {
+			if err := os.Remove(filepath.Join(d.dir, file)); err != nil && !os.IsNotExist(err) {
+				log.Warn("could not remove dumped block", "err", err, "file", file)
+			}
+		}
+	}
+}
+
+// prune removes the dumps of the slots before the kept recent slots.
+func (d *BlockDumper) prune() {
+	if d.latestSlot < d.slots {
+		return
+	}
+	oldestKept := d.latestSlot - d.slots + 1
+
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		log.Warn("could not list dumped blocks", "err", err, "dir", d.dir)
+		return
+	}
+	for _, entry := range entries {
+		slot, ok := dumpedSlot(entry.Name())
+		if !ok || slot >= oldestKept {
+			continue
+		}
+		if err := os.Remove(filepath.Join(d.dir, entry.Name())); err != nil {
+			log.Warn("could not remove dumped block", "err", err, "file", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func dumpedFiles(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestBlockDumper(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "dumps")
+	dumper, err := NewBlockDumper(dir, 0)
+	require.NoError(t, err)
+
+	req := newTestSubmitBlockRequest(t, nil)
+	block := newTestBlock(10)
+
+	// Only the failed submissions are dumped without recent slots to keep
+	require.NoError(t, dumper.Dump(req, block, false))
+	require.Empty(t, dumpedFiles(t, dir))
+	require.NoError(t, dumper.Dump(req, block, true))
+	name := "25_" + req.Message.BlockHash.String()
+	require.Equal(t, []string{name + ".json", name + ".rlp"}, dumpedFiles(t, dir))
+
+	reqJSON, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	require.NoError(t, err)
+	var dumpedReq boostTypes.BuilderSubmitBlockRequest
+	require.NoError(t, json.Unmarshal(reqJSON, &dumpedReq))
+	require.Equal(t, req, &dumpedReq)
+
+	blockRLP, err := os.ReadFile(filepath.Join(dir, name+".rlp"))
+	require.NoError(t, err)
+	var dumpedBlock types.Block
+	require.NoError(t, rlp.DecodeBytes(blockRLP, &dumpedBlock))
+	require.Equal(t, block.Hash(), dumpedBlock.Hash())
+
+	// The oldest failed submissions are removed past the maximum kept
+	dumper.maxFailed = 2
+	for _, slot := range []uint64{27, 26, 28} {
+		req.Message.Slot = slot
+		require.NoError(t, dumper.Dump(req, block, true))
+	}
+	require.Len(t, dumpedFiles(t, dir), 4)
+	require.NoFileExists(t, filepath.Join(dir, name+".json"))
+	require.NoFileExists(t, filepath.Join(dir, "26_"+req.Message.BlockHash.String()+".rlp"))
+	require.FileExists(t, filepath.Join(dir, "27_"+req.Message.BlockHash.String()+".json"))
+	require.FileExists(t, filepath.Join(dir, "28_"+req.Message.BlockHash.String()+".rlp"))
+	req.Message.Slot = 25
+
+	// All submissions of the recent slots are kept
+	dir = t.TempDir()
+	dumper, err = NewBlockDumper(dir, 2)
+	require.NoError(t, err)
+	for _, slot := range []uint64{25, 26, 27} {
+		req.Message.Slot = slot
+		require.NoError(t, dumper.Dump(req, block, false))
+	}
+	require.Len(t, dumpedFiles(t, dir), 4)
+	require.NoFileExists(t, filepath.Join(dir, name+".json"))
+	require.FileExists(t, filepath.Join(dir, "26_"+req.Message.BlockHash.String()+".json"))
+}
+
+func TestOnSealedBlockDumpsFailedSubmission(t *testing.T) {
+	dumper, err := NewBlockDumper(t.TempDir(), 0)
+	require.NoError(t, err)
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{BlockDumper: dumper})
+	executableData := newTestExecutableData()
+
+	require.NoError(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25))
+	require.Empty(t, dumpedFiles(t, dumper.dir))
+
+	testRelay.submitErr = errors.New("rejected")
+	require.Error(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25))
+	require.Len(t, dumpedFiles(t, dumper.dir), 2)
+}
@@ -0,0 +1,38 @@
+package builder
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrBuildLimitReached = errors.New("concurrent builds limit reached until the slot deadline")
+
+// acquireBuildSlot waits until fewer than MaxConcurrentBuilds builds are running, until the deadline.
+func (b *Builder) acquireBuildSlot(deadline time.Time) error {
+	if b.buildSlots == nil {
+		return nil
+	}
+
+	select {
+	case b.buildSlots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case b.buildSlots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		droppedBuildsCounter.Inc(1)
+		return ErrBuildLimitReached
+	}
+}
+
+func (b *Builder) releaseBuildSlot() {
+	if b.buildSlots != nil {
+		<-b.buildSlots
+	}
+}
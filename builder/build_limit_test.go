@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxConcurrentBuilds(t *testing.T) {
+	testEthService := &slowEthereumService{
+		testEthereumService: *newTestEthereumService(),
+		buildDelay:          300 * time.Millisecond,
+	}
+	builder, _ := newTestBuilderWithOptions(t, testEthService, BuilderOptions{AllowOverlappingBuilds: true, MaxConcurrentBuilds: 2})
+
+	var wg sync.WaitGroup
+	for slot := uint64(25); slot < 29; slot++ {
+		wg.Add(1)
+		go func(slot uint64) {
+			defer wg.Done()
+			_, _, _, _, err := builder.buildBlockWithRetry(testEthService, newTestAttributes(slot), time.Now().Add(time.Second))
+			require.NoError(t, err)
+		}(slot)
+	}
+	wg.Wait()
+
+	testEthService.mu.Lock()
+	defer testEthService.mu.Unlock()
+	require.Equal(t, 2, testEthService.maxBuilds)
+	require.Equal(t, 4, testEthService.totalBuilt)
+}
+
+func TestAcquireBuildSlot(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{MaxConcurrentBuilds: 1})
+
+	require.NoError(t, builder.acquireBuildSlot(time.Now().Add(time.Second)))
+
+	// Dropped at the deadline
+	start := time.Now()
+	require.ErrorIs(t, builder.acquireBuildSlot(time.Now().Add(100*time.Millisecond)), ErrBuildLimitReached)
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// Acquired once released
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		builder.releaseBuildSlot()
+	}()
+	require.NoError(t, builder.acquireBuildSlot(time.Now().Add(time.Second)))
+	builder.releaseBuildSlot()
+
+	// Unbounded
+	builder, _ = newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	for i := 0; i < 10; i++ {
+		require.NoError(t, builder.acquireBuildSlot(time.Now()))
+	}
+}
@@ -1,10 +1,16 @@
 package builder
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math/rand"
 	_ "os"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/beacon"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -14,71 +20,543 @@ import (
 	boostTypes "github.com/flashbots/go-boost-utils/types"
 )
 
+const (
+	defaultSecondsPerSlot   = 12
+	beaconStartupTimeout    = 5 * time.Second
+	relayConstraintsTimeout = 500 * time.Millisecond
+	noPayloadRetryDelay     = 100 * time.Millisecond
+)
+
+var (
+	ErrEmptyTransaction = errors.New("empty transaction in execution payload")
+	// ErrZeroPayloadHash is returned for the execution payloads with a zero state root, receipts root or block hash,
+	// which even an empty block doesn't have
+	ErrZeroPayloadHash = errors.New("zero hash in execution payload")
+	// ErrNoPayloadFromEL is returned when the EL built no block for the payload attributes
+	ErrNoPayloadFromEL = errors.New("did not receive the payload")
+	// ErrStaleRegistration is returned when the slot's validator registration is older than MaxRegistrationAge
+	ErrStaleRegistration = errors.New("stale validator registration")
+	// ErrSimulationFailed is returned when SimulateBlocks is set and the built block failed the local simulation
+	ErrSimulationFailed = errors.New("block simulation failed")
+	// ErrRegistrationNotFound is returned by the relays without a registration for the validator
+	ErrRegistrationNotFound = errors.New("validator registration not found")
+	// ErrParentMismatch is returned when the EL built the block on another parent than the attributes' head
+	ErrParentMismatch = errors.New("block built on another parent than the head")
+	// ErrBlockNumberMismatch is returned when the EL built a block whose number is not the parent's number plus one
+	ErrBlockNumberMismatch = errors.New("block number is not the parent's number plus one")
+	// ErrBloomMismatch is returned by the block simulation when the block's logs bloom does not match its receipts
+	ErrBloomMismatch = errors.New("logs bloom does not match the receipts")
+	// ErrNoActiveBuild is returned by TriggerRebuild when no blocks are being built for the slot
+	ErrNoActiveBuild = errors.New("no active build for the slot")
+	// ErrInvalidSignature is returned when the submission's signature does not verify against the builder pubkey
+	ErrInvalidSignature = errors.New("invalid submission signature")
+)
+
 type PubkeyHex string
 
 type ValidatorData struct {
 	Pubkey       PubkeyHex
 	FeeRecipient boostTypes.Address `json:"feeRecipient"`
 	GasLimit     uint64             `json:"gasLimit"`
-	Timestamp    uint64             `json:"timestamp"`
+	// Timestamp is the unix time in seconds of the validator's signed registration, zero if unknown
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// isStale returns true if the registration is older than maxAge, registrations of unknown age are never stale.
+func (vd ValidatorData) isStale(now time.Time, maxAge time.Duration) bool {
+	if maxAge <= 0 || vd.Timestamp == 0 {
+		return false
+	}
+	return now.Sub(time.Unix(int64(vd.Timestamp), 0)) > maxAge
+}
+
+// mostRecentRegistration returns the registration with the latest timestamp, the first one on ties.
+func mostRecentRegistration(registrations ...ValidatorData) ValidatorData {
+	var latest ValidatorData
+	for i, vd := range registrations {
+		if i == 0 || vd.Timestamp > latest.Timestamp {
+			latest = vd
+		}
+	}
+	return latest
 }
 
 type IBeaconClient interface {
 	isValidator(pubkey PubkeyHex) bool
 	getProposerForSlot(requestedSlot uint64) (PubkeyHex, error)
+	getProposerIndexForSlot(requestedSlot uint64) (uint64, error)
+	GetGenesis(ctx context.Context) (genesisTime uint64, err error)
+	GetSpec(ctx context.Context) (secondsPerSlot uint64, err error)
+	GetRandao(ctx context.Context) (common.Hash, error)
 }
 
 type IRelay interface {
-	SubmitBlock(msg *boostTypes.BuilderSubmitBlockRequest) error
+	SubmitBlock(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) error
 	GetValidatorForSlot(nextSlot uint64) (ValidatorData, error)
+	GetConstraints(ctx context.Context) (RelayConstraints, error)
+	// GetRegisteredGasLimit returns the gas limit of the validator's standing registration, independent of the slot.
+	// Returns ErrRegistrationNotFound if the validator is not registered
+	GetRegisteredGasLimit(ctx context.Context, pubkey boostTypes.PublicKey) (uint64, error)
 }
 
 type IBuilder interface {
 	OnPayloadAttribute(attrs *BuilderPayloadAttributes) error
+	GetPayload(blindedBlock *boostTypes.SignedBlindedBeaconBlock) (*boostTypes.ExecutionPayload, error)
+}
+
+// BuilderOptions are the optional settings of the builder, the zero value keeps the defaults.
+type BuilderOptions struct {
+	// AllowOverlappingBuilds lets a new slot's build start while the previous slot's build is still in flight
+	AllowOverlappingBuilds bool
+	// ResubmitWorkers is the number of workers running the resubmissions of the slots' blocks, 4 if zero
+	ResubmitWorkers int
+	// MaxConcurrentBuilds bounds the number of blocks built at once across slots, builds over the limit wait
+	// until the slot deadline and are dropped after. Unbounded if zero
+	MaxConcurrentBuilds int
+	// BidValueStrategy computes the submitted bid value from the block profit, the full profit is bid if not set
+	BidValueStrategy BidValueStrategy
+	// AttributesRecorder records every received payload attribute if set
+	AttributesRecorder *AttributesRecorder
+	// BlockDumper writes the submissions of the recent slots or the failed submissions to disk if set
+	BlockDumper *BlockDumper
+	// Webhook is posted the outcome of every submission if set
+	Webhook *WebhookNotifier
+	// AllRelaysRejectedPause pauses the builder for the duration after all relays rejected a submission, so that the
+	// builder doesn't loop on rejections. Not paused if zero
+	AllRelaysRejectedPause time.Duration
+	// AllRelaysRejectedWebhook is posted the submissions all relays rejected if set
+	AllRelaysRejectedWebhook *WebhookNotifier
+	// MaxSubmitBytesPerSlot bounds the serialized size of the submissions of a slot summed across the relays, the
+	// submissions over the budget are skipped. The relays are submitted to in their configured order, so the earlier
+	// relays are prioritized within the budget. Unbounded if zero
+	MaxSubmitBytesPerSlot uint64
+	// ArchiveSink archives every submission in the background if set, whether the relays accepted it or not
+	ArchiveSink ArchiveSink
+	// NotSyncedPolicy selects the behaviour when the EL is not synced, the slot is dropped by default
+	NotSyncedPolicy NotSyncedPolicy
+	// NotSyncedMaxWait bounds the wait for the EL to sync with NotSyncedWait
+	NotSyncedMaxWait time.Duration
+	// FallbackEthService is the EL used with NotSyncedFallback
+	FallbackEthService IEthereumService
+	// SingleShot builds and submits a single block per slot instead of resubmitting until the slot deadline
+	SingleShot bool
+	// CircuitBreakerThreshold is the number of consecutive failed submissions opening the relay circuit breaker, the
+	// breaker is disabled if not set
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is the time the relay circuit breaker stays open before the relay is retried, 2s if not
+	// set
+	CircuitBreakerCooldown time.Duration
+	// RelayLatencySLA disables the relay once its p99 submission latency exceeds the SLA for RelayLatencySLAWindow
+	// (a minute by default), probing it once per slot until it recovers. Disabled if zero
+	RelayLatencySLA       time.Duration
+	RelayLatencySLAWindow time.Duration
+	// Reconcile checks whether the won blocks landed on-chain, using the relay's delivered payloads and the unblinded blocks
+	Reconcile bool
+	// SelfDrivenBuilds builds on the EL head for the next slot if no payload attributes were received
+	// SelfDrivenBuildDelay into the current slot, requires the genesis time from the beacon node
+	SelfDrivenBuilds     bool
+	SelfDrivenBuildDelay time.Duration
+	// GasLimitSmoothing moves the gas limit from the parent's by this fraction of the gap to the validator's gas limit
+	// every block, within the per-block adjustment cap. The validator's gas limit is used directly if not within (0, 1)
+	GasLimitSmoothing float64
+	// MissedSlotsThreshold is the number of consecutive slots without payload attributes after which the missed
+	// slots are logged and counted, requires the genesis time from the beacon node. Disabled if zero
+	MissedSlotsThreshold uint64
+	// SlotMismatchPolicy selects the behaviour when the attributes' slot differs from the slot of their timestamp by
+	// more than SlotMismatchTolerance slots, the mismatch is logged by default. Requires the genesis time from the
+	// beacon node
+	SlotMismatchPolicy    SlotMismatchPolicy
+	SlotMismatchTolerance uint64
+	// TimestampFlexibility is the number of seconds past the slot's start the relays accept as the block's
+	// timestamp, the blocks are then built at the latest of them. Requires the genesis time from the beacon node and
+	// DevNetwork, as the consensus rules require the slot's start as the timestamp. Zero keeps the attributes'
+	// timestamp
+	TimestampFlexibility uint64
+	// MaxSlotsAhead is the number of slots past the current slot the attributes may be for, attributes of later
+	// slots are dropped with ErrSlotTooFarAhead. Requires the genesis time from the beacon node. Disabled if zero
+	MaxSlotsAhead uint64
+	// InitialSubmissionDelay delays the slot's first build, letting transactions accumulate on networks with sparse
+	// mempools. The delayed build runs in the background and is dropped if replaced by the attributes of another slot.
+	// At most half the slot duration, zero submits right away
+	InitialSubmissionDelay time.Duration
+	// SpeculativeBuilds builds a block for the next slot on the current head once the slot's first block is submitted,
+	// submitted right away if the next slot's attributes are those it was built for, as when the slot is missed
+	SpeculativeBuilds bool
+	// BeaconPolicy selects the behaviour when the proposer cross-check with the beacon node can't be done or the
+	// prevRandao of the builds without attributes can't be fetched, they are skipped with a warning by default
+	BeaconPolicy BeaconPolicy
+	// RandSource is the source of the builder's randomness, such as the submission IDs. Seeded from crypto/rand if
+	// nil, tests set a seeded source for reproducible runs
+	RandSource rand.Source
+	// MaintenanceWindows are the periods the builder is paused during, as by Pause
+	MaintenanceWindows []MaintenanceWindow
+	// Observer only polls the relays' top bids of the slots, see ObservedBids, without building nor submitting blocks
+	Observer bool
+	// MaxRegistrationAge drops the slots whose validator registration is older, disabled if zero
+	MaxRegistrationAge time.Duration
+	// SimulateBlocks re-executes every built block on the EL before submitting it, dropping the invalid blocks.
+	// Opt-in as it adds a block execution to each submission
+	SimulateBlocks bool
+	// RelaySecretKeys are the builder keys the submissions to some relays are signed with instead of the builder key,
+	// by relay name in Relays
+	RelaySecretKeys map[string]*bls.SecretKey
+	// RelayFees are the fees the relays deduct from the bid value by relay name in Relays, the minimum value of a
+	// relay applies to the bid value net of its fee. The submitted bid value is the block's proposer payment either
+	// way, which the relays check it against
+	RelayFees map[string]RelayFee
+	// FailoverRelays are sent the signed block of a failed submission, in order until one accepts it, without
+	// rebuilding it. They are not submitted to otherwise
+	FailoverRelays []IRelay
+	// RegistrationCheckInterval is the interval of the builder registration check with relays implementing
+	// BuilderRegistrar, the check is disabled if not set
+	RegistrationCheckInterval time.Duration
+	// MaxCandidateHeads is the number of heads the blocks are built on, the attributes' head and their candidate
+	// heads. Only the attributes' head is built on if below 2. The relays validate a submission's parent and index the
+	// bids by slot, parent and proposer, so the block of every head is submitted: the proposer gets the bid on the
+	// parent it asks for, and the bids on the other heads are never delivered. With cancellations, each head's latest
+	// block only replaces the bid on its own parent
+	MaxCandidateHeads int
+	// SigningDomainCheckInterval is the interval of the comparison of the builder signing domain with the domain of
+	// the genesis fork version served by the beacon client, if it implements GenesisForkVersionReporter. The check is
+	// disabled if not set
+	SigningDomainCheckInterval time.Duration
+	// LazyBuild builds the blocks of a slot after its first only when the relays' top bid looks beatable if set, the
+	// blocks are built at every resubmission otherwise
+	LazyBuild *LazyBuildPolicy
+	// PrevRandaoOverride replaces the prevRandao of the payload attributes and of the head state's randao mix if
+	// set, on the development networks only. The attributes with another non-zero prevRandao are refused
+	PrevRandaoOverride *common.Hash
+	// DevNetwork is set on the development networks without a real beacon chain, see IsDevNetwork. It is required
+	// by the test features breaking the consensus rules
+	DevNetwork bool
+	// CanarySubmissions allows SubmitCanary, whose canaries are real bids for the next slot
+	CanarySubmissions bool
 }
 
 type Builder struct {
+	// gasLimitCheckedSlot is accessed atomically, first for 64-bit alignment
+	gasLimitCheckedSlot uint64
+
 	beaconClient IBeaconClient
 	relay        IRelay
 	eth          IEthereumService
 	resubmitter  Resubmitter
+	singleShot   bool
+	payloads     *PayloadStore
+	breaker      *CircuitBreaker
+	latencySLA   *LatencySLA
+	retries      submissionRetryBuffer
+	inFlight     *inFlightSubmissions
+	toggles      relayToggles
+	statuses     relayStatuses
+	history      *submissionHistory
+	// slots cleans up the per-slot state of the past slots
+	slots slotLifecycle
+
+	buildSlots         chan struct{}
+	maxRegistrationAge time.Duration
+	simulateBlocks     bool
+	gasLimitSmoothing  float64
+
+	attrsMu         sync.Mutex
+	lastAttrsSlot   uint64
+	selfDrivenDelay time.Duration
+	// missedSlotsThreshold is the number of consecutive slots without attributes warned about, disabled if zero
+	missedSlotsThreshold uint64
+	// maxCandidateHeads is the number of heads built on, only the attributes' head if below 2
+	maxCandidateHeads int
+	// lazyBuild skips the builds not worth competing for if set
+	lazyBuild *LazyBuildPolicy
+	// prevRandaoOverride replaces the beacon derived prevRandao if set
+	prevRandaoOverride *common.Hash
+	// canarySubmissions allows SubmitCanary
+	canarySubmissions bool
+
+	slotMismatchPolicy    SlotMismatchPolicy
+	slotMismatchTolerance uint64
+	timestampFlexibility  uint64
+	maxSlotsAhead         uint64
+	bidValue              BidValueStrategy
+	recorder              *AttributesRecorder
+	dumper                *BlockDumper
+	webhook               *WebhookNotifier
+	archiver              *archiver
+	allRejectedPause      time.Duration
+	allRejectedWebhook    *WebhookNotifier
+	byteBudget            *slotByteBudget
+	submissions           submissionFeed
+
+	// initialSubmissionDelay is the delay before the slot's first build, at most half the slot duration
+	initialSubmissionDelay time.Duration
+
+	observer     bool
+	observations *bidObservations
+
+	speculativeBuilds bool
+	speculationMu     sync.Mutex
+	// speculation is the latest block built for the next slot, nil if there is none
+	speculation *speculativeBlock
+
+	beaconPolicy BeaconPolicy
+
+	pause pauseState
+	rand  *lockedRand
+
+	notSyncedPolicy  NotSyncedPolicy
+	notSyncedMaxWait time.Duration
+	fallbackEth      IEthereumService
 
 	builderSecretKey     *bls.SecretKey
 	builderPublicKey     boostTypes.PublicKey
 	builderSigningDomain boostTypes.Domain
+	// relayKeys are the keys of the relays not signed for with the builder key, by relay name
+	relayKeys map[string]builderKey
+	relayFees map[string]RelayFee
+	// failoverRelays are submitted to when the submission to the relay failed
+	failoverRelays []IRelay
+
+	// Unix seconds, zero until Start or if the beacon node could not provide it
+	genesisTime    uint64
+	secondsPerSlot uint64
+
+	// opts are the options Start sets up the slot timing and the background tasks with
+	opts BuilderOptions
 }
 
 func NewBuilder(sk *bls.SecretKey, bc IBeaconClient, relay IRelay, builderSigningDomain boostTypes.Domain, eth IEthereumService) *Builder {
-	pkBytes := bls.PublicKeyFromSecretKey(sk).Compress()
+	return NewBuilderWithOptions(sk, bc, relay, builderSigningDomain, eth, BuilderOptions{})
+}
+
+// NewBuilderWithOptions creates the builder with the options, without validating them as NewBuilderFromConfig does.
+func NewBuilderWithOptions(sk *bls.SecretKey, bc IBeaconClient, relay IRelay, builderSigningDomain boostTypes.Domain, eth IEthereumService, opts BuilderOptions) *Builder {
+	return newBuilder(Config{SecretKey: sk, BeaconClient: bc, Relay: relay, SigningDomain: builderSigningDomain, Eth: eth, BuilderOptions: opts})
+}
+
+func newBuilder(cfg Config) *Builder {
+	pkBytes := bls.PublicKeyFromSecretKey(cfg.SecretKey).Compress()
 	pk := boostTypes.PublicKey{}
 	pk.FromSlice(pkBytes)
 
-	return &Builder{
-		beaconClient:     bc,
-		relay:            relay,
-		eth:              eth,
-		resubmitter:      Resubmitter{},
-		builderSecretKey: sk,
+	notSyncedMaxWait := cfg.NotSyncedMaxWait
+	if notSyncedMaxWait == 0 {
+		notSyncedMaxWait = defaultNotSyncedMaxWait
+	}
+
+	relayKeys := make(map[string]builderKey, len(cfg.RelaySecretKeys))
+	for name, relaySk := range cfg.RelaySecretKeys {
+		relayKeys[name] = newBuilderKey(relaySk)
+	}
+
+	b := &Builder{
+		beaconClient: cfg.BeaconClient,
+		relay:        cfg.Relay,
+		eth:          cfg.Eth,
+		resubmitter:  Resubmitter{allowOverlap: cfg.AllowOverlappingBuilds, workers: cfg.ResubmitWorkers},
+		singleShot:   cfg.SingleShot,
+		payloads:     NewPayloadStore(),
+		history:      newSubmissionHistory(),
+		inFlight:     newInFlightSubmissions(),
+		bidValue:     cfg.BidValueStrategy,
+		recorder:     cfg.AttributesRecorder,
+		dumper:       cfg.BlockDumper,
+		webhook:      cfg.Webhook,
+
+		allRejectedPause:   cfg.AllRelaysRejectedPause,
+		allRejectedWebhook: cfg.AllRelaysRejectedWebhook,
+		byteBudget:         newSlotByteBudget(cfg.MaxSubmitBytesPerSlot),
+
+		maxRegistrationAge: cfg.MaxRegistrationAge,
+		simulateBlocks:     cfg.SimulateBlocks,
+		gasLimitSmoothing:  cfg.GasLimitSmoothing,
+
+		slotMismatchPolicy:    cfg.SlotMismatchPolicy,
+		slotMismatchTolerance: cfg.SlotMismatchTolerance,
+		timestampFlexibility:  cfg.TimestampFlexibility,
+		maxSlotsAhead:         cfg.MaxSlotsAhead,
+		canarySubmissions:     cfg.CanarySubmissions,
+
+		observer:     cfg.Observer,
+		observations: newBidObservations(),
+
+		speculativeBuilds: cfg.SpeculativeBuilds,
+		maxCandidateHeads: cfg.MaxCandidateHeads,
+		lazyBuild:         cfg.LazyBuild,
+
+		prevRandaoOverride: cfg.PrevRandaoOverride,
+
+		beaconPolicy: cfg.BeaconPolicy,
+		pause:        pauseState{windows: cfg.MaintenanceWindows},
+		rand:         newLockedRand(cfg.RandSource),
+
+		notSyncedPolicy:  cfg.NotSyncedPolicy,
+		notSyncedMaxWait: notSyncedMaxWait,
+		fallbackEth:      cfg.FallbackEthService,
+		builderSecretKey: cfg.SecretKey,
 		builderPublicKey: pk,
+		relayKeys:        relayKeys,
+		relayFees:        cfg.RelayFees,
+		failoverRelays:   cfg.FailoverRelays,
 
-		builderSigningDomain: builderSigningDomain,
+		builderSigningDomain: cfg.SigningDomain,
+
+		secondsPerSlot: defaultSecondsPerSlot,
+		opts:           cfg.BuilderOptions,
+	}
+	if cfg.ArchiveSink != nil {
+		b.archiver = newArchiver(cfg.ArchiveSink)
+	}
+	b.registerSlotCleanups()
+	b.breaker = NewCircuitBreaker(cfg.CircuitBreakerThreshold, cfg.CircuitBreakerCooldown, b.resubmitBuffered)
+	if cfg.MaxConcurrentBuilds > 0 {
+		b.buildSlots = make(chan struct{}, cfg.MaxConcurrentBuilds)
+	}
+	if cfg.TimestampFlexibility > 0 {
+		log.Warn("TIMESTAMP FLEXIBILITY ENABLED: the blocks are built past the slot's start, they are invalid on a network enforcing the consensus rules", "flexibility", cfg.TimestampFlexibility)
+	}
+	return b
+}
+
+// Start gets the genesis time and the slot duration from the beacon node, then sets up what depends on them and
+// starts the background tasks. The builder is not started by its constructors, which make no network calls, and Start
+// is called once, before the payload attributes are received.
+func (b *Builder) Start() {
+	ctx, cancel := context.WithTimeout(context.Background(), beaconStartupTimeout)
+	defer cancel()
+
+	genesisTime, err := b.beaconClient.GetGenesis(ctx)
+	if err != nil {
+		log.Error("could not get genesis time from beacon node, continuing anyway", "err", err)
+	}
+	b.genesisTime = genesisTime
+
+	secondsPerSlot, err := b.beaconClient.GetSpec(ctx)
+	if err != nil || secondsPerSlot == 0 {
+		log.Error("could not get seconds per slot from beacon node, using default", "err", err, "default", defaultSecondsPerSlot)
+		secondsPerSlot = defaultSecondsPerSlot
+	}
+	b.secondsPerSlot = secondsPerSlot
+
+	opts := b.opts
+	b.latencySLA = NewLatencySLA(opts.RelayLatencySLA, opts.RelayLatencySLAWindow, b.slotDuration())
+	if opts.InitialSubmissionDelay > 0 {
+		b.initialSubmissionDelay = opts.InitialSubmissionDelay
+		if maxDelay := b.slotDuration() / 2; b.initialSubmissionDelay > maxDelay {
+			log.Warn("initial submission delay capped to half the slot duration", "delay", opts.InitialSubmissionDelay, "max_delay", maxDelay)
+			b.initialSubmissionDelay = maxDelay
+		}
+	}
+	if opts.RegistrationCheckInterval > 0 {
+		for _, monitor := range b.registrationMonitors(opts.RegistrationCheckInterval) {
+			go monitor.run()
+		}
+	}
+	if opts.SigningDomainCheckInterval > 0 {
+		go b.runSigningDomainCheck(opts.SigningDomainCheckInterval)
+	}
+	if opts.Reconcile {
+		go b.runReconciliation()
+	}
+	if opts.SelfDrivenBuilds {
+		b.selfDrivenDelay = opts.SelfDrivenBuildDelay
+		if b.selfDrivenDelay <= 0 || b.selfDrivenDelay >= b.slotDuration() {
+			b.selfDrivenDelay = b.slotDuration() / 2
+		}
+		go b.runSlotTicker()
+	}
+	if opts.MissedSlotsThreshold > 0 {
+		b.missedSlotsThreshold = opts.MissedSlotsThreshold
+		go b.runMissedSlotsMonitor()
 	}
 }
 
-func (b *Builder) onSealedBlock(executableData *beacon.ExecutableDataV1, block *types.Block, proposerPubkey boostTypes.PublicKey, proposerFeeRecipient boostTypes.Address, slot uint64) error {
+// Stop closes the relays holding connections open, such as the submission streams, and the attributes recording.
+func (b *Builder) Stop() {
+	for _, relay := range b.relays() {
+		if closer, ok := relay.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
+	if b.recorder != nil {
+		if err := b.recorder.Close(); err != nil {
+			log.Warn("could not close the payload attributes recording", "err", err)
+		}
+	}
+}
+
+func (b *Builder) slotDuration() time.Duration {
+	return time.Duration(b.secondsPerSlot) * time.Second
+}
+
+// initialSubmissionDelayFor returns the delay before the slot's first build, leaving at least half of the time until
+// the deadline to build and submit.
+func (b *Builder) initialSubmissionDelayFor(slot uint64, deadline time.Time) time.Duration {
+	if b.initialSubmissionDelay <= 0 {
+		return 0
+	}
+	delay := b.initialSubmissionDelay
+	if remaining := time.Until(deadline) / 2; delay > remaining {
+		delay = remaining
+	}
+	if delay <= 0 {
+		return 0
+	}
+	log.Debug("delaying the slot's first build", "slot", slot, "delay", delay)
+	return delay
+}
+
+// onSealedBlock signs and submits the block built by eth, which simulates it first if SimulateBlocks is set.
+func (b *Builder) onSealedBlock(eth IEthereumService, executableData *beacon.ExecutableDataV1, block *types.Block, profitBreakdown *ProfitBreakdown, proposerPubkey boostTypes.PublicKey, proposerFeeRecipient boostTypes.Address, proposerIndex *uint64, slot uint64) error {
+	// Every submission gets an ID which is logged and sent to the relay, to correlate it across systems
+	submissionID := b.newSubmissionID()
+	clientVersion := eth.ClientVersion()
+	logger := log.New("submission_id", submissionID, "slot", slot, "el_version", clientVersion)
+	if proposerIndex != nil {
+		logger = logger.New("proposer_index", *proposerIndex)
+	}
+
+	if !b.anyRelayEnabled() {
+		logger.Debug("dropping block, all relays are disabled", "block_hash", block.Hash())
+		return ErrRelayDisabled
+	}
+	if reason := b.pause.reason(time.Now()); reason != "" {
+		logger.Debug("dropping block, the builder is paused", "reason", reason, "block_hash", block.Hash())
+		return ErrPaused
+	}
+
+	if b.simulateBlocks {
+		ctx, cancel := context.WithTimeout(context.Background(), b.slotDuration())
+		err := eth.SimulateBlock(ctx, block)
+		cancel()
+		if err != nil {
+			simulationFailedCounter.Inc(1)
+			logger.Error("dropping block failing the simulation", "err", err, "block_hash", block.Hash())
+			return fmt.Errorf("%w: %v", ErrSimulationFailed, err)
+		}
+	}
+
 	payload, err := executableDataToExecutionPayload(executableData)
 	if err != nil {
-		log.Error("could not format execution payload", "err", err)
+		logger.Error("could not format execution payload", "err", err)
 		return err
 	}
 
-	value := new(boostTypes.U256Str)
-	err = value.FromBig(block.Profit)
+	bidValue, err := computeBidValue(b.bidValue, block.Profit)
 	if err != nil {
-		log.Error("could not set block value", "err", err)
+		logger.Error("could not compute bid value", "err", err, "profit", block.Profit)
 		return err
 	}
 
+	value, err := bidValueToU256(bidValue)
+	if err != nil {
+		logger.Error("could not set block value", "err", err)
+		return err
+	}
+
+	if !b.anyRelayAcceptsValue(context.Background(), bidValue) {
+		logger.Debug("dropping block, the bid is below the minimum value of all relays", "block_hash", block.Hash(), "value", bidValue)
+		return ErrBidBelowMinValue
+	}
+
 	blockBidMsg := boostTypes.BidTrace{
 		Slot:                 slot,
 		ParentHash:           payload.ParentHash,
@@ -93,7 +571,12 @@ func (b *Builder) onSealedBlock(executableData *beacon.ExecutableDataV1, block *
 
 	signature, err := boostTypes.SignMessage(&blockBidMsg, b.builderSigningDomain, b.builderSecretKey)
 	if err != nil {
-		log.Error("could not sign builder bid", "err", err)
+		logger.Error("could not sign builder bid", "err", err)
+		return err
+	}
+	if err := verifySignature(&blockBidMsg, b.builderSigningDomain, signature); err != nil {
+		invalidSignatureCounter.Inc(1)
+		logger.Error("dropping block with an invalid signature", "err", err, "block_hash", payload.BlockHash)
 		return err
 	}
 
@@ -103,28 +586,153 @@ func (b *Builder) onSealedBlock(executableData *beacon.ExecutableDataV1, block *
 		ExecutionPayload: payload,
 	}
 
-	err = b.relay.SubmitBlock(&blockSubmitReq)
+	err = b.submitBlock(submissionID, clientVersion, proposerIndex, &blockSubmitReq, slot)
+	if b.dumper != nil {
+		if dumpErr := b.dumper.Dump(&blockSubmitReq, block, err != nil); dumpErr != nil {
+			logger.Warn("could not dump block", "err", dumpErr, "block_hash", payload.BlockHash)
+		}
+	}
+	if b.archiver != nil {
+		b.archiver.archive(&blockSubmitReq)
+	}
 	if err != nil {
-		log.Error("could not submit block", "err", err)
+		logger.Error("could not submit block", "err", err)
 		return err
 	}
+	logger.Info("submitted block", "block_hash", payload.BlockHash, "value", bidValue)
+
+	b.payloads.Add(slot, payload)
+	b.history.Add(slot, payload.BlockHash, payload.BlockNumber, b.reconcileAt(slot))
+
+	if profitBreakdown != nil {
+		logProfitBreakdown(logger, profitBreakdown, block)
+		logFeeBreakdown(logger, profitBreakdown, block)
+	}
 
 	return nil
 }
 
-func (b *Builder) OnPayloadAttribute(attrs *BuilderPayloadAttributes) error {
+func logProfitBreakdown(logger log.Logger, breakdown *ProfitBreakdown, block *types.Block) {
+	logger.Info("block profit breakdown", "block_hash", block.Hash(), "profit", block.Profit, "priority_fees", breakdown.PriorityFees, "direct_payments", breakdown.DirectPayments, "bundle_payments", breakdown.BundlePayments, "payment_tx_fee", breakdown.PaymentTxFee)
+
+	profitPriorityFeesHist.Update(weiToGwei(breakdown.PriorityFees))
+	profitDirectPaymentsHist.Update(weiToGwei(breakdown.DirectPayments))
+	profitBundlePaymentsHist.Update(weiToGwei(breakdown.BundlePayments))
+	profitPaymentTxFeeHist.Update(weiToGwei(breakdown.PaymentTxFee))
+}
+
+// logFeeBreakdown logs and meters the fee composition of the block, the proposer payment excluded from the
+// transaction count.
+func logFeeBreakdown(logger log.Logger, breakdown *ProfitBreakdown, block *types.Block) {
+	txCount := len(block.Transactions())
+	if breakdown.PaymentIndex >= 0 {
+		txCount--
+	}
+	logger.Info("block fee breakdown", "block_hash", block.Hash(), "txs", txCount, "gas_used", block.GasUsed(), "base_fee", block.BaseFee(), "priority_fees", breakdown.PriorityFees, "base_fee_burned", breakdown.BaseFeeBurned)
+
+	feeBaseFeeBurnedHist.Update(weiToGwei(breakdown.BaseFeeBurned))
+	feeTxCountHist.Update(int64(txCount))
+}
+
+// GetPayload returns the full execution payload of a previously submitted block for the given blinded block.
+func (b *Builder) GetPayload(blindedBlock *boostTypes.SignedBlindedBeaconBlock) (*boostTypes.ExecutionPayload, error) {
+	if blindedBlock == nil || blindedBlock.Message == nil || blindedBlock.Message.Body == nil {
+		return nil, errors.New("invalid blinded block")
+	}
+
+	payload, err := b.payloads.GetForHeader(blindedBlock.Message.Body.ExecutionPayloadHeader)
+	if err != nil {
+		log.Info("could not unblind block", "err", err, "slot", blindedBlock.Message.Slot)
+		return nil, err
+	}
+	b.history.MarkWon(blindedBlock.Message.Slot, payload.BlockHash)
+
+	return payload, nil
+}
+
+// OnPayloadAttribute builds and submits blocks for the attributes. Panics in the builds are recovered and returned
+// as ErrPanic, as the attributes come from external events which should not take down the node.
+func (b *Builder) OnPayloadAttribute(attrs *BuilderPayloadAttributes) (err error) {
+	defer recoverPanic(&err, "OnPayloadAttribute")
+	return b.onPayloadAttribute(attrs)
+}
+
+func (b *Builder) onPayloadAttribute(attrs *BuilderPayloadAttributes) error {
+	received := time.Now()
 	if attrs == nil {
 		return nil
 	}
 
+	// The attributes are not an error for the beacon node while paused
+	if reason := b.pause.reason(received); reason != "" {
+		pausedSlotsCounter.Inc(1)
+		log.Info("skipping payload attributes, the builder is paused", "reason", reason, "slot", attrs.Slot)
+		return nil
+	}
+
+	if err := attrs.Validate(); err != nil {
+		log.Info("dropping payload attributes", "err", err, "slot", attrs.Slot, "head_hash", attrs.HeadHash)
+		return err
+	}
+
+	if err := b.checkSlotAhead(attrs, received); err != nil {
+		return err
+	}
+
+	if err := b.checkAttributesSlot(attrs); err != nil {
+		return err
+	}
+
+	b.markReceivedAttributes(attrs.Slot)
+
+	if b.recorder != nil {
+		if err := b.recorder.Record(attrs); err != nil {
+			log.Error("could not record payload attributes", "err", err, "slot", attrs.Slot)
+		}
+	}
+
+	if err := b.overridePrevRandao(attrs); err != nil {
+		log.Error("refusing the payload attributes", "err", err, "slot", attrs.Slot)
+		return err
+	}
+	return b.buildForAttributes(attrs, received, nil)
+}
+
+// TriggerRebuild builds and submits a block for the slot right away, out of the resubmission cadence, for example
+// when a valuable bundle arrived. Returns ErrNoActiveBuild if blocks are not being built for the slot, including
+// in single-shot mode.
+func (b *Builder) TriggerRebuild(slot uint64) error {
+	if !b.resubmitter.nudge(slot) {
+		return fmt.Errorf("%w: slot %d", ErrNoActiveBuild, slot)
+	}
+	log.Info("triggered rebuild", "slot", slot)
+	return nil
+}
+
+// buildForAttributes builds and submits blocks for the attributes until the slot deadline,
+// unless superseded returns true when the builds would start. The latency from received, the time the attributes
+// were received, to the slot's first submission is recorded unless received is zero.
+func (b *Builder) buildForAttributes(attrs *BuilderPayloadAttributes, received time.Time, superseded func() bool) error {
+	b.slots.advance(attrs.Slot)
+
+	if b.observer {
+		return b.observeSlot(attrs)
+	}
+
+	deadline := time.Now().Add(b.slotDuration())
+
 	vd, err := b.relay.GetValidatorForSlot(attrs.Slot)
 	if err != nil {
 		log.Info("could not get validator while submitting block", "err", err, "slot", attrs.Slot)
 		return err
 	}
 
+	if vd.isStale(time.Now(), b.maxRegistrationAge) {
+		log.Info("dropping slot with stale validator registration", "slot", attrs.Slot, "pubkey", vd.Pubkey, "registered", time.Unix(int64(vd.Timestamp), 0), "max_age", b.maxRegistrationAge)
+		return fmt.Errorf("%w: registered at %d", ErrStaleRegistration, vd.Timestamp)
+	}
+
 	attrs.SuggestedFeeRecipient = [20]byte(vd.FeeRecipient)
-	attrs.GasLimit = vd.GasLimit
 
 	proposerPubkey, err := boostTypes.HexToPubkey(string(vd.Pubkey))
 	if err != nil {
@@ -132,38 +740,181 @@ func (b *Builder) OnPayloadAttribute(attrs *BuilderPayloadAttributes) error {
 		return err
 	}
 
-	if !b.eth.Synced() {
-		return errors.New("backend not Synced")
+	// The Bellatrix bid trace identifies the proposer by pubkey only, the index is checked against it when the beacon
+	// node has the slot's duties and sent with the submissions
+	var submittedProposerIndex *uint64
+	proposerIndex, err := b.proposerIndex(attrs.Slot, vd.Pubkey)
+	if errors.Is(err, ErrProposerMismatch) {
+		log.Info("dropping slot, the registered validator is not the proposer", "err", err, "slot", attrs.Slot)
+		return err
+	} else if err != nil {
+		if err = b.beaconValidationFailed("proposer cross-check", attrs.Slot, err); err != nil {
+			return err
+		}
+	} else {
+		submittedProposerIndex = &proposerIndex
+	}
+	go b.checkRegisteredGasLimit(attrs.Slot, proposerPubkey, vd.GasLimit)
+
+	eth, err := b.syncedEthService(deadline)
+	if err != nil {
+		log.Info("could not build block", "err", err, "slot", attrs.Slot)
+		return err
 	}
 
-	parentBlock := b.eth.GetBlockByHash(attrs.HeadHash)
+	parentBlock := eth.GetBlockByHash(attrs.HeadHash)
 	if parentBlock == nil {
-		log.Info("Block hash not found in blocktree", "head block hash", attrs.HeadHash)
+		log.Info("Block hash not found in blocktree", "head_hash", attrs.HeadHash)
 		return errors.New("parent block not found in blocktree")
 	}
 
-	firstBlockResult := b.resubmitter.newTask(12*time.Second, time.Second, func() error {
-		executableData, block := b.eth.BuildBlock(attrs)
-		if executableData == nil || block == nil {
-			log.Error("did not receive the payload")
-			return errors.New("did not receive the payload")
-		}
+	attrs.GasLimit = b.gasLimitForSlot(vd.GasLimit, parentBlock.GasLimit(), attrs.Slot)
+	reportGasLimit(attrs.Slot, attrs.HeadHash, vd.GasLimit, parentBlock.GasLimit(), attrs.GasLimit)
+	if err := b.chooseTimestamp(attrs, parentBlock.Time()); err != nil {
+		return err
+	}
 
-		err := b.onSealedBlock(executableData, block, proposerPubkey, vd.FeeRecipient, attrs.Slot)
+	if b.speculativeBuilds {
+		b.submitSpeculation(eth, attrs, proposerPubkey, vd.FeeRecipient, submittedProposerIndex)
+	}
+
+	heads := append([]headCandidate{{attrs: attrs, parent: parentBlock}}, b.candidateHeads(eth, attrs, vd.GasLimit)...)
+
+	var submitted, speculated int32
+	var lazy lazyBuildState
+	buildAndSubmit := func() error {
+		if !b.lazyBuildWorth(attrs.Slot, &lazy) {
+			return nil
+		}
+		blocks, err := b.buildOnHeads(eth, heads, deadline, proposerIndex)
 		if err != nil {
-			log.Error("could not run block hook", "err", err)
 			return err
 		}
+		lazy.setProfit(mostProfitable(blocks).block.Profit)
+		// Each head's block is submitted, a failed submission doesn't keep the other heads' blocks from the relays
+		var firstErr error
+		anySubmitted := false
+		for _, built := range blocks {
+			err = b.onSealedBlock(eth, built.executableData, built.block, built.profitBreakdown, proposerPubkey, vd.FeeRecipient, submittedProposerIndex, attrs.Slot)
+			if err != nil {
+				log.Error("could not run block hook", "err", err, "head_hash", built.head.attrs.HeadHash)
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			anySubmitted = true
+
+			if !received.IsZero() && atomic.CompareAndSwapInt32(&submitted, 0, 1) {
+				latency := time.Since(received)
+				firstSubmissionTimer.Update(latency)
+				log.Info("submitted the slot's first block", "slot", attrs.Slot, "block_hash", built.block.Hash(), "since_attributes", latency)
+			}
+		}
+		if anySubmitted && b.speculativeBuilds && atomic.CompareAndSwapInt32(&speculated, 0, 1) {
+			go b.buildSpeculative(eth, attrs, parentBlock.GasLimit(), deadline)
+		}
 
-		return nil
-	})
+		return firstErr
+	}
+
+	// The delayed first build runs in the background, the attributes of the next slot replace it
+	delay := b.initialSubmissionDelayFor(attrs.Slot, deadline)
 
+	if b.singleShot {
+		return b.resubmitter.runOnceUnless(superseded, delay, deadline, buildAndSubmit)
+	}
+
+	firstBlockResult := b.resubmitter.newTaskUnless(superseded, attrs.Slot, delay, time.Until(deadline), time.Second, buildAndSubmit)
 	return firstBlockResult
 }
 
+// buildBlockWithRetry builds a block, retrying once shortly after if the EL did not return one
+// as it may not be ready yet, for example right after the head changed.
+func (b *Builder) buildBlockWithRetry(eth IEthereumService, attrs *BuilderPayloadAttributes, deadline time.Time) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown, BuildStats, error) {
+	if err := b.acquireBuildSlot(deadline); err != nil {
+		return nil, nil, nil, BuildStats{}, err
+	}
+	defer b.releaseBuildSlot()
+
+	executableData, block, profitBreakdown, stats, err := buildBlockWithStats(eth, attrs)
+	if executableData != nil && block != nil {
+		return executableData, block, profitBreakdown, stats, nil
+	}
+	noPayloadFromELCounter.Inc(1)
+	if errors.Is(err, ErrProposerPayment) {
+		// Building again would not pay the proposer either
+		return nil, nil, nil, stats, err
+	}
+
+	if time.Until(deadline) <= noPayloadRetryDelay {
+		return nil, nil, nil, stats, ErrNoPayloadFromEL
+	}
+	time.Sleep(noPayloadRetryDelay)
+
+	executableData, block, profitBreakdown, stats, err = buildBlockWithStats(eth, attrs)
+	if executableData == nil || block == nil {
+		noPayloadFromELCounter.Inc(1)
+		if errors.Is(err, ErrProposerPayment) {
+			return nil, nil, nil, stats, err
+		}
+		return nil, nil, nil, stats, ErrNoPayloadFromEL
+	}
+	return executableData, block, profitBreakdown, stats, nil
+}
+
+// buildBlockWithStats builds a block with the timings and the error reported by the EL, or timed around BuildBlock
+// for the Ethereum services not reporting them.
+func buildBlockWithStats(eth IEthereumService, attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown, BuildStats, error) {
+	if reporter, ok := eth.(BuildStatsReporter); ok {
+		return reporter.BuildBlockWithStats(attrs)
+	}
+
+	start := time.Now()
+	executableData, block, profitBreakdown := eth.BuildBlock(attrs)
+	elapsed := time.Since(start)
+	return executableData, block, profitBreakdown, BuildStats{Total: elapsed, Build: elapsed}, nil
+}
+
+// gasLimitForSlot clamps the validator's gas limit to the relay constraints, unconstrained if they are not available.
+func (b *Builder) gasLimitForSlot(requested uint64, parentGasLimit uint64, slot uint64) uint64 {
+	if smoothed := smoothGasLimit(requested, parentGasLimit, b.gasLimitSmoothing); smoothed != requested {
+		log.Debug("gas limit smoothed", "slot", slot, "requested", requested, "smoothed", smoothed, "parent_gas_limit", parentGasLimit)
+		requested = smoothed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), relayConstraintsTimeout)
+	defer cancel()
+
+	constraints, err := b.relay.GetConstraints(ctx)
+	if err != nil {
+		log.Info("could not get relay constraints", "err", err, "slot", slot)
+		return requested
+	}
+
+	gasLimit := clampGasLimit(requested, parentGasLimit, constraints)
+	if gasLimit != requested {
+		log.Info("gas limit adjusted due to relay constraints", "slot", slot, "requested", requested, "adjusted", gasLimit, "parent_gas_limit", parentGasLimit, "relay_min_gas_limit", constraints.MinGasLimit, "relay_max_gas_limit", constraints.MaxGasLimit)
+	}
+	return gasLimit
+}
+
 func executableDataToExecutionPayload(data *beacon.ExecutableDataV1) (*boostTypes.ExecutionPayload, error) {
+	switch {
+	case data.StateRoot == (common.Hash{}):
+		return nil, fmt.Errorf("%w: state root of block %s", ErrZeroPayloadHash, data.BlockHash)
+	case data.ReceiptsRoot == (common.Hash{}):
+		return nil, fmt.Errorf("%w: receipts root of block %s", ErrZeroPayloadHash, data.BlockHash)
+	case data.BlockHash == (common.Hash{}):
+		return nil, fmt.Errorf("%w: block hash", ErrZeroPayloadHash)
+	}
+
 	transactionData := make([]hexutil.Bytes, len(data.Transactions))
 	for i, tx := range data.Transactions {
+		if len(tx) == 0 {
+			log.Error("empty transaction in executable data", "index", i, "block_hash", data.BlockHash)
+			return nil, fmt.Errorf("%w at index %d", ErrEmptyTransaction, i)
+		}
 		transactionData[i] = hexutil.Bytes(tx)
 	}
 
@@ -0,0 +1,141 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/beacon"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+var benchmarkTxCounts = []int{0, 100, 1000}
+
+// newBenchmarkExecutableData returns executable data with txCount transactions of a typical transfer size
+func newBenchmarkExecutableData(txCount int) *beacon.ExecutableDataV1 {
+	rng := rand.New(rand.NewSource(1))
+	txs := make([][]byte, txCount)
+	for i := range txs {
+		txs[i] = make([]byte, 200)
+		rng.Read(txs[i])
+	}
+
+	return &beacon.ExecutableDataV1{
+		ParentHash:    common.Hash{0x02, 0x03},
+		FeeRecipient:  common.Address{0x04, 0x10},
+		StateRoot:     common.Hash{0x07, 0x16},
+		ReceiptsRoot:  common.Hash{0x08, 0x20},
+		LogsBloom:     make([]byte, 256),
+		Number:        uint64(10),
+		GasLimit:      uint64(30_000_000),
+		GasUsed:       uint64(21_000 * txCount),
+		Timestamp:     uint64(105),
+		ExtraData:     []byte{0x00, 0x42, 0xfa, 0xfc},
+		BaseFeePerGas: big.NewInt(16),
+		BlockHash:     common.Hash{0x09, 0xff},
+		Transactions:  txs,
+	}
+}
+
+func BenchmarkExecutableDataToExecutionPayload(b *testing.B) {
+	for _, txCount := range benchmarkTxCounts {
+		data := newBenchmarkExecutableData(txCount)
+		b.Run(fmt.Sprintf("txs=%d", txCount), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := executableDataToExecutionPayload(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkRemoteRelaySubmitBlock compares the concurrent submissions to a relay over HTTP/1.1, with a connection
+// per concurrent request, and HTTP/2, multiplexing them over a single connection.
+func BenchmarkRemoteRelaySubmitBlock(b *testing.B) {
+	srv, tlsConfig := newTestTLSRelay(b)
+	payload, err := executableDataToExecutionPayload(newBenchmarkExecutableData(100))
+	if err != nil {
+		b.Fatal(err)
+	}
+	req := &boostTypes.BuilderSubmitBlockRequest{Message: &boostTypes.BidTrace{Slot: 25}, ExecutionPayload: payload}
+
+	for _, disableHTTP2 := range []bool{true, false} {
+		relay, err := NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{TLSConfig: tlsConfig, DisableHTTP2: disableHTTP2})
+		if err != nil {
+			b.Fatal(err)
+		}
+		name := "h2"
+		if disableHTTP2 {
+			name = "h1.1"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := relay.SubmitBlock(context.Background(), req); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkSignBid(b *testing.B) {
+	builder, _ := newTestBuilderWithOptions(b, newTestEthereumService(), BuilderOptions{})
+	msg := &boostTypes.BidTrace{
+		Slot:          25,
+		BuilderPubkey: builder.builderPublicKey,
+		GasLimit:      30_000_000,
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := boostTypes.SignMessage(msg, builder.builderSigningDomain, builder.builderSecretKey); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOnSealedBlock(b *testing.B) {
+	for _, txCount := range benchmarkTxCounts {
+		data := newBenchmarkExecutableData(txCount)
+		b.Run(fmt.Sprintf("txs=%d", txCount), func(b *testing.B) {
+			builder, _ := newTestBuilderWithOptions(b, newTestEthereumService(), BuilderOptions{})
+			block := newTestBlock(10)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := builder.onSealedBlock(builder.eth, data, block, nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkBuildToSubmit runs the payload attributes handling, from the relay validator lookup to the submission
+// to the mock relay, with instant block building.
+func BenchmarkBuildToSubmit(b *testing.B) {
+	for _, txCount := range benchmarkTxCounts {
+		ethService := newTestEthereumService()
+		ethService.testExecutableData = newBenchmarkExecutableData(txCount)
+		b.Run(fmt.Sprintf("txs=%d", txCount), func(b *testing.B) {
+			builder, _ := newTestBuilderWithOptions(b, ethService, BuilderOptions{})
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := builder.OnPayloadAttribute(newTestAttributes(25)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
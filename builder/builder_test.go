@@ -1,7 +1,12 @@
 package builder
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math/big"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -9,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/beacon"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/flashbots/go-boost-utils/bls"
 	boostTypes "github.com/flashbots/go-boost-utils/types"
 	"github.com/stretchr/testify/require"
@@ -60,9 +66,7 @@ func TestOnPayloadAttributes(t *testing.T) {
 		Transactions: [][]byte{},
 	}
 
-	testBlock := &types.Block{
-		Profit: big.NewInt(10),
-	}
+	testBlock := newTestBlock(10)
 
 	testPayloadAttributes := &BuilderPayloadAttributes{
 		Timestamp:             hexutil.Uint64(104),
@@ -70,11 +74,13 @@ func TestOnPayloadAttributes(t *testing.T) {
 		SuggestedFeeRecipient: common.Address{0x04, 0x10},
 		GasLimit:              uint64(21),
 		Slot:                  uint64(25),
+		HeadHash:              common.Hash{0x02, 0x03},
 	}
 
 	testEthService := &testEthereumService{synced: true, testExecutableData: testExecutableData, testBlock: testBlock}
 
 	builder := NewBuilder(sk, &testBeacon, &testRelay, bDomain, testEthService)
+	builder.Start()
 
 	builder.OnPayloadAttribute(testPayloadAttributes)
 
@@ -122,9 +128,659 @@ func TestOnPayloadAttributes(t *testing.T) {
 
 	require.Equal(t, uint64(25), testRelay.requestedSlot)
 
+	// The submitted payload can be unblinded by its header
+	expectedHeader, err := boostTypes.PayloadToPayloadHeader(&expectedExecutionPayload)
+	require.NoError(t, err)
+	unblindedPayload, err := builder.GetPayload(&boostTypes.SignedBlindedBeaconBlock{
+		Message: &boostTypes.BlindedBeaconBlock{
+			Slot: uint64(25),
+			Body: &boostTypes.BlindedBeaconBlockBody{ExecutionPayloadHeader: expectedHeader},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, expectedExecutionPayload, *unblindedPayload)
+
+	require.NotEmpty(t, testRelay.submissionID)
+	firstSubmissionID := testRelay.submissionID
+
 	// Clear the submitted message and check that the job will be ran again and a new message will be submitted
 	testRelay.submittedMsg = nil
 	time.Sleep(2 * time.Second)
 	require.NotNil(t, testRelay.submittedMsg)
 	require.Equal(t, expectedMessage, *testRelay.submittedMsg.Message)
+	require.NotEqual(t, firstSubmissionID, testRelay.submissionID)
+}
+
+func TestExecutableDataToExecutionPayloadEmptyTransaction(t *testing.T) {
+	data := newTestExecutableData()
+	data.Transactions = [][]byte{{0x01, 0x02}}
+
+	payload, err := executableDataToExecutionPayload(data)
+	require.NoError(t, err)
+	require.Len(t, payload.Transactions, 1)
+
+	data.Transactions = append(data.Transactions, []byte{})
+	_, err = executableDataToExecutionPayload(data)
+	require.ErrorIs(t, err, ErrEmptyTransaction)
+}
+
+func TestExecutableDataToExecutionPayloadZeroHash(t *testing.T) {
+	for name, zero := range map[string]func(*beacon.ExecutableDataV1){
+		"state root":    func(data *beacon.ExecutableDataV1) { data.StateRoot = common.Hash{} },
+		"receipts root": func(data *beacon.ExecutableDataV1) { data.ReceiptsRoot = common.Hash{} },
+		"block hash":    func(data *beacon.ExecutableDataV1) { data.BlockHash = common.Hash{} },
+	} {
+		data := newTestExecutableData()
+		zero(data)
+		_, err := executableDataToExecutionPayload(data)
+		require.ErrorIs(t, err, ErrZeroPayloadHash, name)
+		require.ErrorContains(t, err, name)
+	}
+
+	// The blocks with zero roots are not submitted
+	eth := newTestEthereumService()
+	eth.testExecutableData.StateRoot = common.Hash{}
+	builder, testRelay := newTestBuilderWithOptions(t, eth, BuilderOptions{SingleShot: true})
+	require.ErrorIs(t, builder.OnPayloadAttribute(newTestAttributes(25)), ErrZeroPayloadHash)
+	require.Nil(t, testRelay.submittedMsg)
+}
+
+type slowEthereumService struct {
+	testEthereumService
+	buildDelay time.Duration
+
+	mu         sync.Mutex
+	building   int
+	maxBuilds  int
+	totalBuilt int
+}
+
+func (s *slowEthereumService) BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown) {
+	s.mu.Lock()
+	s.building++
+	s.totalBuilt++
+	if s.building > s.maxBuilds {
+		s.maxBuilds = s.building
+	}
+	s.mu.Unlock()
+
+	time.Sleep(s.buildDelay)
+
+	s.mu.Lock()
+	s.building--
+	s.mu.Unlock()
+	return s.testEthereumService.BuildBlock(attrs)
+}
+
+// statsEthereumService reports fixed build timings, and builds no block if err is set
+type statsEthereumService struct {
+	testEthereumService
+	stats  BuildStats
+	err    error
+	builds int
+}
+
+func (s *statsEthereumService) BuildBlockWithStats(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown, BuildStats, error) {
+	s.builds++
+	if s.err != nil {
+		return nil, nil, nil, s.stats, s.err
+	}
+	executableData, block, breakdown := s.testEthereumService.BuildBlock(attrs)
+	return executableData, block, breakdown, s.stats, nil
+}
+
+func TestBuildBlockWithStats(t *testing.T) {
+	// The builds of services not reporting their timings are timed around BuildBlock
+	slowService := &slowEthereumService{testEthereumService: *newTestEthereumService(), buildDelay: 50 * time.Millisecond}
+	_, block, _, stats, err := buildBlockWithStats(slowService, newTestAttributes(25))
+	require.NoError(t, err)
+	require.NotNil(t, block)
+	require.GreaterOrEqual(t, stats.Total, 50*time.Millisecond)
+	require.Equal(t, stats.Total, stats.Build)
+
+	reported := BuildStats{Total: 3 * time.Second, Build: 2 * time.Second}
+	statsService := &statsEthereumService{testEthereumService: *newTestEthereumService(), stats: reported}
+	_, block, _, stats, err = buildBlockWithStats(statsService, newTestAttributes(25))
+	require.NoError(t, err)
+	require.NotNil(t, block)
+	require.Equal(t, reported, stats)
+
+	// The proposer payment errors are returned, without building again
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	statsService.err = fmt.Errorf("%w: commit tx: insufficient funds", ErrProposerPayment)
+	statsService.builds = 0
+	_, block, _, _, err = builder.buildBlockWithRetry(statsService, newTestAttributes(25), time.Now().Add(time.Second))
+	require.ErrorIs(t, err, ErrProposerPayment)
+	require.Nil(t, block)
+	require.Equal(t, 1, statsService.builds)
+}
+
+// newTestBlock returns a block with the profit, usable both as the built block and as its parent: it is numbered as
+// the parent of the test payloads, which are block 10
+func newTestBlock(profit int64) *types.Block {
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(9), GasLimit: 30_000_000})
+	block.Profit = big.NewInt(profit)
+	return block
+}
+
+func newTestAttributes(slot uint64) *BuilderPayloadAttributes {
+	return &BuilderPayloadAttributes{
+		Timestamp: hexutil.Uint64(1000 + slot*12),
+		Slot:      slot,
+		HeadHash:  common.Hash{0x02, 0x03},
+	}
+}
+
+// newTestExecutableData returns the executable data of an empty block, with the roots set.
+func newTestExecutableData() *beacon.ExecutableDataV1 {
+	return &beacon.ExecutableDataV1{
+		StateRoot:     common.Hash{0x07, 0x16},
+		ReceiptsRoot:  common.Hash{0x08, 0x20},
+		BlockHash:     common.Hash{0x09, 0xff},
+		BaseFeePerGas: big.NewInt(16),
+		Transactions:  [][]byte{},
+	}
+}
+
+func newTestEthereumService() *testEthereumService {
+	executableData := newTestExecutableData()
+	// The head of newTestAttributes
+	executableData.ParentHash = common.Hash{0x02, 0x03}
+	executableData.Number = 10
+	return &testEthereumService{
+		synced:             true,
+		testExecutableData: executableData,
+		testBlock:          newTestBlock(10),
+	}
+}
+
+func newTestBuilderWithOptions(t testing.TB, ethService IEthereumService, opts BuilderOptions) (*Builder, *testRelay) {
+	validator := NewRandomValidator()
+	testBeacon := &testBeaconClient{validator: validator}
+	testRelay := &testRelay{
+		validator: ValidatorData{
+			Pubkey:   PubkeyHex(validator.Pk.String()),
+			GasLimit: 10,
+		},
+	}
+
+	sk, err := bls.GenerateRandomSecretKey()
+	require.NoError(t, err)
+	bDomain := boostTypes.ComputeDomain(boostTypes.DomainTypeAppBuilder, [4]byte{0x02, 0x0, 0x0, 0x0}, boostTypes.Hash{})
+
+	builder := NewBuilderWithOptions(sk, testBeacon, testRelay, bDomain, ethService, opts)
+	builder.Start()
+	return builder, testRelay
+}
+
+// specCountingBeaconClient counts the genesis and spec requests
+type specCountingBeaconClient struct {
+	*testBeaconClient
+	requests int
+}
+
+func (b *specCountingBeaconClient) GetGenesis(ctx context.Context) (uint64, error) {
+	b.requests++
+	return b.testBeaconClient.GetGenesis(ctx)
+}
+
+func (b *specCountingBeaconClient) GetSpec(ctx context.Context) (uint64, error) {
+	b.requests++
+	return b.testBeaconClient.GetSpec(ctx)
+}
+
+func TestBuilderStartQueriesBeacon(t *testing.T) {
+	sk, err := bls.GenerateRandomSecretKey()
+	require.NoError(t, err)
+	bDomain := boostTypes.ComputeDomain(boostTypes.DomainTypeAppBuilder, [4]byte{0x02, 0x0, 0x0, 0x0}, boostTypes.Hash{})
+	beacon := &specCountingBeaconClient{testBeaconClient: &testBeaconClient{validator: NewRandomValidator(), genesisTime: 1000, secondsPerSlot: 6}}
+
+	// The constructor makes no network calls, the slot timing defaults until Start
+	builder := NewBuilderWithOptions(sk, beacon, &testRelay{}, bDomain, newTestEthereumService(), BuilderOptions{InitialSubmissionDelay: 4 * time.Second})
+	require.Zero(t, beacon.requests)
+	require.Zero(t, builder.genesisTime)
+	require.Equal(t, uint64(defaultSecondsPerSlot), builder.secondsPerSlot)
+
+	service := &Service{builder: builder}
+	require.NoError(t, service.Start())
+	require.Equal(t, 2, beacon.requests)
+	require.Equal(t, uint64(1000), builder.genesisTime)
+	require.Equal(t, 6*time.Second, builder.slotDuration())
+	// The initial delay is capped to half of the beacon node's slot duration
+	require.Equal(t, 3*time.Second, builder.initialSubmissionDelay)
+}
+
+func TestBuilderPayloadAttributesValidate(t *testing.T) {
+	require.NoError(t, newTestAttributes(25).Validate())
+
+	attrs := newTestAttributes(25)
+	attrs.Slot = 0
+	require.ErrorIs(t, attrs.Validate(), ErrInvalidAttributes)
+	require.ErrorContains(t, attrs.Validate(), "slot")
+
+	attrs = newTestAttributes(25)
+	attrs.HeadHash = common.Hash{}
+	require.ErrorContains(t, attrs.Validate(), "head hash")
+
+	attrs = newTestAttributes(25)
+	attrs.Timestamp = 0
+	require.ErrorContains(t, attrs.Validate(), "timestamp")
+
+	// Dropped before requesting the slot's validator
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	require.ErrorIs(t, builder.OnPayloadAttribute(&BuilderPayloadAttributes{Slot: 25}), ErrInvalidAttributes)
+	require.Zero(t, testRelay.requestedSlot)
+	require.Nil(t, testRelay.submittedMsg)
+}
+
+func TestStaleRegistration(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{MaxRegistrationAge: time.Hour})
+
+	testRelay.validator.Timestamp = uint64(time.Now().Add(-2 * time.Hour).Unix())
+	require.ErrorIs(t, builder.OnPayloadAttribute(newTestAttributes(25)), ErrStaleRegistration)
+	require.Nil(t, testRelay.submittedMsg)
+
+	testRelay.validator.Timestamp = uint64(time.Now().Add(-time.Minute).Unix())
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(26)))
+	require.NotNil(t, testRelay.submittedMsg)
+
+	// Unknown age
+	require.False(t, ValidatorData{}.isStale(time.Now(), time.Hour))
+	// Disabled
+	require.False(t, ValidatorData{Timestamp: 1}.isStale(time.Now(), 0))
+}
+
+func TestInitialSubmissionDelay(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{InitialSubmissionDelay: 100 * time.Millisecond})
+
+	// The attributes are not held up by the delay
+	start := time.Now()
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+	require.Nil(t, testRelay.submittedMsg)
+	time.Sleep(200 * time.Millisecond)
+	require.NotNil(t, testRelay.submittedMsg)
+
+	// The delayed build is dropped once the attributes of another slot replace it
+	builder, testRelay = newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{InitialSubmissionDelay: 200 * time.Millisecond, SingleShot: true})
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	builder.initialSubmissionDelay = 0
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(26)))
+	require.Equal(t, uint64(26), testRelay.submittedMsg.Message.Slot)
+	time.Sleep(300 * time.Millisecond)
+	require.Equal(t, uint64(26), testRelay.submittedMsg.Message.Slot)
+
+	// The delay leaves half of the time until the deadline
+	require.Less(t, builder.initialSubmissionDelayFor(25, time.Now().Add(50*time.Millisecond)), 50*time.Millisecond)
+
+	// Capped to half the slot duration
+	builder, _ = newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{InitialSubmissionDelay: time.Minute})
+	require.Equal(t, 6*time.Second, builder.initialSubmissionDelay)
+}
+
+func TestMostRecentRegistration(t *testing.T) {
+	older := ValidatorData{Pubkey: "0x01", Timestamp: 10}
+	newer := ValidatorData{Pubkey: "0x02", Timestamp: 20}
+	require.Equal(t, newer, mostRecentRegistration(older, newer))
+	require.Equal(t, newer, mostRecentRegistration(newer, older))
+	require.Equal(t, older, mostRecentRegistration(older, ValidatorData{Pubkey: "0x03", Timestamp: 10}))
+}
+
+func TestOnPayloadAttributesSlowBuild(t *testing.T) {
+	testEthService := &slowEthereumService{
+		testEthereumService: *newTestEthereumService(),
+		// Slower than the 1s resubmit interval
+		buildDelay: 1500 * time.Millisecond,
+	}
+
+	builder, _ := newTestBuilderWithOptions(t, testEthService, BuilderOptions{})
+
+	var wg sync.WaitGroup
+	for slot := uint64(25); slot < 27; slot++ {
+		wg.Add(1)
+		go func(slot uint64) {
+			defer wg.Done()
+			builder.OnPayloadAttribute(newTestAttributes(slot))
+		}(slot)
+		time.Sleep(500 * time.Millisecond)
+	}
+	wg.Wait()
+	time.Sleep(2 * time.Second)
+
+	testEthService.mu.Lock()
+	defer testEthService.mu.Unlock()
+	require.Equal(t, 1, testEthService.maxBuilds)
+	require.GreaterOrEqual(t, testEthService.totalBuilt, 2)
+}
+
+func TestGasLimitRelayConstraints(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+
+	// Unconstrained
+	require.Equal(t, uint64(35_000_000), builder.gasLimitForSlot(35_000_000, 30_000_000, 25))
+
+	testRelay.constraints = RelayConstraints{MaxGasLimit: 30_010_000}
+	require.Equal(t, uint64(30_010_000), builder.gasLimitForSlot(35_000_000, 30_000_000, 25))
+}
+
+func TestGasLimitSmoothing(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{GasLimitSmoothing: 0.5})
+	require.Equal(t, uint64(30_005_000), builder.gasLimitForSlot(30_010_000, 30_000_000, 25))
+
+	// The relay constraints apply to the smoothed gas limit
+	testRelay.constraints = RelayConstraints{MaxGasLimit: 30_002_000}
+	require.Equal(t, uint64(30_002_000), builder.gasLimitForSlot(30_010_000, 30_000_000, 25))
+}
+
+func TestOnPayloadAttributesSingleShot(t *testing.T) {
+	testEthService := &slowEthereumService{testEthereumService: *newTestEthereumService()}
+	builder, testRelay := newTestBuilderWithOptions(t, testEthService, BuilderOptions{SingleShot: true})
+
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.NotNil(t, testRelay.submittedMsg)
+
+	// No resubmission after the resubmit interval
+	time.Sleep(1500 * time.Millisecond)
+	testEthService.mu.Lock()
+	defer testEthService.mu.Unlock()
+	require.Equal(t, 1, testEthService.totalBuilt)
+}
+
+type flakyEthereumService struct {
+	testEthereumService
+	failures int32
+	built    int32
+}
+
+func (s *flakyEthereumService) BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown) {
+	if atomic.AddInt32(&s.built, 1) <= s.failures {
+		return nil, nil, nil
+	}
+	return s.testEthereumService.BuildBlock(attrs)
+}
+
+func TestOnPayloadAttributesNoPayloadFromEL(t *testing.T) {
+	// Retried once when the EL returns no block
+	testEthService := &flakyEthereumService{testEthereumService: *newTestEthereumService(), failures: 1}
+	builder, testRelay := newTestBuilderWithOptions(t, testEthService, BuilderOptions{SingleShot: true})
+
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.NotNil(t, testRelay.submittedMsg)
+	require.Equal(t, int32(2), atomic.LoadInt32(&testEthService.built))
+
+	testEthService = &flakyEthereumService{testEthereumService: *newTestEthereumService(), failures: 2}
+	builder, testRelay = newTestBuilderWithOptions(t, testEthService, BuilderOptions{SingleShot: true})
+
+	err := builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrNoPayloadFromEL)
+	require.Nil(t, testRelay.submittedMsg)
+	require.Equal(t, int32(2), atomic.LoadInt32(&testEthService.built))
+}
+
+func TestOnPayloadAttributesBlockValueOverflow(t *testing.T) {
+	testEthService := newTestEthereumService()
+	testEthService.testBlock.Profit = new(big.Int).Lsh(big.NewInt(1), 256)
+	builder, testRelay := newTestBuilderWithOptions(t, testEthService, BuilderOptions{SingleShot: true})
+
+	err := builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrBlockValueOverflow)
+	require.Nil(t, testRelay.submittedMsg)
+}
+
+func TestOnPayloadAttributesClientVersion(t *testing.T) {
+	testEthService := newTestEthereumService()
+	testEthService.clientVersion = "Geth/v1.10.23-stable/linux-amd64/go1.18"
+	builder, testRelay := newTestBuilderWithOptions(t, testEthService, BuilderOptions{SingleShot: true})
+
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.NotNil(t, testRelay.submittedMsg)
+	require.Equal(t, "Geth/v1.10.23-stable/linux-amd64/go1.18", testRelay.clientVersion)
+}
+
+func TestOnPayloadAttributesParentMismatch(t *testing.T) {
+	testEthService := newTestEthereumService()
+	testEthService.testExecutableData.ParentHash = common.Hash{0x02, 0x04}
+	builder, testRelay := newTestBuilderWithOptions(t, testEthService, BuilderOptions{SingleShot: true})
+
+	err := builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrParentMismatch)
+	require.Nil(t, testRelay.submittedMsg)
+}
+
+func TestOnPayloadAttributesBlockNumberMismatch(t *testing.T) {
+	testEthService := newTestEthereumService()
+	testEthService.testExecutableData.Number = 11
+	builder, testRelay := newTestBuilderWithOptions(t, testEthService, BuilderOptions{SingleShot: true})
+
+	err := builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrBlockNumberMismatch)
+	require.Nil(t, testRelay.submittedMsg)
+}
+
+func TestOnPayloadAttributesSimulateBlocks(t *testing.T) {
+	testEthService := newTestEthereumService()
+	testEthService.simulateErr = errors.New("invalid state root")
+
+	// The simulation is opt-in
+	builder, testRelay := newTestBuilderWithOptions(t, testEthService, BuilderOptions{SingleShot: true})
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.NotNil(t, testRelay.submittedMsg)
+
+	builder, testRelay = newTestBuilderWithOptions(t, testEthService, BuilderOptions{SingleShot: true, SimulateBlocks: true})
+	err := builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrSimulationFailed)
+	require.Nil(t, testRelay.submittedMsg)
+
+	testEthService.simulateErr = nil
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(26)))
+	require.NotNil(t, testRelay.submittedMsg)
+}
+
+func TestOnPayloadAttributesBloomMismatch(t *testing.T) {
+	testEthService := newTestEthereumService()
+	testEthService.simulateErr = fmt.Errorf("%w: block %s", ErrBloomMismatch, testEthService.testBlock.Hash())
+
+	// The bloom not matching the receipts fails the simulation
+	builder, testRelay := newTestBuilderWithOptions(t, testEthService, BuilderOptions{SingleShot: true, SimulateBlocks: true})
+	err := builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrSimulationFailed)
+	require.ErrorContains(t, err, ErrBloomMismatch.Error())
+	require.Nil(t, testRelay.submittedMsg)
+}
+
+// panickingEthereumService panics from the panicFrom-th build on
+type panickingEthereumService struct {
+	testEthereumService
+	panicFrom int32
+	built     int32
+}
+
+func (s *panickingEthereumService) BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown) {
+	if atomic.AddInt32(&s.built, 1) >= s.panicFrom {
+		panic("nil block")
+	}
+	return s.testEthereumService.BuildBlock(attrs)
+}
+
+func TestOnPayloadAttributesRecoversPanic(t *testing.T) {
+	testEthService := &panickingEthereumService{testEthereumService: *newTestEthereumService(), panicFrom: 1}
+	builder, testRelay := newTestBuilderWithOptions(t, testEthService, BuilderOptions{})
+
+	err := builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrPanic)
+	require.Nil(t, testRelay.submittedMsg)
+
+	// The resubmissions recover as well
+	testEthService = &panickingEthereumService{testEthereumService: *newTestEthereumService(), panicFrom: 2}
+	builder, testRelay = newTestBuilderWithOptions(t, testEthService, BuilderOptions{})
+
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.NotNil(t, testRelay.submittedMsg)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&testEthService.built) > 2 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTriggerRebuild(t *testing.T) {
+	testEthService := &flakyEthereumService{testEthereumService: *newTestEthereumService()}
+	builder, _ := newTestBuilderWithOptions(t, testEthService, BuilderOptions{})
+
+	require.ErrorIs(t, builder.TriggerRebuild(25), ErrNoActiveBuild)
+
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.Equal(t, int32(1), atomic.LoadInt32(&testEthService.built))
+
+	// The rebuild runs before the next resubmission a second later
+	require.NoError(t, builder.TriggerRebuild(25))
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&testEthService.built) == 2 }, 500*time.Millisecond, 10*time.Millisecond)
+
+	require.ErrorIs(t, builder.TriggerRebuild(26), ErrNoActiveBuild)
+
+	// Single-shot slots are not rebuilt
+	builder, _ = newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SingleShot: true})
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.ErrorIs(t, builder.TriggerRebuild(25), ErrNoActiveBuild)
+}
+
+func TestFirstSubmissionLatency(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+	)
+	handler := log.Root().GetHandler()
+	log.Root().SetHandler(log.FuncHandler(func(r *log.Record) error {
+		if r.Msg != "submitted the slot's first block" {
+			return nil
+		}
+		for i := 0; i+1 < len(r.Ctx); i += 2 {
+			if r.Ctx[i] == "since_attributes" {
+				mu.Lock()
+				latencies = append(latencies, r.Ctx[i+1].(time.Duration))
+				mu.Unlock()
+			}
+		}
+		return nil
+	}))
+	t.Cleanup(func() { log.Root().SetHandler(handler) })
+	recorded := func() []time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Duration(nil), latencies...)
+	}
+
+	// Recorded from the receipt of the attributes, once for the slot's submissions
+	testEthService := &flakyEthereumService{testEthereumService: *newTestEthereumService()}
+	builder, _ := newTestBuilderWithOptions(t, testEthService, BuilderOptions{})
+	received := time.Now()
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.Len(t, recorded(), 1)
+	require.LessOrEqual(t, recorded()[0], time.Since(received))
+
+	require.NoError(t, builder.TriggerRebuild(25))
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&testEthService.built) == 3 }, 2*time.Second, 10*time.Millisecond)
+	require.Len(t, recorded(), 1)
+
+	// Not recorded for the builds without attributes
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SingleShot: true})
+	require.NoError(t, builder.buildForAttributes(newTestAttributes(26), time.Time{}, nil))
+	require.NotNil(t, testRelay.submittedMsg)
+	require.Len(t, recorded(), 1)
+}
+
+type syncingEthereumService struct {
+	testEthereumService
+	syncedAt time.Time
+	built    int32
+}
+
+func (s *syncingEthereumService) Synced() bool { return time.Now().After(s.syncedAt) }
+
+func (s *syncingEthereumService) BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown) {
+	atomic.AddInt32(&s.built, 1)
+	return s.testEthereumService.BuildBlock(attrs)
+}
+
+func TestNotSyncedPolicy(t *testing.T) {
+	newSyncingService := func(syncIn time.Duration) *syncingEthereumService {
+		return &syncingEthereumService{testEthereumService: *newTestEthereumService(), syncedAt: time.Now().Add(syncIn)}
+	}
+
+	// Fail immediately
+	builder, relay := newTestBuilderWithOptions(t, newSyncingService(time.Hour), BuilderOptions{})
+	start := time.Now()
+	err := builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrNotSynced)
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+	require.Nil(t, relay.submittedMsg)
+
+	// Wait until synced
+	builder, relay = newTestBuilderWithOptions(t, newSyncingService(300*time.Millisecond), BuilderOptions{NotSyncedPolicy: NotSyncedWait, NotSyncedMaxWait: time.Second})
+	err = builder.OnPayloadAttribute(newTestAttributes(25))
+	require.NoError(t, err)
+	require.NotNil(t, relay.submittedMsg)
+
+	// Wait is bounded
+	builder, relay = newTestBuilderWithOptions(t, newSyncingService(time.Hour), BuilderOptions{NotSyncedPolicy: NotSyncedWait, NotSyncedMaxWait: 200 * time.Millisecond})
+	start = time.Now()
+	err = builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrNotSynced)
+	require.Less(t, time.Since(start), time.Second)
+	require.Nil(t, relay.submittedMsg)
+
+	// Wait is bounded by the slot deadline
+	builder, _ = newTestBuilderWithOptions(t, newSyncingService(time.Hour), BuilderOptions{NotSyncedPolicy: NotSyncedWait, NotSyncedMaxWait: time.Hour})
+	builder.secondsPerSlot = 1
+	start = time.Now()
+	err = builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrNotSynced)
+	require.Less(t, time.Since(start), 2*time.Second)
+
+	// Fall back to the synced EL
+	primary, fallback := newSyncingService(time.Hour), newSyncingService(0)
+	builder, relay = newTestBuilderWithOptions(t, primary, BuilderOptions{NotSyncedPolicy: NotSyncedFallback, FallbackEthService: fallback})
+	err = builder.OnPayloadAttribute(newTestAttributes(25))
+	require.NoError(t, err)
+	require.NotNil(t, relay.submittedMsg)
+	require.Equal(t, int32(0), atomic.LoadInt32(&primary.built))
+	require.Equal(t, int32(1), atomic.LoadInt32(&fallback.built))
+
+	// Fallback not synced either
+	builder, _ = newTestBuilderWithOptions(t, newSyncingService(time.Hour), BuilderOptions{NotSyncedPolicy: NotSyncedFallback, FallbackEthService: newSyncingService(time.Hour)})
+	err = builder.OnPayloadAttribute(newTestAttributes(25))
+	require.ErrorIs(t, err, ErrNotSynced)
+}
+
+func TestParseNotSyncedPolicy(t *testing.T) {
+	policy, err := ParseNotSyncedPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, NotSyncedFail, policy)
+
+	policy, err = ParseNotSyncedPolicy("wait")
+	require.NoError(t, err)
+	require.Equal(t, NotSyncedWait, policy)
+
+	_, err = ParseNotSyncedPolicy("retry")
+	require.Error(t, err)
+}
+
+func TestLogFeeBreakdown(t *testing.T) {
+	var txCounts []int
+	logger := log.New()
+	logger.SetHandler(log.FuncHandler(func(r *log.Record) error {
+		for i := 0; i+1 < len(r.Ctx); i += 2 {
+			if r.Ctx[i] == "txs" {
+				txCounts = append(txCounts, r.Ctx[i+1].(int))
+			}
+		}
+		return nil
+	}))
+
+	txs := types.Transactions{types.NewTransaction(0, common.Address{}, nil, 21000, nil, nil), types.NewTransaction(1, common.Address{}, nil, 21000, nil, nil)}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10)}).WithBody(txs, nil)
+	block.Profit = big.NewInt(10)
+	breakdown := &ProfitBreakdown{BaseFeeBurned: new(big.Int), PaymentIndex: 1}
+
+	// Only the proposer payment is left out of the count, a profitable block may be paid by the EL without one
+	logFeeBreakdown(logger, breakdown, block)
+	breakdown.PaymentIndex = -1
+	logFeeBreakdown(logger, breakdown, block)
+	require.Equal(t, []int{1, 2}, txCounts)
 }
@@ -0,0 +1,206 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrBundleNotIntact is returned for the built blocks including part of a bundle or its transactions out of order
+var ErrBundleNotIntact = errors.New("bundle not included intact")
+
+// BundleHint is a searcher bundle the builder tries to include atomically: the block includes either all of its
+// transactions, in order, or none of them.
+type BundleHint struct {
+	// Txs are the bundle's RLP encoded signed transactions, in order
+	Txs []hexutil.Bytes `json:"txs"`
+	// Value is what the bundle pays the builder, the most valuable of the conflicting bundles is kept
+	Value *hexutil.Big `json:"value,omitempty"`
+}
+
+type txNonce struct {
+	sender common.Address
+	nonce  uint64
+}
+
+type bundle struct {
+	txs    types.Transactions
+	nonces []txNonce
+	value  *big.Int
+}
+
+// bundleSet is the bundles of a build, without conflicts.
+type bundleSet struct {
+	bundles []*bundle
+}
+
+// newBundleSet decodes the build parameters' bundles, nil if they have none. The bundles not decoding are dropped, as
+// are the bundles conflicting with a more valuable one.
+func newBundleSet(params *BuildParams, signer types.Signer) *bundleSet {
+	if params == nil || len(params.Bundles) == 0 {
+		return nil
+	}
+
+	var bundles []*bundle
+	for i, hint := range params.Bundles {
+		decoded, err := decodeBundle(hint, signer)
+		if err != nil {
+			log.Warn("dropping invalid bundle", "bundle", i, "err", err)
+			continue
+		}
+		bundles = append(bundles, decoded)
+	}
+	bundles = resolveBundleConflicts(bundles)
+	if len(bundles) == 0 {
+		return nil
+	}
+	return &bundleSet{bundles: bundles}
+}
+
+func decodeBundle(hint BundleHint, signer types.Signer) (*bundle, error) {
+	if len(hint.Txs) == 0 {
+		return nil, errors.New("empty bundle")
+	}
+
+	decoded := &bundle{value: new(big.Int)}
+	if hint.Value != nil {
+		decoded.value = hint.Value.ToInt()
+	}
+	for i, encoded := range hint.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(encoded); err != nil {
+			return nil, fmt.Errorf("could not decode transaction %d: %w", i, err)
+		}
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("could not recover the sender of transaction %d (%s): %w", i, tx.Hash(), err)
+		}
+		decoded.txs = append(decoded.txs, tx)
+		decoded.nonces = append(decoded.nonces, txNonce{sender: sender, nonce: tx.Nonce()})
+	}
+	return decoded, nil
+}
+
+// resolveBundleConflicts keeps the most valuable of the bundles sharing a transaction or a sender's nonce, the
+// earlier bundle on a tie. The kept bundles stay in their order.
+func resolveBundleConflicts(bundles []*bundle) []*bundle {
+	byValue := make([]int, len(bundles))
+	for i := range byValue {
+		byValue[i] = i
+	}
+	sort.SliceStable(byValue, func(i, j int) bool {
+		return bundles[byValue[i]].value.Cmp(bundles[byValue[j]].value) > 0
+	})
+
+	kept := make([]bool, len(bundles))
+	txs := make(map[common.Hash]struct{})
+	nonces := make(map[txNonce]struct{})
+	for _, i := range byValue {
+		conflicts := false
+		for j, tx := range bundles[i].txs {
+			_, sameTx := txs[tx.Hash()]
+			_, sameNonce := nonces[bundles[i].nonces[j]]
+			if sameTx || sameNonce {
+				conflicts = true
+				break
+			}
+		}
+		if conflicts {
+			log.Debug("dropping bundle conflicting with a more valuable one", "value", bundles[i].value)
+			continue
+		}
+		kept[i] = true
+		for j, tx := range bundles[i].txs {
+			txs[tx.Hash()] = struct{}{}
+			nonces[bundles[i].nonces[j]] = struct{}{}
+		}
+	}
+
+	resolved := make([]*bundle, 0, len(bundles))
+	for i, b := range bundles {
+		if kept[i] {
+			resolved = append(resolved, b)
+		}
+	}
+	return resolved
+}
+
+// filter returns the set without the bundles for which drop returns an error, logged with the error, nil for a nil
+// set or if no bundle is left.
+func (s *bundleSet) filter(drop func(b *bundle) error) *bundleSet {
+	if s == nil {
+		return nil
+	}
+	kept := make([]*bundle, 0, len(s.bundles))
+	for i, b := range s.bundles {
+		if err := drop(b); err != nil {
+			log.Debug("dropping bundle", "bundle", i, "err", err)
+			continue
+		}
+		kept = append(kept, b)
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return &bundleSet{bundles: kept}
+}
+
+// transactions returns the transactions of each bundle, in order, nil for a nil set.
+func (s *bundleSet) transactions() []types.Transactions {
+	if s == nil {
+		return nil
+	}
+	txs := make([]types.Transactions, len(s.bundles))
+	for i, b := range s.bundles {
+		txs[i] = b.txs
+	}
+	return txs
+}
+
+// hashes returns the hashes of the bundles' transactions, nil for a nil set.
+func (s *bundleSet) hashes() map[common.Hash]struct{} {
+	if s == nil {
+		return nil
+	}
+	hashes := make(map[common.Hash]struct{})
+	for _, b := range s.bundles {
+		for _, tx := range b.txs {
+			hashes[tx.Hash()] = struct{}{}
+		}
+	}
+	return hashes
+}
+
+// check returns ErrBundleNotIntact if the block includes some of a bundle's transactions but not all, or not
+// contiguously in the bundle's order. The bundles not included at all are fine, the miner leaves out the bundles
+// failing or reverting.
+func (s *bundleSet) check(block *types.Block) error {
+	positions := make(map[common.Hash]int, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		positions[tx.Hash()] = i
+	}
+
+	for i, b := range s.bundles {
+		included, last := 0, -1
+		for _, tx := range b.txs {
+			position, found := positions[tx.Hash()]
+			if !found {
+				continue
+			}
+			if last >= 0 && position != last+1 {
+				return fmt.Errorf("%w: bundle %d transaction %s out of order", ErrBundleNotIntact, i, tx.Hash())
+			}
+			included, last = included+1, position
+		}
+		if included > 0 && included < len(b.txs) {
+			return fmt.Errorf("%w: bundle %d has %d of its %d transactions in the block", ErrBundleNotIntact, i, included, len(b.txs))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package builder
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleSet(t *testing.T) {
+	require.Nil(t, newBundleSet(nil, nil))
+	require.Nil(t, newBundleSet(&BuildParams{MaxTransactions: 1}, nil))
+
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	keyA, _ := crypto.GenerateKey()
+	keyB, _ := crypto.GenerateKey()
+	signTx := func(key *ecdsa.PrivateKey, nonce uint64, tip int64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: nonce, GasTipCap: big.NewInt(tip), GasFeeCap: big.NewInt(100)})
+	}
+	hint := func(value int64, txs ...*types.Transaction) BundleHint {
+		hint := BundleHint{Value: (*hexutil.Big)(big.NewInt(value))}
+		for _, tx := range txs {
+			encoded, err := tx.MarshalBinary()
+			require.NoError(t, err)
+			hint.Txs = append(hint.Txs, encoded)
+		}
+		return hint
+	}
+	hashes := func(txs types.Transactions) []common.Hash {
+		hashes := make([]common.Hash, len(txs))
+		for i, tx := range txs {
+			hashes[i] = tx.Hash()
+		}
+		return hashes
+	}
+	txA0, txA1, txB0 := signTx(keyA, 0, 1), signTx(keyA, 1, 1), signTx(keyB, 0, 1)
+	// Conflicts with the first bundle on account A's nonce 1, the most valuable wins
+	txA1Alt := signTx(keyA, 1, 2)
+
+	bundles := newBundleSet(&BuildParams{Bundles: []BundleHint{
+		hint(10, txA1, txB0),
+		hint(20, txA1Alt),
+		{Txs: []hexutil.Bytes{{0x01}}},
+		{},
+	}}, signer)
+	require.Len(t, bundles.bundles, 1)
+	require.Equal(t, hashes(types.Transactions{txA1Alt}), hashes(bundles.bundles[0].txs))
+
+	// On a tie the earlier bundle wins
+	bundles = newBundleSet(&BuildParams{Bundles: []BundleHint{hint(10, txA1, txB0), hint(10, txB0)}}, signer)
+	require.Len(t, bundles.bundles, 1)
+	require.Equal(t, hashes(types.Transactions{txA1, txB0}), hashes(bundles.bundles[0].txs))
+
+	require.Len(t, bundles.transactions(), 1)
+	require.Equal(t, hashes(types.Transactions{txA1, txB0}), hashes(bundles.transactions()[0]))
+	require.Nil(t, (*bundleSet)(nil).transactions())
+
+	block := types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{txA0, txA1, txB0}, nil)
+	require.NoError(t, bundles.check(block))
+
+	// A bundle not included at all is fine, not a partial one or out of order
+	block = types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{txA0}, nil)
+	require.NoError(t, bundles.check(block))
+
+	block = types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{txA0, txA1}, nil)
+	require.ErrorIs(t, bundles.check(block), ErrBundleNotIntact)
+
+	block = types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{txB0, txA0, txA1}, nil)
+	require.ErrorIs(t, bundles.check(block), ErrBundleNotIntact)
+
+	// Nor one split up by another transaction
+	block = types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{txA1, txA0, txB0}, nil)
+	require.ErrorIs(t, bundles.check(block), ErrBundleNotIntact)
+}
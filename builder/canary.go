@@ -0,0 +1,178 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// ErrCanariesDisabled is returned for the canaries when the builder was not started with CanarySubmissions
+var ErrCanariesDisabled = errors.New("canary submissions are disabled")
+
+// ErrCanaryConflictsWithBids is returned for the canaries for a slot the builder bids in, where the canary could
+// replace the builder's bid
+var ErrCanaryConflictsWithBids = errors.New("canary conflicts with the slot's bids")
+
+// CanaryResult is the outcome of a canary submission.
+type CanaryResult struct {
+	Relay     string          `json:"relay"`
+	Slot      uint64          `json:"slot"`
+	BlockHash boostTypes.Hash `json:"blockHash"`
+	Value     *big.Int        `json:"value"`
+	// Accepted is whether the relay accepted the canary, else Error is the relay's error
+	Accepted bool   `json:"accepted"`
+	Error    string `json:"error,omitempty"`
+}
+
+type canaryKey struct{}
+
+func withCanary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, canaryKey{}, true)
+}
+
+// isCanary reports whether the context is a canary submission's.
+func isCanary(ctx context.Context) bool {
+	canary, _ := ctx.Value(canaryKey{}).(bool)
+	return canary
+}
+
+// SubmitCanary builds a minimal block for the next slot on the EL head and submits it to the relay, identified by
+// its name in Relays, to check the relay accepts the builder's submissions. The relays have no endpoint validating a
+// submission outside of the auction, so the canary is a real bid of the block's profit for the next slot, and it is
+// refused unless the builder was started with CanarySubmissions. It is also refused once the slot's payload
+// attributes were received or the builder submitted for the slot, as the relay would take the canary for the
+// builder's bid. The canaries are not stored for GetPayload, retried, counted in the relays' status or sent to the
+// submission subscribers, and they are sent to disabled relays.
+//
+// The result's Error is set if the relay did not accept the canary, the error is returned if the canary could not
+// be submitted.
+func (b *Builder) SubmitCanary(ctx context.Context, id string) (CanaryResult, error) {
+	if !b.canarySubmissions {
+		return CanaryResult{}, ErrCanariesDisabled
+	}
+	relay, err := b.relayByName(id)
+	if err != nil {
+		return CanaryResult{}, err
+	}
+	if b.genesisTime == 0 {
+		return CanaryResult{}, errors.New("canaries require the genesis time from the beacon node")
+	}
+	slot, _ := b.nextSlotStart(time.Now())
+	if b.receivedAttributes(slot) || b.history.submitted(slot) {
+		return CanaryResult{}, fmt.Errorf("%w: slot %d", ErrCanaryConflictsWithBids, slot)
+	}
+
+	req, value, err := b.buildCanary(ctx, relay, slot)
+	if err != nil {
+		return CanaryResult{}, err
+	}
+	result := CanaryResult{Relay: id, Slot: slot, BlockHash: req.ExecutionPayload.BlockHash, Value: value}
+
+	submissionID := b.newSubmissionID()
+	logger := log.New("submission_id", submissionID, "slot", slot, "relay", id)
+	ctx = withCanary(withSubmissionID(ctx, submissionID))
+	err = relay.SubmitBlock(ctx, req)
+
+	canarySubmissionsCounter.Inc(1)
+	if err != nil {
+		canaryRejectedCounter.Inc(1)
+		result.Error = err.Error()
+		logger.Warn("relay did not accept the canary", "err", err, "block_hash", result.BlockHash)
+		return result, nil
+	}
+	result.Accepted = true
+	logger.Info("relay accepted the canary", "block_hash", result.BlockHash, "value", result.Value)
+	return result, nil
+}
+
+// buildCanary builds the canary for the slot on the EL head with a single transaction from the txpool at most, and
+// signs it with the relay's key. The bid is the block's profit, without the bid value policy or the relay's fee.
+func (b *Builder) buildCanary(ctx context.Context, relay IRelay, slot uint64) (*boostTypes.BuilderSubmitBlockRequest, *big.Int, error) {
+	vd, err := relay.GetValidatorForSlot(slot)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get the slot's validator: %w", err)
+	}
+	proposerPubkey, err := boostTypes.HexToPubkey(string(vd.Pubkey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	head := b.eth.CurrentBlock()
+	if head == nil {
+		return nil, nil, errors.New("no head block")
+	}
+	randao, err := b.prevRandao(ctx, slot, head)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attrs := &BuilderPayloadAttributes{
+		Timestamp:             hexutil.Uint64(b.genesisTime + slot*b.secondsPerSlot),
+		Random:                randao,
+		SuggestedFeeRecipient: [20]byte(vd.FeeRecipient),
+		Slot:                  slot,
+		HeadHash:              head.Hash(),
+		GasLimit:              b.gasLimitForSlot(vd.GasLimit, head.GasLimit(), slot),
+		BuildParams:           &BuildParams{MaxTransactions: 1},
+	}
+	executableData, block, _ := b.eth.BuildBlock(attrs)
+	if block == nil {
+		return nil, nil, errors.New("could not build the canary")
+	}
+
+	payload, err := executableDataToExecutionPayload(executableData)
+	if err != nil {
+		return nil, nil, err
+	}
+	profit := block.Profit
+	if profit == nil {
+		profit = new(big.Int)
+	}
+	value, err := bidValueToU256(profit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := b.keyFor(relay)
+	msg := &boostTypes.BidTrace{
+		Slot:                 slot,
+		ParentHash:           payload.ParentHash,
+		BlockHash:            payload.BlockHash,
+		BuilderPubkey:        key.pk,
+		ProposerPubkey:       proposerPubkey,
+		ProposerFeeRecipient: vd.FeeRecipient,
+		GasLimit:             executableData.GasLimit,
+		GasUsed:              executableData.GasUsed,
+		Value:                *value,
+	}
+	signature, err := boostTypes.SignMessage(msg, b.builderSigningDomain, key.sk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &boostTypes.BuilderSubmitBlockRequest{Signature: signature, Message: msg, ExecutionPayload: payload}, profit, nil
+}
+
+// relayByName returns the relay with the name in Relays.
+func (b *Builder) relayByName(id string) (IRelay, error) {
+	for _, relay := range b.relays() {
+		if name, _ := relayIdentity(relay); name == id {
+			return relay, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownRelay, id)
+}
+
+// submitted reports whether blocks were submitted for the slot.
+func (h *submissionHistory) submitted(slot uint64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, found := h.slots[slot]
+	return found
+}
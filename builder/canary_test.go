@@ -0,0 +1,52 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitCanary(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	builder.genesisTime = 1000
+
+	// The canaries are real bids, refused unless enabled
+	_, err := builder.SubmitCanary(context.Background(), "unknown")
+	require.ErrorIs(t, err, ErrCanariesDisabled)
+	require.Nil(t, testRelay.submittedMsg)
+
+	builder, testRelay = newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{CanarySubmissions: true})
+	builder.genesisTime = 1000
+
+	_, err = builder.SubmitCanary(context.Background(), "missing")
+	require.ErrorIs(t, err, ErrUnknownRelay)
+
+	api := NewBuilderAPI(builder)
+	result, err := api.SubmitCanary(context.Background(), "unknown")
+	require.NoError(t, err)
+	require.True(t, result.Accepted)
+	require.Empty(t, result.Error)
+	require.NotNil(t, testRelay.submittedMsg)
+	require.Equal(t, result.Slot, testRelay.submittedMsg.Message.Slot)
+	require.Equal(t, result.BlockHash, testRelay.submittedMsg.Message.BlockHash)
+	require.NotEmpty(t, testRelay.submissionID)
+
+	// The canaries are not stored or recorded as the builder's submissions
+	require.False(t, builder.history.submitted(result.Slot))
+	require.Zero(t, builder.payloads.Len())
+
+	testRelay.submitErr = errors.New("rejected")
+	result, err = builder.SubmitCanary(context.Background(), "unknown")
+	require.NoError(t, err)
+	require.False(t, result.Accepted)
+	require.Equal(t, "rejected", result.Error)
+
+	// Not sent for a slot the builder builds
+	testRelay.submitErr, testRelay.submittedMsg = nil, nil
+	builder.markReceivedAttributes(result.Slot)
+	_, err = builder.SubmitCanary(context.Background(), "unknown")
+	require.ErrorIs(t, err, ErrCanaryConflictsWithBids)
+	require.Nil(t, testRelay.submittedMsg)
+}
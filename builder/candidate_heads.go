@@ -0,0 +1,154 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/beacon"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// headCandidate is a head the slot's blocks are built on, with the attributes of the builds on it.
+type headCandidate struct {
+	attrs  *BuilderPayloadAttributes
+	parent *types.Block
+}
+
+// builtBlock is a block built on one of the slot's heads.
+type builtBlock struct {
+	head            headCandidate
+	executableData  *beacon.ExecutableDataV1
+	block           *types.Block
+	profitBreakdown *ProfitBreakdown
+}
+
+func (b *builtBlock) profit() *big.Int {
+	if b.block.Profit == nil {
+		return new(big.Int)
+	}
+	return b.block.Profit
+}
+
+// candidateHeads returns the heads built on besides the attributes' head: their candidate heads known to the EL, in
+// order and up to the builder's maximum. The attributes of each candidate are the attributes' with the candidate as
+// the head, and the gas limit and timestamp chosen for the candidate's parent.
+func (b *Builder) candidateHeads(eth IEthereumService, attrs *BuilderPayloadAttributes, validatorGasLimit uint64) []headCandidate {
+	if b.maxCandidateHeads < 2 || len(attrs.CandidateHeads) == 0 {
+		return nil
+	}
+
+	seen := map[common.Hash]struct{}{attrs.HeadHash: {}}
+	var candidates []headCandidate
+	for _, head := range attrs.CandidateHeads {
+		if len(candidates)+1 >= b.maxCandidateHeads {
+			log.Debug("candidate heads over the maximum not built on", "slot", attrs.Slot, "max", b.maxCandidateHeads, "candidates", len(attrs.CandidateHeads))
+			break
+		}
+		if _, found := seen[head]; found || head == (common.Hash{}) {
+			continue
+		}
+		seen[head] = struct{}{}
+
+		parent := eth.GetBlockByHash(head)
+		if parent == nil {
+			log.Info("candidate head not found in blocktree", "slot", attrs.Slot, "head_hash", head)
+			continue
+		}
+		candidateAttrs := *attrs
+		candidateAttrs.HeadHash = head
+		candidateAttrs.GasLimit = b.gasLimitForSlot(validatorGasLimit, parent.GasLimit(), attrs.Slot)
+		reportCandidateGasLimit(attrs.Slot, head, validatorGasLimit, parent.GasLimit(), candidateAttrs.GasLimit)
+		if err := b.chooseTimestamp(&candidateAttrs, parent.Time()); err != nil {
+			log.Info("not building on candidate head", "err", err, "slot", attrs.Slot, "head_hash", head)
+			continue
+		}
+		candidates = append(candidates, headCandidate{attrs: &candidateAttrs, parent: parent})
+	}
+	return candidates
+}
+
+// buildOnHeads builds a block on every head concurrently and returns the valid blocks, one per head in the heads'
+// order. The relays index the bids by slot, parent and proposer and only deliver the bid on the parent the proposer
+// asks for, so each head's block is submitted for its own parent instead of only the most profitable block. Returns
+// the first head's error if no block is valid.
+func (b *Builder) buildOnHeads(eth IEthereumService, heads []headCandidate, deadline time.Time, proposerIndex uint64) ([]*builtBlock, error) {
+	if len(heads) == 1 {
+		built, err := b.buildOnHead(eth, heads[0], deadline, proposerIndex)
+		if err != nil {
+			return nil, err
+		}
+		return []*builtBlock{built}, nil
+	}
+
+	built := make([]*builtBlock, len(heads))
+	errs := make([]error, len(heads))
+	var wg sync.WaitGroup
+	for i := range heads {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			built[i], errs[i] = b.buildOnHead(eth, heads[i], deadline, proposerIndex)
+		}(i)
+	}
+	wg.Wait()
+
+	var valid []*builtBlock
+	for i := range heads {
+		if errs[i] != nil {
+			continue
+		}
+		if i > 0 {
+			candidateHeadBlocksCounter.Inc(1)
+			log.Info("submitting a block built on a candidate head", "slot", built[i].head.attrs.Slot, "block_hash", built[i].block.Hash(), "head_hash", built[i].head.attrs.HeadHash, "attributes_head_hash", heads[0].attrs.HeadHash, "profit", built[i].profit())
+		}
+		valid = append(valid, built[i])
+	}
+	if len(valid) == 0 {
+		return nil, errs[0]
+	}
+	return valid, nil
+}
+
+// mostProfitable returns the most profitable of the blocks, the earlier block on a tie.
+func mostProfitable(blocks []*builtBlock) *builtBlock {
+	best := blocks[0]
+	for _, built := range blocks[1:] {
+		if built.profit().Cmp(best.profit()) > 0 {
+			best = built
+		}
+	}
+	return best
+}
+
+// buildOnHead builds a block on the head, dropping it unless it is a child of the head built before the deadline.
+func (b *Builder) buildOnHead(eth IEthereumService, head headCandidate, deadline time.Time, proposerIndex uint64) (*builtBlock, error) {
+	attrs := head.attrs
+	executableData, block, profitBreakdown, stats, err := b.buildBlockWithRetry(eth, attrs, deadline)
+	if err != nil {
+		log.Error("could not build block", "err", err, "slot", attrs.Slot, "head_hash", attrs.HeadHash)
+		return nil, err
+	}
+	elBuildTimer.Update(stats.Total)
+	elBuildTimerByTxs(len(block.Transactions())).Update(stats.Total)
+	log.Info("built block", "slot", attrs.Slot, "block_hash", block.Hash(), "proposer_index", proposerIndex, "el_build_duration", stats.Total, "el_seal_duration", stats.Build, "txs", len(block.Transactions()))
+
+	if executableData.ParentHash != attrs.HeadHash {
+		log.Error("dropping block built on another parent than the head", "slot", attrs.Slot, "block_hash", block.Hash(), "parent_hash", executableData.ParentHash, "head_hash", attrs.HeadHash)
+		return nil, fmt.Errorf("%w: built on %s instead of %s", ErrParentMismatch, executableData.ParentHash, attrs.HeadHash)
+	}
+	if executableData.Number != head.parent.NumberU64()+1 {
+		log.Error("dropping block whose number does not follow the parent's", "slot", attrs.Slot, "block_hash", block.Hash(), "block_number", executableData.Number, "parent_number", head.parent.NumberU64())
+		return nil, fmt.Errorf("%w: block %d on parent %d", ErrBlockNumberMismatch, executableData.Number, head.parent.NumberU64())
+	}
+
+	if !time.Now().Before(deadline) {
+		log.Info("dropping block built past the slot deadline", "slot", attrs.Slot, "block_hash", block.Hash())
+		return nil, errors.New("block built past the slot deadline")
+	}
+	return &builtBlock{head: head, executableData: executableData, block: block, profitBreakdown: profitBreakdown}, nil
+}
@@ -0,0 +1,122 @@
+package builder
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/beacon"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+// headsEthereumService builds blocks of the heads' profits on the heads, the heads it has no profit for are unknown
+type headsEthereumService struct {
+	testEthereumService
+	profits map[common.Hash]int64
+	// misbuilt are the heads built on another parent
+	misbuilt map[common.Hash]bool
+}
+
+func (s *headsEthereumService) BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown) {
+	executableData := newTestExecutableData()
+	executableData.ParentHash = attrs.HeadHash
+	if s.misbuilt[attrs.HeadHash] {
+		executableData.ParentHash = common.Hash{0xba, 0xd0}
+	}
+	executableData.BlockHash = common.Hash{0x09, attrs.HeadHash[0]}
+	executableData.Number = 10
+	return executableData, newTestBlock(s.profits[attrs.HeadHash]), nil
+}
+
+func (s *headsEthereumService) GetBlockByHash(hash common.Hash) *types.Block {
+	if _, found := s.profits[hash]; !found {
+		return nil
+	}
+	return newTestBlock(0)
+}
+
+// parentsRelay records the parents of the submitted blocks
+type parentsRelay struct {
+	*testRelay
+	parents []common.Hash
+}
+
+func (r *parentsRelay) SubmitBlock(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) error {
+	r.parents = append(r.parents, common.Hash(msg.Message.ParentHash))
+	return r.testRelay.SubmitBlock(ctx, msg)
+}
+
+func TestBuildOnCandidateHeads(t *testing.T) {
+	head, richer, unknown, misbuilt := common.Hash{0x02, 0x03}, common.Hash{0x11}, common.Hash{0x12}, common.Hash{0x13}
+	build := func(opts BuilderOptions, profits map[common.Hash]int64, candidates ...common.Hash) (*Builder, *parentsRelay) {
+		opts.SingleShot = true
+		eth := &headsEthereumService{testEthereumService: *newTestEthereumService(), profits: profits, misbuilt: map[common.Hash]bool{misbuilt: true}}
+		builder, testRelay := newTestBuilderWithOptions(t, eth, opts)
+		relay := &parentsRelay{testRelay: testRelay}
+		builder.relay = relay
+		attrs := newTestAttributes(25)
+		attrs.CandidateHeads = candidates
+		require.NoError(t, builder.OnPayloadAttribute(attrs))
+		return builder, relay
+	}
+
+	// A block is submitted for every head with a valid block, the attributes' head first. The unknown and misbuilt
+	// heads are skipped
+	profits := map[common.Hash]int64{head: 10, richer: 30, misbuilt: 50}
+	builder, relay := build(BuilderOptions{MaxCandidateHeads: 4}, profits, unknown, misbuilt, head, richer)
+	require.Equal(t, []common.Hash{head, richer}, relay.parents)
+
+	// The payloads of every head's block are stored for the proposer's header
+	for _, parent := range []common.Hash{head, richer} {
+		_, found := builder.payloads.Get(boostTypes.Hash{0x09, parent[0]})
+		require.True(t, found, parent)
+	}
+
+	// Only the attributes' head is built on by default, and up to the maximum of heads
+	_, relay = build(BuilderOptions{}, profits, richer)
+	require.Equal(t, []common.Hash{head}, relay.parents)
+	_, relay = build(BuilderOptions{MaxCandidateHeads: 2}, profits, misbuilt, richer)
+	require.Equal(t, []common.Hash{head}, relay.parents)
+
+	// The lazy builds compare the market's top bid with the most profitable head's block, the earlier block on a tie
+	heads := []*builtBlock{{block: newTestBlock(10)}, {block: newTestBlock(30)}, {block: newTestBlock(30)}}
+	require.Same(t, heads[1], mostProfitable(heads))
+}
+
+func TestCandidateHeadsGasLimitReport(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		reports = make(map[common.Hash]log.Lvl)
+	)
+	handler := log.Root().GetHandler()
+	log.Root().SetHandler(log.FuncHandler(func(r *log.Record) error {
+		if r.Msg != "chose slot gas limit" && r.Msg != "chose candidate head gas limit" {
+			return nil
+		}
+		for i := 0; i+1 < len(r.Ctx); i += 2 {
+			if r.Ctx[i] == "head_hash" {
+				mu.Lock()
+				reports[r.Ctx[i+1].(common.Hash)] = r.Lvl
+				mu.Unlock()
+			}
+		}
+		return nil
+	}))
+	t.Cleanup(func() { log.Root().SetHandler(handler) })
+
+	// The slot's gas limit is reported for the attributes' head, the candidate heads' are only logged at debug level
+	head, candidate := common.Hash{0x02, 0x03}, common.Hash{0x11}
+	eth := &headsEthereumService{testEthereumService: *newTestEthereumService(), profits: map[common.Hash]int64{head: 10, candidate: 30}}
+	builder, _ := newTestBuilderWithOptions(t, eth, BuilderOptions{SingleShot: true, MaxCandidateHeads: 2})
+	attrs := newTestAttributes(25)
+	attrs.CandidateHeads = []common.Hash{candidate}
+	require.NoError(t, builder.OnPayloadAttribute(attrs))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, map[common.Hash]log.Lvl{head: log.LvlInfo, candidate: log.LvlDebug}, reports)
+}
@@ -0,0 +1,107 @@
+package builder
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var ErrCircuitOpen = errors.New("relay circuit breaker open")
+
+const defaultCircuitBreakerCooldown = 2 * time.Second
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker stops relay submissions after threshold consecutive failures. After the cooldown it half-opens,
+// calling onHalfOpen, and the next submission's result closes or re-opens it. It never opens if the threshold is 0.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	state     circuitState
+	failures  int
+	threshold int
+	cooldown  time.Duration
+
+	onHalfOpen func()
+}
+
+func NewCircuitBreaker(threshold int, cooldown time.Duration, onHalfOpen func()) *CircuitBreaker {
+	if cooldown <= 0 {
+		cooldown = defaultCircuitBreakerCooldown
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, onHalfOpen: onHalfOpen}
+}
+
+// Allow returns false while the breaker is open.
+func (c *CircuitBreaker) Allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state != circuitOpen
+}
+
+func (c *CircuitBreaker) Success() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != circuitClosed {
+		log.Info("relay circuit breaker closed", "from", c.state)
+	}
+	c.state = circuitClosed
+	c.failures = 0
+}
+
+func (c *CircuitBreaker) Failure() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures++
+	if c.threshold <= 0 {
+		return
+	}
+	if c.state == circuitHalfOpen || (c.state == circuitClosed && c.failures >= c.threshold) {
+		log.Warn("relay circuit breaker opened", "from", c.state, "failures", c.failures, "cooldown", c.cooldown)
+		c.state = circuitOpen
+		time.AfterFunc(c.cooldown, c.halfOpen)
+	}
+}
+
+func (c *CircuitBreaker) State() circuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state
+}
+
+func (c *CircuitBreaker) halfOpen() {
+	c.mu.Lock()
+	if c.state != circuitOpen {
+		c.mu.Unlock()
+		return
+	}
+	log.Info("relay circuit breaker half-open")
+	c.state = circuitHalfOpen
+	c.mu.Unlock()
+
+	if c.onHalfOpen != nil {
+		c.onHalfOpen()
+	}
+}
@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	halfOpened := make(chan struct{}, 1)
+	breaker := NewCircuitBreaker(2, 50*time.Millisecond, func() { halfOpened <- struct{}{} })
+
+	breaker.Failure()
+	require.True(t, breaker.Allow())
+
+	// A success resets the consecutive failures
+	breaker.Success()
+	breaker.Failure()
+	require.True(t, breaker.Allow())
+
+	breaker.Failure()
+	require.False(t, breaker.Allow())
+	require.Equal(t, circuitOpen, breaker.State())
+
+	select {
+	case <-halfOpened:
+	case <-time.After(time.Second):
+		t.Fatal("circuit breaker did not half-open")
+	}
+	require.True(t, breaker.Allow())
+	require.Equal(t, circuitHalfOpen, breaker.State())
+
+	// A single failure while half-open re-opens
+	breaker.Failure()
+	require.Equal(t, circuitOpen, breaker.State())
+
+	<-halfOpened
+	breaker.Success()
+	require.Equal(t, circuitClosed, breaker.State())
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	breaker := NewCircuitBreaker(0, 0, nil)
+	for i := 0; i < 10; i++ {
+		breaker.Failure()
+	}
+	require.True(t, breaker.Allow())
+	require.Equal(t, circuitClosed, breaker.State())
+}
@@ -0,0 +1,46 @@
+package builder
+
+import (
+	"context"
+	"net/http"
+)
+
+// ClientVersionHeader carries the version of the EL which built the block to the relay, if enabled
+const ClientVersionHeader = "X-Builder-El-Version"
+
+type clientVersionKey struct{}
+
+func withClientVersion(ctx context.Context, clientVersion string) context.Context {
+	return context.WithValue(ctx, clientVersionKey{}, clientVersion)
+}
+
+// ClientVersionFromContext returns the version of the EL which built the submitted block, if any.
+func ClientVersionFromContext(ctx context.Context) (string, bool) {
+	clientVersion, ok := ctx.Value(clientVersionKey{}).(string)
+	return clientVersion, ok && clientVersion != ""
+}
+
+// submissionContext returns the context of the submission's requests to the relays.
+func submissionContext(submission *pendingSubmission) context.Context {
+	ctx := withSubmissionDeadline(withSubmissionID(context.Background(), submission.submissionID), submission.deadline)
+	if submission.proposerIndex != nil {
+		ctx = withProposerIndex(ctx, *submission.proposerIndex)
+	}
+	return withClientVersion(ctx, submission.clientVersion)
+}
+
+// clientVersionTransport sets the EL version header on requests made with a submission context.
+type clientVersionTransport struct {
+	base http.RoundTripper
+}
+
+func (t clientVersionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clientVersion, ok := ClientVersionFromContext(req.Context())
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set(ClientVersionHeader, clientVersion)
+	return t.base.RoundTrip(req)
+}
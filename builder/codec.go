@@ -0,0 +1,240 @@
+package builder
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+var ErrInvalidSSZ = errors.New("invalid ssz encoding")
+
+// Codec encodes the block submissions sent to the relay.
+type Codec interface {
+	// Encode returns the encoded submission and its content type
+	Encode(req *boostTypes.BuilderSubmitBlockRequest) ([]byte, string, error)
+	Decode(data []byte) (*boostTypes.BuilderSubmitBlockRequest, error)
+}
+
+// ParseCodec returns the codec by name: json (default) or ssz.
+func ParseCodec(name string) (Codec, error) {
+	switch name {
+	case "", "json":
+		return JSONCodec{}, nil
+	case "ssz":
+		return SSZCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown relay codec %q, expected json or ssz", name)
+	}
+}
+
+// JSONCodec encodes submissions as the relay API's JSON.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(req *boostTypes.BuilderSubmitBlockRequest) ([]byte, string, error) {
+	data, err := json.Marshal(req)
+	return data, "application/json", err
+}
+
+func (JSONCodec) Decode(data []byte) (*boostTypes.BuilderSubmitBlockRequest, error) {
+	req := new(boostTypes.BuilderSubmitBlockRequest)
+	if err := json.Unmarshal(data, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+const (
+	signatureSSZSize = 96
+	bidTraceSSZSize  = 236
+	// submitBlockRequestSSZFixedSize is the bid trace, execution payload offset and signature
+	submitBlockRequestSSZFixedSize = bidTraceSSZSize + 4 + signatureSSZSize
+	// executionPayloadSSZFixedSize is the fixed size fields and the extra data and transactions offsets
+	executionPayloadSSZFixedSize = 32 + 20 + 32 + 32 + 256 + 32 + 4*8 + 4 + 32 + 32 + 4
+
+	maxExtraDataSize   = 32
+	maxTransactions    = 1048576
+	maxTransactionSize = 1073741824
+)
+
+// SSZCodec encodes submissions as SSZ, with the fields in the order of the builder-specs SubmitBlockRequest
+// container: message, execution payload and signature.
+type SSZCodec struct{}
+
+func (SSZCodec) Encode(req *boostTypes.BuilderSubmitBlockRequest) ([]byte, string, error) {
+	if req.Message == nil || req.ExecutionPayload == nil {
+		return nil, "", fmt.Errorf("%w: missing message or execution payload", ErrInvalidSSZ)
+	}
+
+	buf := make([]byte, 0, submitBlockRequestSSZFixedSize+executionPayloadSSZSize(req.ExecutionPayload))
+	buf, err := req.Message.MarshalSSZTo(buf)
+	if err != nil {
+		return nil, "", err
+	}
+	buf = appendUint32(buf, submitBlockRequestSSZFixedSize)
+	buf = append(buf, req.Signature[:]...)
+
+	buf, err = marshalExecutionPayloadSSZ(buf, req.ExecutionPayload)
+	if err != nil {
+		return nil, "", err
+	}
+	return buf, "application/octet-stream", nil
+}
+
+func (SSZCodec) Decode(data []byte) (*boostTypes.BuilderSubmitBlockRequest, error) {
+	if len(data) < submitBlockRequestSSZFixedSize {
+		return nil, fmt.Errorf("%w: request of %d bytes", ErrInvalidSSZ, len(data))
+	}
+
+	req := &boostTypes.BuilderSubmitBlockRequest{Message: new(boostTypes.BidTrace)}
+	if err := req.Message.UnmarshalSSZ(data[:bidTraceSSZSize]); err != nil {
+		return nil, err
+	}
+	if offset := binary.LittleEndian.Uint32(data[bidTraceSSZSize:]); offset != submitBlockRequestSSZFixedSize {
+		return nil, fmt.Errorf("%w: execution payload offset %d", ErrInvalidSSZ, offset)
+	}
+	copy(req.Signature[:], data[bidTraceSSZSize+4:submitBlockRequestSSZFixedSize])
+
+	payload, err := unmarshalExecutionPayloadSSZ(data[submitBlockRequestSSZFixedSize:])
+	if err != nil {
+		return nil, err
+	}
+	req.ExecutionPayload = payload
+	return req, nil
+}
+
+func executionPayloadSSZSize(payload *boostTypes.ExecutionPayload) int {
+	size := executionPayloadSSZFixedSize + len(payload.ExtraData)
+	for _, tx := range payload.Transactions {
+		size += 4 + len(tx)
+	}
+	return size
+}
+
+func marshalExecutionPayloadSSZ(buf []byte, payload *boostTypes.ExecutionPayload) ([]byte, error) {
+	if len(payload.ExtraData) > maxExtraDataSize {
+		return nil, fmt.Errorf("%w: extra data of %d bytes", ErrInvalidSSZ, len(payload.ExtraData))
+	}
+	if len(payload.Transactions) > maxTransactions {
+		return nil, fmt.Errorf("%w: %d transactions", ErrInvalidSSZ, len(payload.Transactions))
+	}
+
+	buf = append(buf, payload.ParentHash[:]...)
+	buf = append(buf, payload.FeeRecipient[:]...)
+	buf = append(buf, payload.StateRoot[:]...)
+	buf = append(buf, payload.ReceiptsRoot[:]...)
+	buf = append(buf, payload.LogsBloom[:]...)
+	buf = append(buf, payload.Random[:]...)
+	buf = appendUint64(buf, payload.BlockNumber)
+	buf = appendUint64(buf, payload.GasLimit)
+	buf = appendUint64(buf, payload.GasUsed)
+	buf = appendUint64(buf, payload.Timestamp)
+	buf = appendUint32(buf, executionPayloadSSZFixedSize)
+	// U256Str is stored little-endian, as encoded in SSZ
+	buf = append(buf, payload.BaseFeePerGas[:]...)
+	buf = append(buf, payload.BlockHash[:]...)
+	buf = appendUint32(buf, uint32(executionPayloadSSZFixedSize+len(payload.ExtraData)))
+
+	buf = append(buf, payload.ExtraData...)
+
+	offset := 4 * len(payload.Transactions)
+	for _, tx := range payload.Transactions {
+		if len(tx) > maxTransactionSize {
+			return nil, fmt.Errorf("%w: transaction of %d bytes", ErrInvalidSSZ, len(tx))
+		}
+		buf = appendUint32(buf, uint32(offset))
+		offset += len(tx)
+	}
+	for _, tx := range payload.Transactions {
+		buf = append(buf, tx...)
+	}
+	return buf, nil
+}
+
+func unmarshalExecutionPayloadSSZ(data []byte) (*boostTypes.ExecutionPayload, error) {
+	if len(data) < executionPayloadSSZFixedSize {
+		return nil, fmt.Errorf("%w: execution payload of %d bytes", ErrInvalidSSZ, len(data))
+	}
+
+	payload := new(boostTypes.ExecutionPayload)
+	pos := 0
+	next := func(n int) []byte {
+		field := data[pos : pos+n]
+		pos += n
+		return field
+	}
+	copy(payload.ParentHash[:], next(32))
+	copy(payload.FeeRecipient[:], next(20))
+	copy(payload.StateRoot[:], next(32))
+	copy(payload.ReceiptsRoot[:], next(32))
+	copy(payload.LogsBloom[:], next(256))
+	copy(payload.Random[:], next(32))
+	payload.BlockNumber = binary.LittleEndian.Uint64(next(8))
+	payload.GasLimit = binary.LittleEndian.Uint64(next(8))
+	payload.GasUsed = binary.LittleEndian.Uint64(next(8))
+	payload.Timestamp = binary.LittleEndian.Uint64(next(8))
+	extraDataOffset := int(binary.LittleEndian.Uint32(next(4)))
+	copy(payload.BaseFeePerGas[:], next(32))
+	copy(payload.BlockHash[:], next(32))
+	transactionsOffset := int(binary.LittleEndian.Uint32(next(4)))
+
+	if extraDataOffset != executionPayloadSSZFixedSize || transactionsOffset < extraDataOffset || transactionsOffset > len(data) {
+		return nil, fmt.Errorf("%w: execution payload offsets %d, %d", ErrInvalidSSZ, extraDataOffset, transactionsOffset)
+	}
+	if transactionsOffset-extraDataOffset > maxExtraDataSize {
+		return nil, fmt.Errorf("%w: extra data of %d bytes", ErrInvalidSSZ, transactionsOffset-extraDataOffset)
+	}
+	payload.ExtraData = append(boostTypes.ExtraData{}, data[extraDataOffset:transactionsOffset]...)
+
+	transactions, err := unmarshalTransactionsSSZ(data[transactionsOffset:])
+	if err != nil {
+		return nil, err
+	}
+	payload.Transactions = transactions
+	return payload, nil
+}
+
+func unmarshalTransactionsSSZ(data []byte) ([]hexutil.Bytes, error) {
+	transactions := []hexutil.Bytes{}
+	if len(data) == 0 {
+		return transactions, nil
+	}
+	if len(data) < 4 {
+		return nil, fmt.Errorf("%w: transactions of %d bytes", ErrInvalidSSZ, len(data))
+	}
+
+	// The first offset is the size of the offsets
+	firstOffset := int(binary.LittleEndian.Uint32(data))
+	if firstOffset%4 != 0 || firstOffset == 0 || firstOffset > len(data) || firstOffset/4 > maxTransactions {
+		return nil, fmt.Errorf("%w: transactions offset %d", ErrInvalidSSZ, firstOffset)
+	}
+
+	count := firstOffset / 4
+	for i := 0; i < count; i++ {
+		start := int(binary.LittleEndian.Uint32(data[4*i:]))
+		end := len(data)
+		if i+1 < count {
+			end = int(binary.LittleEndian.Uint32(data[4*(i+1):]))
+		}
+		if start < firstOffset || end < start || end > len(data) {
+			return nil, fmt.Errorf("%w: transaction %d offsets %d, %d", ErrInvalidSSZ, i, start, end)
+		}
+		transactions = append(transactions, append(hexutil.Bytes{}, data[start:end]...))
+	}
+	return transactions, nil
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
@@ -0,0 +1,133 @@
+package builder
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ferranbt/fastssz/spectests"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSubmitBlockRequest(t *testing.T, transactions []hexutil.Bytes) *boostTypes.BuilderSubmitBlockRequest {
+	value := new(boostTypes.U256Str)
+	require.NoError(t, value.FromBig(big.NewInt(1_000_000_007)))
+	baseFee := new(boostTypes.U256Str)
+	require.NoError(t, baseFee.FromBig(big.NewInt(16)))
+
+	return &boostTypes.BuilderSubmitBlockRequest{
+		Signature: boostTypes.Signature{0x01, 0x02},
+		Message: &boostTypes.BidTrace{
+			Slot:                 25,
+			ParentHash:           boostTypes.Hash{0x02, 0x03},
+			BlockHash:            boostTypes.Hash{0x09, 0xff},
+			BuilderPubkey:        boostTypes.PublicKey{0x04},
+			ProposerPubkey:       boostTypes.PublicKey{0x05},
+			ProposerFeeRecipient: boostTypes.Address{0x06},
+			GasLimit:             30_000_000,
+			GasUsed:              21_000,
+			Value:                *value,
+		},
+		ExecutionPayload: &boostTypes.ExecutionPayload{
+			ParentHash:    boostTypes.Hash{0x02, 0x03},
+			FeeRecipient:  boostTypes.Address{0x06},
+			StateRoot:     boostTypes.Root{0x07},
+			ReceiptsRoot:  boostTypes.Root{0x08},
+			LogsBloom:     boostTypes.Bloom{0x0a},
+			Random:        boostTypes.Hash{0x0b},
+			BlockNumber:   10,
+			GasLimit:      30_000_000,
+			GasUsed:       21_000,
+			Timestamp:     1312,
+			ExtraData:     boostTypes.ExtraData{0x00, 0x42},
+			BaseFeePerGas: *baseFee,
+			BlockHash:     boostTypes.Hash{0x09, 0xff},
+			Transactions:  transactions,
+		},
+	}
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	for _, codec := range []Codec{JSONCodec{}, SSZCodec{}} {
+		for _, transactions := range [][]hexutil.Bytes{{}, {{0x01}}, {{0x01, 0x02}, {0x03}, {0x04, 0x05, 0x06}}} {
+			req := newTestSubmitBlockRequest(t, transactions)
+
+			data, _, err := codec.Encode(req)
+			require.NoError(t, err)
+
+			decoded, err := codec.Decode(data)
+			require.NoError(t, err)
+			require.Equal(t, req, decoded)
+		}
+	}
+}
+
+func TestSSZCodec(t *testing.T) {
+	req := newTestSubmitBlockRequest(t, []hexutil.Bytes{{0x01, 0x02}, {0x03}})
+
+	data, contentType, err := SSZCodec{}.Encode(req)
+	require.NoError(t, err)
+	require.Equal(t, "application/octet-stream", contentType)
+	require.Len(t, data, submitBlockRequestSSZFixedSize+executionPayloadSSZFixedSize+2+2*4+3)
+
+	for _, truncated := range []int{0, 100, submitBlockRequestSSZFixedSize + 10, submitBlockRequestSSZFixedSize + executionPayloadSSZFixedSize + 2 + 1} {
+		_, err = SSZCodec{}.Decode(data[:truncated])
+		require.ErrorIs(t, err, ErrInvalidSSZ)
+	}
+
+	req.ExecutionPayload.ExtraData = make(boostTypes.ExtraData, 33)
+	_, _, err = SSZCodec{}.Encode(req)
+	require.ErrorIs(t, err, ErrInvalidSSZ)
+}
+
+func TestSSZCodecSpecOrder(t *testing.T) {
+	req := newTestSubmitBlockRequest(t, []hexutil.Bytes{{0x01, 0x02}, {0x03}})
+	data, _, err := SSZCodec{}.Encode(req)
+	require.NoError(t, err)
+
+	// The builder-specs SubmitBlockRequest is the message, the execution payload's offset and the signature, then
+	// the execution payload, here encoded by the consensus spec tests' ExecutionPayload
+	payload := req.ExecutionPayload
+	specPayload := &spectests.ExecutionPayload{
+		ParentHash:    payload.ParentHash,
+		FeeRecipient:  payload.FeeRecipient,
+		StateRoot:     payload.StateRoot,
+		ReceiptsRoot:  payload.ReceiptsRoot,
+		LogsBloom:     payload.LogsBloom,
+		PrevRandao:    payload.Random,
+		BlockNumber:   payload.BlockNumber,
+		GasLimit:      payload.GasLimit,
+		GasUsed:       payload.GasUsed,
+		Timestamp:     payload.Timestamp,
+		ExtraData:     payload.ExtraData,
+		BaseFeePerGas: payload.BaseFeePerGas,
+		BlockHash:     payload.BlockHash,
+		Transactions:  [][]byte{{0x01, 0x02}, {0x03}},
+	}
+	encodedPayload, err := specPayload.MarshalSSZ()
+	require.NoError(t, err)
+	message, err := req.Message.MarshalSSZ()
+	require.NoError(t, err)
+
+	expected := append([]byte{}, message...)
+	expected = appendUint32(expected, uint32(len(message)+4+len(req.Signature)))
+	expected = append(expected, req.Signature[:]...)
+	expected = append(expected, encodedPayload...)
+	require.Equal(t, expected, data)
+	require.Equal(t, uint32(336), binary.LittleEndian.Uint32(data[236:]))
+}
+
+func TestParseCodec(t *testing.T) {
+	codec, err := ParseCodec("")
+	require.NoError(t, err)
+	require.Equal(t, JSONCodec{}, codec)
+
+	codec, err = ParseCodec("ssz")
+	require.NoError(t, err)
+	require.Equal(t, SSZCodec{}, codec)
+
+	_, err = ParseCodec("rlp")
+	require.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// ErrInvalidConfig is returned for the builder configurations with invalid values or combinations of options
+var ErrInvalidConfig = errors.New("invalid builder config")
+
+// Config is the complete configuration of a builder, its dependencies and its options.
+type Config struct {
+	// SecretKey is the builder key the submissions are signed with
+	SecretKey    *bls.SecretKey
+	BeaconClient IBeaconClient
+	// Relay is the relay the blocks are submitted to, a MultiRelay for several relays
+	Relay IRelay
+	// SigningDomain is the builder signing domain of the network
+	SigningDomain boostTypes.Domain
+	Eth           IEthereumService
+	BuilderOptions
+}
+
+// Validate checks the configuration for missing dependencies, invalid values and options which can't be combined,
+// returning an ErrInvalidConfig listing all the problems found.
+func (c *Config) Validate() error {
+	var problems []string
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.SecretKey == nil {
+		problem("missing secret key")
+	}
+	if c.BeaconClient == nil {
+		problem("missing beacon client")
+	}
+	if c.Relay == nil {
+		problem("missing relay")
+	}
+	if c.SigningDomain == (boostTypes.Domain{}) {
+		problem("missing signing domain")
+	}
+	if c.Eth == nil {
+		problem("missing ethereum service")
+	}
+
+	for _, option := range []struct {
+		name  string
+		value int
+	}{
+		{"ResubmitWorkers", c.ResubmitWorkers},
+		{"MaxConcurrentBuilds", c.MaxConcurrentBuilds},
+		{"CircuitBreakerThreshold", c.CircuitBreakerThreshold},
+		{"MaxCandidateHeads", c.MaxCandidateHeads},
+	} {
+		if option.value < 0 {
+			problem("negative %s %d", option.name, option.value)
+		}
+	}
+	for _, option := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"AllRelaysRejectedPause", c.AllRelaysRejectedPause},
+		{"NotSyncedMaxWait", c.NotSyncedMaxWait},
+		{"CircuitBreakerCooldown", c.CircuitBreakerCooldown},
+		{"RelayLatencySLA", c.RelayLatencySLA},
+		{"RelayLatencySLAWindow", c.RelayLatencySLAWindow},
+		{"SelfDrivenBuildDelay", c.SelfDrivenBuildDelay},
+		{"InitialSubmissionDelay", c.InitialSubmissionDelay},
+		{"MaxRegistrationAge", c.MaxRegistrationAge},
+		{"RegistrationCheckInterval", c.RegistrationCheckInterval},
+		{"SigningDomainCheckInterval", c.SigningDomainCheckInterval},
+	} {
+		if option.value < 0 {
+			problem("negative %s %s", option.name, option.value)
+		}
+	}
+	if c.GasLimitSmoothing < 0 || c.GasLimitSmoothing > 1 {
+		problem("GasLimitSmoothing %v not within [0, 1]", c.GasLimitSmoothing)
+	}
+
+	if _, err := ParseNotSyncedPolicy(string(c.NotSyncedPolicy)); err != nil {
+		problem("%v", err)
+	} else if c.NotSyncedPolicy == NotSyncedFallback && c.FallbackEthService == nil {
+		problem("NotSyncedFallback requires a FallbackEthService")
+	}
+	if _, err := ParseSlotMismatchPolicy(string(c.SlotMismatchPolicy)); err != nil {
+		problem("%v", err)
+	}
+	if _, err := ParseBeaconPolicy(string(c.BeaconPolicy)); err != nil {
+		problem("%v", err)
+	}
+	for _, window := range c.MaintenanceWindows {
+		if !window.End.After(window.Start) {
+			problem("maintenance window %s/%s ends before it starts", window.Start.Format(time.RFC3339), window.End.Format(time.RFC3339))
+		}
+	}
+
+	if c.TimestampFlexibility > 0 && !c.DevNetwork {
+		problem("TimestampFlexibility is for development networks only, the consensus rules require the slot's start as the timestamp")
+	}
+	if c.PrevRandaoOverride != nil && !c.DevNetwork {
+		problem("PrevRandaoOverride is for development networks only, the prevRandao must come from the beacon state")
+	}
+
+	if c.Observer && c.SpeculativeBuilds {
+		problem("SpeculativeBuilds can't be combined with Observer, which builds no blocks")
+	}
+	if c.LazyBuild != nil {
+		switch {
+		case c.Observer:
+			problem("LazyBuild can't be combined with Observer, which builds no blocks")
+		case c.SingleShot:
+			problem("LazyBuild can't be combined with SingleShot, LazyBuild only skips the resubmissions")
+		case c.Relay != nil && !c.anyRelay(func(relay IRelay) bool { _, ok := relay.(TopBidGetter); return ok }):
+			problem("LazyBuild requires a relay publishing its top bids")
+		}
+		if c.LazyBuild.MaxDeficit != nil && c.LazyBuild.MaxDeficit.Sign() < 0 {
+			problem("negative LazyBuild MaxDeficit %s", c.LazyBuild.MaxDeficit)
+		}
+	}
+
+	if c.Relay != nil {
+		var unknown []string
+		for name := range c.RelaySecretKeys {
+			if !c.hasRelay(name) {
+				unknown = append(unknown, fmt.Sprintf("RelaySecretKeys set for unknown relay %q", name))
+			}
+		}
+		for name := range c.RelayFees {
+			if !c.hasRelay(name) {
+				unknown = append(unknown, fmt.Sprintf("RelayFees set for unknown relay %q", name))
+			}
+		}
+		sort.Strings(unknown)
+		problems = append(problems, unknown...)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(problems, "; "))
+}
+
+// anyRelay reports whether any of the relays or failover relays satisfies the predicate.
+func (c *Config) anyRelay(predicate func(IRelay) bool) bool {
+	for _, relay := range relayMembers(c.Relay) {
+		if predicate(relay) {
+			return true
+		}
+	}
+	for _, relay := range c.FailoverRelays {
+		if predicate(relay) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) hasRelay(name string) bool {
+	return c.anyRelay(func(relay IRelay) bool {
+		relayName, _ := relayIdentity(relay)
+		return relayName == name
+	})
+}
+
+// NewBuilderFromConfig validates the configuration and creates the builder.
+func NewBuilderFromConfig(cfg Config) (*Builder, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return newBuilder(cfg), nil
+}
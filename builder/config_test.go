@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig(t *testing.T) Config {
+	sk, err := bls.GenerateRandomSecretKey()
+	require.NoError(t, err)
+	return Config{
+		SecretKey:     sk,
+		BeaconClient:  &testBeaconClient{validator: NewRandomValidator()},
+		Relay:         &testRelay{},
+		SigningDomain: boostTypes.ComputeDomain(boostTypes.DomainTypeAppBuilder, [4]byte{0x02, 0x0, 0x0, 0x0}, boostTypes.Hash{}),
+		Eth:           newTestEthereumService(),
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := newTestConfig(t)
+	require.NoError(t, cfg.Validate())
+	builder, err := NewBuilderFromConfig(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, builder)
+
+	for _, tc := range []struct {
+		name    string
+		modify  func(cfg *Config)
+		problem string
+	}{
+		{"missing relay", func(cfg *Config) { cfg.Relay = nil }, "missing relay"},
+		{"missing signing domain", func(cfg *Config) { cfg.SigningDomain = boostTypes.Domain{} }, "missing signing domain"},
+		{"negative workers", func(cfg *Config) { cfg.ResubmitWorkers = -1 }, "negative ResubmitWorkers -1"},
+		{"negative duration", func(cfg *Config) { cfg.NotSyncedMaxWait = -time.Second }, "negative NotSyncedMaxWait -1s"},
+		{"gas limit smoothing", func(cfg *Config) { cfg.GasLimitSmoothing = 2 }, "GasLimitSmoothing 2 not within [0, 1]"},
+		{"unknown policy", func(cfg *Config) { cfg.BeaconPolicy = "loose" }, `unknown beacon policy "loose"`},
+		{"fallback without service", func(cfg *Config) { cfg.NotSyncedPolicy = NotSyncedFallback }, "NotSyncedFallback requires a FallbackEthService"},
+		{"inverted maintenance window", func(cfg *Config) {
+			cfg.MaintenanceWindows = []MaintenanceWindow{{Start: time.Unix(100, 0).UTC(), End: time.Unix(50, 0).UTC()}}
+		}, "maintenance window 1970-01-01T00:01:40Z/1970-01-01T00:00:50Z ends before it starts"},
+		{"timestamp flexibility", func(cfg *Config) { cfg.TimestampFlexibility = 2 }, "TimestampFlexibility is for development networks only"},
+		{"prevRandao override", func(cfg *Config) { cfg.PrevRandaoOverride = &common.Hash{0x01} }, "PrevRandaoOverride is for development networks only"},
+		{"speculative observer", func(cfg *Config) { cfg.Observer, cfg.SpeculativeBuilds = true, true }, "SpeculativeBuilds can't be combined with Observer"},
+		{"lazy single shot", func(cfg *Config) {
+			cfg.Relay = &topBidRelay{testRelay: &testRelay{}}
+			cfg.SingleShot, cfg.LazyBuild = true, &LazyBuildPolicy{}
+		}, "LazyBuild can't be combined with SingleShot"},
+		{"lazy without top bids", func(cfg *Config) { cfg.LazyBuild = &LazyBuildPolicy{} }, "LazyBuild requires a relay publishing its top bids"},
+		{"negative lazy deficit", func(cfg *Config) {
+			cfg.Relay = &topBidRelay{testRelay: &testRelay{}}
+			cfg.LazyBuild = &LazyBuildPolicy{MaxDeficit: big.NewInt(-1)}
+		}, "negative LazyBuild MaxDeficit -1"},
+		{"unknown relay fee", func(cfg *Config) { cfg.RelayFees = map[string]RelayFee{"relay.example.com": {}} }, `RelayFees set for unknown relay "relay.example.com"`},
+	} {
+		cfg := newTestConfig(t)
+		tc.modify(&cfg)
+		err := cfg.Validate()
+		require.ErrorIs(t, err, ErrInvalidConfig, tc.name)
+		require.ErrorContains(t, err, tc.problem, tc.name)
+
+		_, err = NewBuilderFromConfig(cfg)
+		require.ErrorIs(t, err, ErrInvalidConfig, tc.name)
+	}
+
+	// The lazy builds are valid with a relay publishing its top bids, among the relays of a MultiRelay
+	cfg = newTestConfig(t)
+	cfg.Relay = NewMultiRelay([]IRelay{&testRelay{}, &topBidRelay{testRelay: &testRelay{}}}, ValidatorConflictRecent)
+	cfg.LazyBuild = &LazyBuildPolicy{}
+	require.NoError(t, cfg.Validate())
+
+	// All the problems are reported
+	cfg = Config{}
+	require.EqualError(t, cfg.Validate(), "invalid builder config: missing secret key; missing beacon client; missing relay; missing signing domain; missing ethereum service")
+}
@@ -0,0 +1,363 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package controlapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ControlClient is the client API for Control service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ControlClient interface {
+	// Pause stops building and submitting blocks until Resume.
+	Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*PauseResponse, error)
+	// Resume resumes building and submitting blocks from the next payload attributes.
+	Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error)
+	// Status returns whether the builder is paused and the status of the relays.
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// EnableRelay resumes the submissions to the relay, NOT_FOUND if there is no such relay.
+	EnableRelay(ctx context.Context, in *EnableRelayRequest, opts ...grpc.CallOption) (*EnableRelayResponse, error)
+	// DisableRelay stops the submissions to the relay, NOT_FOUND if there is no such relay.
+	DisableRelay(ctx context.Context, in *DisableRelayRequest, opts ...grpc.CallOption) (*DisableRelayResponse, error)
+	// TriggerRebuild builds and submits a block for the slot right away, FAILED_PRECONDITION if blocks are not
+	// being built for the slot.
+	TriggerRebuild(ctx context.Context, in *TriggerRebuildRequest, opts ...grpc.CallOption) (*TriggerRebuildResponse, error)
+	// StreamSubmissions streams the outcome of every block submission from the time of the call. The events a
+	// slow client doesn't keep up with are dropped.
+	StreamSubmissions(ctx context.Context, in *StreamSubmissionsRequest, opts ...grpc.CallOption) (Control_StreamSubmissionsClient, error)
+}
+
+type controlClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewControlClient(cc grpc.ClientConnInterface) ControlClient {
+	return &controlClient{cc}
+}
+
+func (c *controlClient) Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*PauseResponse, error) {
+	out := new(PauseResponse)
+	err := c.cc.Invoke(ctx, "/builder.control.v1.Control/Pause", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error) {
+	out := new(ResumeResponse)
+	err := c.cc.Invoke(ctx, "/builder.control.v1.Control/Resume", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, "/builder.control.v1.Control/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) EnableRelay(ctx context.Context, in *EnableRelayRequest, opts ...grpc.CallOption) (*EnableRelayResponse, error) {
+	out := new(EnableRelayResponse)
+	err := c.cc.Invoke(ctx, "/builder.control.v1.Control/EnableRelay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) DisableRelay(ctx context.Context, in *DisableRelayRequest, opts ...grpc.CallOption) (*DisableRelayResponse, error) {
+	out := new(DisableRelayResponse)
+	err := c.cc.Invoke(ctx, "/builder.control.v1.Control/DisableRelay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) TriggerRebuild(ctx context.Context, in *TriggerRebuildRequest, opts ...grpc.CallOption) (*TriggerRebuildResponse, error) {
+	out := new(TriggerRebuildResponse)
+	err := c.cc.Invoke(ctx, "/builder.control.v1.Control/TriggerRebuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) StreamSubmissions(ctx context.Context, in *StreamSubmissionsRequest, opts ...grpc.CallOption) (Control_StreamSubmissionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Control_ServiceDesc.Streams[0], "/builder.control.v1.Control/StreamSubmissions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlStreamSubmissionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_StreamSubmissionsClient interface {
+	Recv() (*SubmissionEvent, error)
+	grpc.ClientStream
+}
+
+type controlStreamSubmissionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlStreamSubmissionsClient) Recv() (*SubmissionEvent, error) {
+	m := new(SubmissionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ControlServer is the server API for Control service.
+// All implementations must embed UnimplementedControlServer
+// for forward compatibility
+type ControlServer interface {
+	// Pause stops building and submitting blocks until Resume.
+	Pause(context.Context, *PauseRequest) (*PauseResponse, error)
+	// Resume resumes building and submitting blocks from the next payload attributes.
+	Resume(context.Context, *ResumeRequest) (*ResumeResponse, error)
+	// Status returns whether the builder is paused and the status of the relays.
+	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	// EnableRelay resumes the submissions to the relay, NOT_FOUND if there is no such relay.
+	EnableRelay(context.Context, *EnableRelayRequest) (*EnableRelayResponse, error)
+	// DisableRelay stops the submissions to the relay, NOT_FOUND if there is no such relay.
+	DisableRelay(context.Context, *DisableRelayRequest) (*DisableRelayResponse, error)
+	// TriggerRebuild builds and submits a block for the slot right away, FAILED_PRECONDITION if blocks are not
+	// being built for the slot.
+	TriggerRebuild(context.Context, *TriggerRebuildRequest) (*TriggerRebuildResponse, error)
+	// StreamSubmissions streams the outcome of every block submission from the time of the call. The events a
+	// slow client doesn't keep up with are dropped.
+	StreamSubmissions(*StreamSubmissionsRequest, Control_StreamSubmissionsServer) error
+	mustEmbedUnimplementedControlServer()
+}
+
+// UnimplementedControlServer must be embedded to have forward compatible implementations.
+type UnimplementedControlServer struct {
+}
+
+func (UnimplementedControlServer) Pause(context.Context, *PauseRequest) (*PauseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pause not implemented")
+}
+func (UnimplementedControlServer) Resume(context.Context, *ResumeRequest) (*ResumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (UnimplementedControlServer) Status(context.Context, *StatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedControlServer) EnableRelay(context.Context, *EnableRelayRequest) (*EnableRelayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableRelay not implemented")
+}
+func (UnimplementedControlServer) DisableRelay(context.Context, *DisableRelayRequest) (*DisableRelayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableRelay not implemented")
+}
+func (UnimplementedControlServer) TriggerRebuild(context.Context, *TriggerRebuildRequest) (*TriggerRebuildResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerRebuild not implemented")
+}
+func (UnimplementedControlServer) StreamSubmissions(*StreamSubmissionsRequest, Control_StreamSubmissionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamSubmissions not implemented")
+}
+func (UnimplementedControlServer) mustEmbedUnimplementedControlServer() {}
+
+// UnsafeControlServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ControlServer will
+// result in compilation errors.
+type UnsafeControlServer interface {
+	mustEmbedUnimplementedControlServer()
+}
+
+func RegisterControlServer(s grpc.ServiceRegistrar, srv ControlServer) {
+	s.RegisterService(&Control_ServiceDesc, srv)
+}
+
+func _Control_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/builder.control.v1.Control/Pause",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Pause(ctx, req.(*PauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/builder.control.v1.Control/Resume",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Resume(ctx, req.(*ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/builder.control.v1.Control/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_EnableRelay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableRelayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).EnableRelay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/builder.control.v1.Control/EnableRelay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).EnableRelay(ctx, req.(*EnableRelayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_DisableRelay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableRelayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).DisableRelay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/builder.control.v1.Control/DisableRelay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).DisableRelay(ctx, req.(*DisableRelayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_TriggerRebuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerRebuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).TriggerRebuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/builder.control.v1.Control/TriggerRebuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).TriggerRebuild(ctx, req.(*TriggerRebuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_StreamSubmissions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamSubmissionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).StreamSubmissions(m, &controlStreamSubmissionsServer{stream})
+}
+
+type Control_StreamSubmissionsServer interface {
+	Send(*SubmissionEvent) error
+	grpc.ServerStream
+}
+
+type controlStreamSubmissionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlStreamSubmissionsServer) Send(m *SubmissionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Control_ServiceDesc is the grpc.ServiceDesc for Control service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Control_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "builder.control.v1.Control",
+	HandlerType: (*ControlServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Pause",
+			Handler:    _Control_Pause_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _Control_Resume_Handler,
+		},
+		{
+			MethodName: "Status",
+			Handler:    _Control_Status_Handler,
+		},
+		{
+			MethodName: "EnableRelay",
+			Handler:    _Control_EnableRelay_Handler,
+		},
+		{
+			MethodName: "DisableRelay",
+			Handler:    _Control_DisableRelay_Handler,
+		},
+		{
+			MethodName: "TriggerRebuild",
+			Handler:    _Control_TriggerRebuild_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSubmissions",
+			Handler:       _Control_StreamSubmissions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "control.proto",
+}
@@ -0,0 +1,184 @@
+// Package controlapi serves the gRPC control API of the builder: pausing and resuming it, toggling the relays,
+// querying its status, triggering rebuilds and streaming the submission events. It is an opt-in integration,
+// started with the builder service as an Extension.
+package controlapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative control.proto
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/ethereum/go-ethereum/builder"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// submissionStreamBuffer is the number of submission events buffered per stream, later events are dropped while
+// the client is not keeping up
+const submissionStreamBuffer = 64
+
+// Controller is the builder the control API is backed by, implemented by *builder.Builder.
+type Controller interface {
+	Pause()
+	Resume()
+	Status() builder.BuilderStatus
+	EnableRelay(id string) error
+	DisableRelay(id string) error
+	TriggerRebuild(slot uint64) error
+	SubscribeSubmissions(ch chan<- builder.SubmissionEvent) (unsubscribe func())
+}
+
+// Server implements the Control service with the controller.
+type Server struct {
+	UnimplementedControlServer
+	controller Controller
+}
+
+func NewServer(controller Controller) *Server {
+	return &Server{controller: controller}
+}
+
+func (s *Server) Pause(ctx context.Context, req *PauseRequest) (*PauseResponse, error) {
+	s.controller.Pause()
+	return &PauseResponse{}, nil
+}
+
+func (s *Server) Resume(ctx context.Context, req *ResumeRequest) (*ResumeResponse, error) {
+	s.controller.Resume()
+	return &ResumeResponse{}, nil
+}
+
+func (s *Server) Status(ctx context.Context, req *StatusRequest) (*StatusResponse, error) {
+	builderStatus := s.controller.Status()
+	resp := &StatusResponse{Paused: builderStatus.Paused, PauseReason: builderStatus.PauseReason}
+	for _, relay := range builderStatus.Relays {
+		info := &Relay{
+			Name:           relay.Name,
+			Url:            relay.URL,
+			Enabled:        relay.Enabled,
+			CircuitState:   relay.CircuitState,
+			LatencySamples: int64(relay.Latency.Samples),
+			LatencyP50:     durationpb.New(relay.Latency.P50),
+			LatencyP99:     durationpb.New(relay.Latency.P99),
+		}
+		if !relay.LastSuccess.IsZero() {
+			info.LastSuccess = timestamppb.New(relay.LastSuccess)
+		}
+		resp.Relays = append(resp.Relays, info)
+	}
+	return resp, nil
+}
+
+func (s *Server) EnableRelay(ctx context.Context, req *EnableRelayRequest) (*EnableRelayResponse, error) {
+	if err := s.controller.EnableRelay(req.Relay); err != nil {
+		return nil, toStatus(err)
+	}
+	return &EnableRelayResponse{}, nil
+}
+
+func (s *Server) DisableRelay(ctx context.Context, req *DisableRelayRequest) (*DisableRelayResponse, error) {
+	if err := s.controller.DisableRelay(req.Relay); err != nil {
+		return nil, toStatus(err)
+	}
+	return &DisableRelayResponse{}, nil
+}
+
+func (s *Server) TriggerRebuild(ctx context.Context, req *TriggerRebuildRequest) (*TriggerRebuildResponse, error) {
+	if err := s.controller.TriggerRebuild(req.Slot); err != nil {
+		return nil, toStatus(err)
+	}
+	return &TriggerRebuildResponse{}, nil
+}
+
+// StreamSubmissions sends the submission events until the client goes away.
+func (s *Server) StreamSubmissions(req *StreamSubmissionsRequest, stream Control_StreamSubmissionsServer) error {
+	events := make(chan builder.SubmissionEvent, submissionStreamBuffer)
+	unsubscribe := s.controller.SubscribeSubmissions(events)
+	defer unsubscribe()
+
+	for {
+		select {
+		case event := <-events:
+			if err := stream.Send(toSubmissionEvent(event)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func toSubmissionEvent(event builder.SubmissionEvent) *SubmissionEvent {
+	converted := &SubmissionEvent{
+		SubmissionId:      event.SubmissionID,
+		Slot:              event.Slot,
+		BlockHash:         event.BlockHash.String(),
+		Value:             event.Value.BigInt().String(),
+		Submitted:         event.Submitted,
+		Error:             event.Error,
+		AllRelaysRejected: event.AllRelaysRejected,
+		Time:              timestamppb.New(event.Time),
+	}
+	for _, outcome := range event.Relays {
+		converted.Relays = append(converted.Relays, &RelayOutcome{Relay: outcome.Relay, Skipped: outcome.Skipped, Error: outcome.Error})
+	}
+	return converted
+}
+
+// toStatus maps the builder's errors to the gRPC status codes.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, builder.ErrUnknownRelay):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, builder.ErrNoActiveBuild):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// service serves the control API on its address for the lifetime of the node.
+type service struct {
+	addr     string
+	server   *grpc.Server
+	listener net.Listener
+}
+
+func (s *service) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			log.Error("gRPC control API stopped", "err", err)
+		}
+	}()
+	log.Info("gRPC control API started", "addr", listener.Addr())
+	return nil
+}
+
+// Stop stops the server right away, as the submission streams never end on their own.
+func (s *service) Stop() error {
+	s.server.Stop()
+	return nil
+}
+
+// Extension serves the control API on the address once the builder is created. The API is not authenticated, the
+// address must only be reachable by the operators.
+func Extension(addr string) builder.Extension {
+	return func(stack *node.Node, b *builder.Builder) error {
+		server := grpc.NewServer()
+		RegisterControlServer(server, NewServer(b))
+		stack.RegisterLifecycle(&service{addr: addr, server: server})
+		return nil
+	}
+}
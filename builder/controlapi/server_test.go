@@ -0,0 +1,158 @@
+package controlapi
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/builder"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testController struct {
+	mu          sync.Mutex
+	paused      bool
+	disabled    map[string]bool
+	rebuilt     []uint64
+	subscribers chan chan<- builder.SubmissionEvent
+}
+
+func (c *testController) Pause()  { c.mu.Lock(); c.paused = true; c.mu.Unlock() }
+func (c *testController) Resume() { c.mu.Lock(); c.paused = false; c.mu.Unlock() }
+
+func (c *testController) Status() builder.BuilderStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	reason := ""
+	if c.paused {
+		reason = "paused"
+	}
+	return builder.BuilderStatus{
+		Paused:      c.paused,
+		PauseReason: reason,
+		Relays:      []builder.RelayInfo{{Name: "relay.example.com", Enabled: !c.disabled["relay.example.com"], CircuitState: "closed"}},
+	}
+}
+
+func (c *testController) EnableRelay(id string) error  { return c.toggle(id, true) }
+func (c *testController) DisableRelay(id string) error { return c.toggle(id, false) }
+
+func (c *testController) toggle(id string, enabled bool) error {
+	if id != "relay.example.com" {
+		return builder.ErrUnknownRelay
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disabled[id] = !enabled
+	return nil
+}
+
+func (c *testController) TriggerRebuild(slot uint64) error {
+	if slot != 25 {
+		return builder.ErrNoActiveBuild
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebuilt = append(c.rebuilt, slot)
+	return nil
+}
+
+func (c *testController) SubscribeSubmissions(ch chan<- builder.SubmissionEvent) func() {
+	c.subscribers <- ch
+	return func() {}
+}
+
+func newTestClient(t *testing.T, controller Controller) ControlClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	RegisterControlServer(server, NewServer(controller))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return NewControlClient(conn)
+}
+
+func TestControlServer(t *testing.T) {
+	controller := &testController{disabled: make(map[string]bool), subscribers: make(chan chan<- builder.SubmissionEvent, 1)}
+	client := newTestClient(t, controller)
+	ctx := context.Background()
+
+	_, err := client.Pause(ctx, &PauseRequest{})
+	require.NoError(t, err)
+	_, err = client.DisableRelay(ctx, &DisableRelayRequest{Relay: "relay.example.com"})
+	require.NoError(t, err)
+	resp, err := client.Status(ctx, &StatusRequest{})
+	require.NoError(t, err)
+	require.True(t, resp.Paused)
+	require.Equal(t, "paused", resp.PauseReason)
+	require.Len(t, resp.Relays, 1)
+	require.Equal(t, "relay.example.com", resp.Relays[0].Name)
+	require.False(t, resp.Relays[0].Enabled)
+	require.Nil(t, resp.Relays[0].LastSuccess)
+
+	_, err = client.Resume(ctx, &ResumeRequest{})
+	require.NoError(t, err)
+	_, err = client.EnableRelay(ctx, &EnableRelayRequest{Relay: "relay.example.com"})
+	require.NoError(t, err)
+	resp, err = client.Status(ctx, &StatusRequest{})
+	require.NoError(t, err)
+	require.False(t, resp.Paused)
+	require.True(t, resp.Relays[0].Enabled)
+
+	// The builder's errors are mapped to the status codes
+	_, err = client.EnableRelay(ctx, &EnableRelayRequest{Relay: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.TriggerRebuild(ctx, &TriggerRebuildRequest{Slot: 26})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = client.TriggerRebuild(ctx, &TriggerRebuildRequest{Slot: 25})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{25}, controller.rebuilt)
+}
+
+func TestStreamSubmissions(t *testing.T) {
+	controller := &testController{subscribers: make(chan chan<- builder.SubmissionEvent, 1)}
+	client := newTestClient(t, controller)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamSubmissions(ctx, &StreamSubmissionsRequest{})
+	require.NoError(t, err)
+
+	var value boostTypes.U256Str
+	value[0] = 10
+	subscriber := <-controller.subscribers
+	subscriber <- builder.SubmissionEvent{
+		SubmissionID:      "submission",
+		Slot:              25,
+		BlockHash:         boostTypes.Hash{0x01},
+		Value:             value,
+		Error:             "rejected",
+		AllRelaysRejected: true,
+		Relays:            []builder.RelayOutcomeEvent{{Relay: "relay.example.com", Error: "rejected"}},
+		Time:              time.Unix(1, 0),
+	}
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "submission", event.SubmissionId)
+	require.Equal(t, uint64(25), event.Slot)
+	require.Equal(t, boostTypes.Hash{0x01}.String(), event.BlockHash)
+	require.Equal(t, "10", event.Value)
+	require.False(t, event.Submitted)
+	require.True(t, event.AllRelaysRejected)
+	require.Len(t, event.Relays, 1)
+	require.Equal(t, "rejected", event.Relays[0].Error)
+	require.Equal(t, int64(1), event.Time.AsTime().Unix())
+}
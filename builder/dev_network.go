@@ -0,0 +1,27 @@
+package builder
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// devChainID is the chain ID of geth's --dev chain
+var devChainID = big.NewInt(1337)
+
+// IsDevNetwork reports whether the chain is a development network without a real beacon chain: geth's --dev chain,
+// or one of the chain IDs listed by the operator. The test features breaking the consensus rules enforced by a
+// beacon chain are only allowed on the development networks.
+func IsDevNetwork(chainID *big.Int, devChainIDs []string) (bool, error) {
+	isDev := chainID != nil && chainID.Cmp(devChainID) == 0
+	for _, listed := range devChainIDs {
+		id, ok := new(big.Int).SetString(strings.TrimSpace(listed), 10)
+		if !ok {
+			return false, fmt.Errorf("invalid development chain ID %q", listed)
+		}
+		if chainID != nil && chainID.Cmp(id) == 0 {
+			isDev = true
+		}
+	}
+	return isDev, nil
+}
@@ -0,0 +1,27 @@
+package builder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDevNetwork(t *testing.T) {
+	isDev, err := IsDevNetwork(big.NewInt(1337), nil)
+	require.NoError(t, err)
+	require.True(t, isDev)
+
+	for _, chainID := range []int64{1, 5, 11155111, 17000} {
+		isDev, err = IsDevNetwork(big.NewInt(chainID), nil)
+		require.NoError(t, err)
+		require.False(t, isDev)
+	}
+
+	isDev, err = IsDevNetwork(big.NewInt(32382), []string{"1000", " 32382"})
+	require.NoError(t, err)
+	require.True(t, isDev)
+
+	_, err = IsDevNetwork(big.NewInt(1), []string{"dev"})
+	require.ErrorContains(t, err, `invalid development chain ID "dev"`)
+}
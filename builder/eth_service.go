@@ -1,72 +1,704 @@
 package builder
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/beacon"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/miner"
 )
 
+// BuildStrategy selects how EthereumService.BuildBlock produces a block.
+type BuildStrategy string
+
+const (
+	// BuildStrategyGetPayload builds a single block from the current txpool,
+	// the same way the engine API getPayload does.
+	BuildStrategyGetPayload BuildStrategy = "getPayload"
+	// BuildStrategyCustom builds up to BuildParams.Iterations blocks within the
+	// build budget, leaving out one more of the bundles at each build, and
+	// returns the most profitable one.
+	BuildStrategyCustom BuildStrategy = "custom"
+)
+
+// ErrProposerPayment is returned for the blocks in which the miner could not pay the proposer.
+var ErrProposerPayment = miner.ErrProposerPayout
+
+const (
+	buildBlockTimeout            = 4 * time.Second
+	defaultCustomBuildIterations = 3
+	maxCustomBuildIterations     = 16
+)
+
+func ParseBuildStrategy(strategy string) (BuildStrategy, error) {
+	switch BuildStrategy(strategy) {
+	case "":
+		return BuildStrategyGetPayload, nil
+	case BuildStrategyGetPayload, BuildStrategyCustom:
+		return BuildStrategy(strategy), nil
+	default:
+		return "", fmt.Errorf("unknown build strategy %q", strategy)
+	}
+}
+
+// BuildParams are strategy-specific parameters passed along with the payload attributes.
+type BuildParams struct {
+	Strategy   BuildStrategy `json:"strategy,omitempty"`
+	Iterations int           `json:"iterations,omitempty"`
+	// PinMempool builds all of the slot's blocks from the txpool snapshot taken at its first build
+	PinMempool bool `json:"pinMempool,omitempty"`
+	// MinPriorityFee excludes transactions paying a lower effective priority fee per gas
+	MinPriorityFee *hexutil.Big `json:"minPriorityFee,omitempty"`
+	// MaxTransactions caps the number of transactions from the txpool in the block, the proposer payment is not
+	// counted. Unlimited if zero
+	MaxTransactions int `json:"maxTransactions,omitempty"`
+	// RequiredTxs must all be in the block, else the block is dropped
+	RequiredTxs []common.Hash `json:"requiredTxs,omitempty"`
+	// DeniedTxs and the transactions of DeniedSenders are excluded from the block
+	DeniedTxs     []common.Hash    `json:"deniedTxs,omitempty"`
+	DeniedSenders []common.Address `json:"deniedSenders,omitempty"`
+	// Bundles are included atomically at the top of the block, in order, the bundles failing or reverting are left
+	// out. Of the bundles sharing a transaction or a sender's nonce only the most valuable is included, and the
+	// bundles breaking the other build parameters are dropped
+	Bundles []BundleHint `json:"bundles,omitempty"`
+}
+
+// filterByMinPriorityFee drops the transactions paying less than minPriorityFee at the base fee,
+// along with the account's later transactions which can't be included without them.
+func filterByMinPriorityFee(pending map[common.Address]types.Transactions, baseFee *big.Int, minPriorityFee *big.Int) map[common.Address]types.Transactions {
+	filtered := make(map[common.Address]types.Transactions, len(pending))
+	for account, txs := range pending {
+		included := txs
+		for i, tx := range txs {
+			if tx.EffectiveGasTipValue(baseFee).Cmp(minPriorityFee) < 0 {
+				included = txs[:i]
+				break
+			}
+		}
+		if len(included) > 0 {
+			filtered[account] = included
+		}
+	}
+	return filtered
+}
+
+// checkBundleMinPriorityFee checks that all of the bundle's transactions pay at least minPriorityFee at the base fee.
+func checkBundleMinPriorityFee(b *bundle, baseFee *big.Int, minPriorityFee *big.Int) error {
+	for i, tx := range b.txs {
+		if tip := tx.EffectiveGasTipValue(baseFee); tip.Cmp(minPriorityFee) < 0 {
+			return fmt.Errorf("transaction %d (%s) priority fee %s below the minimum %s", i, tx.Hash(), tip, minPriorityFee)
+		}
+	}
+	return nil
+}
+
+// checkMinPriorityFee checks that all of the block's transactions but the proposer payment pay at least minPriorityFee.
+func checkMinPriorityFee(block *types.Block, minPriorityFee *big.Int) error {
+	txs := block.Transactions()
+	if block.Profit != nil && block.Profit.Sign() > 0 && len(txs) > 0 {
+		txs = txs[:len(txs)-1]
+	}
+
+	for i, tx := range txs {
+		if tip := tx.EffectiveGasTipValue(block.BaseFee()); tip.Cmp(minPriorityFee) < 0 {
+			return fmt.Errorf("transaction %d (%s) priority fee %s below the minimum %s", i, tx.Hash(), tip, minPriorityFee)
+		}
+	}
+	return nil
+}
+
+// limitTransactions returns the first max transactions of the pending transactions in the miner's order, by
+// effective tip at the base fee and nonce.
+func limitTransactions(pending map[common.Address]types.Transactions, signer types.Signer, baseFee *big.Int, max int) map[common.Address]types.Transactions {
+	remaining := make(map[common.Address]types.Transactions, len(pending))
+	for account, txs := range pending {
+		remaining[account] = txs
+	}
+
+	limited := make(map[common.Address]types.Transactions)
+	ordered := types.NewTransactionsByPriceAndNonce(signer, remaining, baseFee)
+	for n := 0; n < max; n++ {
+		tx := ordered.Peek()
+		if tx == nil {
+			break
+		}
+		account, _ := types.Sender(signer, tx)
+		limited[account] = append(limited[account], tx)
+		ordered.Shift()
+	}
+	return limited
+}
+
+// checkMaxTransactions returns an error if the block has more than max transactions besides the proposer payment.
+func checkMaxTransactions(block *types.Block, max int) error {
+	txs := block.Transactions()
+	if block.Profit != nil && block.Profit.Sign() > 0 && len(txs) > 0 {
+		txs = txs[:len(txs)-1]
+	}
+	if len(txs) > max {
+		return fmt.Errorf("%d transactions above the maximum of %d", len(txs), max)
+	}
+	return nil
+}
+
+// ProfitBreakdown splits the builder's earnings in a block by their origin. PriorityFees + DirectPayments +
+// BundlePayments are the builder's earnings, the proposer payment paying them out net of PaymentTxFee.
+type ProfitBreakdown struct {
+	// PriorityFees are the tips of the transactions outside of the bundles
+	PriorityFees *big.Int
+	// DirectPayments are the transfers to the coinbase by the transactions outside of the bundles
+	DirectPayments *big.Int
+	// BundlePayments are what the bundles' transactions paid the coinbase, their tips and transfers
+	BundlePayments *big.Int
+	// PaymentTxFee is what the proposer payment cost the coinbase besides the payment itself
+	PaymentTxFee *big.Int
+	// BaseFeeBurned is the base fee paid by all of the block's transactions, the proposer payment included. It is not
+	// part of the profit
+	BaseFeeBurned *big.Int
+	// PaymentIndex is the index of the proposer payment in the block's transactions, -1 if the block has none
+	PaymentIndex int
+}
+
+// computeProfitBreakdown derives the breakdown from the block's receipts and the builder's coinbase balance change
+// by each of the block's transactions. The bundled transactions' changes are the bundle payments. The proposer
+// payment is the last transaction if it is sent by the coinbase and pays the block's profit.
+func computeProfitBreakdown(block *types.Block, receipts types.Receipts, coinbaseDeltas []*big.Int, bundled map[common.Hash]struct{}, signer types.Signer) (*ProfitBreakdown, error) {
+	txs := block.Transactions()
+	if len(txs) != len(receipts) {
+		return nil, errors.New("receipts do not match block transactions")
+	}
+	if len(txs) != len(coinbaseDeltas) {
+		return nil, errors.New("coinbase balance changes do not match block transactions")
+	}
+
+	breakdown := &ProfitBreakdown{
+		PriorityFees:   new(big.Int),
+		DirectPayments: new(big.Int),
+		BundlePayments: new(big.Int),
+		PaymentTxFee:   new(big.Int),
+		BaseFeeBurned:  new(big.Int),
+	}
+
+	payoutIdx := proposerPaymentIndex(block, signer)
+	breakdown.PaymentIndex = payoutIdx
+	for i, tx := range txs {
+		gasUsed := new(big.Int).SetUint64(receipts[i].GasUsed)
+		if block.BaseFee() != nil {
+			breakdown.BaseFeeBurned.Add(breakdown.BaseFeeBurned, new(big.Int).Mul(gasUsed, block.BaseFee()))
+		}
+		if i == payoutIdx {
+			// The payment is sent from the coinbase, which gets the payment's tip back
+			breakdown.PaymentTxFee.Sub(new(big.Int).Neg(coinbaseDeltas[i]), tx.Value())
+			continue
+		}
+		if _, found := bundled[tx.Hash()]; found {
+			breakdown.BundlePayments.Add(breakdown.BundlePayments, coinbaseDeltas[i])
+			continue
+		}
+		tip := new(big.Int).Mul(gasUsed, tx.EffectiveGasTipValue(block.BaseFee()))
+		breakdown.PriorityFees.Add(breakdown.PriorityFees, tip)
+		breakdown.DirectPayments.Add(breakdown.DirectPayments, new(big.Int).Sub(coinbaseDeltas[i], tip))
+	}
+	return breakdown, nil
+}
+
+// proposerPaymentIndex returns the index of the block's proposer payment, the last transaction if it is sent by the
+// coinbase and pays the block's profit, else -1.
+func proposerPaymentIndex(block *types.Block, signer types.Signer) int {
+	txs := block.Transactions()
+	if block.Profit == nil || block.Profit.Sign() <= 0 || len(txs) == 0 {
+		return -1
+	}
+	last := txs[len(txs)-1]
+	if sender, err := types.Sender(signer, last); err != nil || sender != block.Coinbase() || last.Value().Cmp(block.Profit) != 0 {
+		return -1
+	}
+	return len(txs) - 1
+}
+
 type IEthereumService interface {
-	BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block)
+	BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown)
 	GetBlockByHash(hash common.Hash) *types.Block
+	CurrentBlock() *types.Block
+	// GetCanonicalHash returns the canonical block hash at the number, the zero hash if the block was not imported yet
+	GetCanonicalHash(number uint64) common.Hash
 	Synced() bool
+	// SimulateBlock executes the block on its parent state, returning an error if the state transition fails
+	SimulateBlock(ctx context.Context, block *types.Block) error
+	// ClientVersion returns the version string of the EL client, which is fetched once at startup
+	ClientVersion() string
+}
+
+// BuildStats are the timings of a block build on the EL.
+type BuildStats struct {
+	// Total is the time spent in the build, including the profit breakdown
+	Total time.Duration
+	// Build is the time spent building the block with the strategy
+	Build time.Duration
+}
+
+// BuildStatsReporter are the Ethereum services reporting the timings of their builds, and the error if no block
+// was built.
+type BuildStatsReporter interface {
+	BuildBlockWithStats(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown, BuildStats, error)
 }
 
 type testEthereumService struct {
 	synced             bool
 	testExecutableData *beacon.ExecutableDataV1
 	testBlock          *types.Block
+	canonicalHashes    map[uint64]common.Hash
+	simulateErr        error
+	clientVersion      string
 }
 
-func (t *testEthereumService) BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block) {
-	return t.testExecutableData, t.testBlock
+func (t *testEthereumService) BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown) {
+	return t.testExecutableData, t.testBlock, nil
 }
 
 func (t *testEthereumService) GetBlockByHash(hash common.Hash) *types.Block { return t.testBlock }
 
+func (t *testEthereumService) CurrentBlock() *types.Block { return t.testBlock }
+
+func (t *testEthereumService) GetCanonicalHash(number uint64) common.Hash {
+	return t.canonicalHashes[number]
+}
+
 func (t *testEthereumService) Synced() bool { return t.synced }
 
+func (t *testEthereumService) SimulateBlock(ctx context.Context, block *types.Block) error {
+	return t.simulateErr
+}
+
+func (t *testEthereumService) ClientVersion() string { return t.clientVersion }
+
 type EthereumService struct {
-	eth *eth.Ethereum
+	eth      *eth.Ethereum
+	strategy BuildStrategy
+
+	// profitBreakdown re-executes every built block to split its profit, which costs an extra block execution
+	profitBreakdown bool
+
+	// pinMempool builds all blocks of a slot from the same txpool snapshot, which makes the blocks comparable
+	// but misses transactions arriving after the slot's first build
+	pinMempool bool
+	snapshotMu sync.Mutex
+	snapshot   *mempoolSnapshot
+
+	clientVersion string
+
+	// extraDataProvider returns the extra data of the slot's blocks, the miner's is kept if nil
+	extraDataProvider func(slot uint64) []byte
 }
 
-func NewEthereumService(eth *eth.Ethereum) *EthereumService {
-	return &EthereumService{eth: eth}
+// mempoolSnapshot is the txpool's pending transactions pinned for the builds of a slot on a parent
+type mempoolSnapshot struct {
+	slot    uint64
+	parent  common.Hash
+	pending map[common.Address]types.Transactions
 }
 
-func (s *EthereumService) BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block) {
-	// Send a request to generate a full block in the background.
-	// The result can be obtained via the returned channel.
-	resCh, err := s.eth.Miner().GetSealingBlockAsync(attrs.HeadHash, uint64(attrs.Timestamp), attrs.SuggestedFeeRecipient, attrs.GasLimit, attrs.Random, false)
+// EthereumServiceOptions are the optional settings of the ethereum service, the zero value keeps the defaults.
+type EthereumServiceOptions struct {
+	// Strategy is the default build strategy, BuildStrategyGetPayload if not set
+	Strategy        BuildStrategy
+	ProfitBreakdown bool
+	PinMempool      bool
+	// ClientVersion is the node's name as returned by web3_clientVersion
+	ClientVersion string
+	// ProposerPaymentKey is set as the miner's builder transaction signing key, with which the miner pays the
+	// proposer the etherbase's earnings in the block as its last transaction. The miner's key from
+	// BUILDER_TX_SIGNING_KEY is kept if nil. The key must be the one of the miner's etherbase, which earns the fees
+	ProposerPaymentKey *ecdsa.PrivateKey
+	// ExtraDataProvider returns the extra data of the blocks built for a slot, the miner's extra data is kept if nil
+	// or if the returned extra data exceeds 32 bytes
+	ExtraDataProvider func(slot uint64) []byte
+}
+
+// NewEthereumService returns the service building with the node's eth backend, clientVersion is the node's name
+// as returned by web3_clientVersion.
+func NewEthereumService(eth *eth.Ethereum, strategy BuildStrategy, profitBreakdown bool, pinMempool bool, clientVersion string) *EthereumService {
+	return NewEthereumServiceWithOptions(eth, EthereumServiceOptions{Strategy: strategy, ProfitBreakdown: profitBreakdown, PinMempool: pinMempool, ClientVersion: clientVersion})
+}
+
+func NewEthereumServiceWithOptions(eth *eth.Ethereum, opts EthereumServiceOptions) *EthereumService {
+	strategy := opts.Strategy
+	if strategy == "" {
+		strategy = BuildStrategyGetPayload
+	}
+	if opts.ProposerPaymentKey != nil {
+		eth.Miner().SetBuilderTxSigningKey(opts.ProposerPaymentKey)
+	}
+	return &EthereumService{
+		eth:             eth,
+		strategy:        strategy,
+		profitBreakdown: opts.ProfitBreakdown,
+		pinMempool:      opts.PinMempool,
+		clientVersion:   opts.ClientVersion,
+
+		extraDataProvider: opts.ExtraDataProvider,
+	}
+}
+
+// BuildBlock builds a block using the configured strategy, which can be overridden per call with attrs.BuildParams.
+// The profit breakdown is only computed if enabled.
+func (s *EthereumService) BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown) {
+	executableData, block, breakdown, _, err := s.BuildBlockWithStats(attrs)
+	if err != nil {
+		log.Error("could not build block", "err", err, "slot", attrs.Slot)
+	}
+	return executableData, block, breakdown
+}
+
+// BuildBlockWithStats is BuildBlock, also returning the timings of the build. The error is the miner's if it could
+// not seal the block, ErrProposerPayment if it could not pay the proposer.
+func (s *EthereumService) BuildBlockWithStats(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown, BuildStats, error) {
+	start := time.Now()
+	executableData, block, bundles, err := s.buildBlock(attrs)
+	build := time.Since(start)
+	stats := func() BuildStats { return BuildStats{Total: time.Since(start), Build: build} }
 	if err != nil {
-		log.Error("Failed to create async sealing payload", "err", err)
-		return nil, nil
+		return nil, nil, nil, stats(), err
+	}
+
+	if block != nil && s.extraDataProvider != nil {
+		extra := s.extraDataProvider(attrs.Slot)
+		if err := checkExtraData(extra); err != nil {
+			log.Error("could not set the slot's extra data, keeping the miner's", "err", err, "slot", attrs.Slot)
+		} else {
+			block = withExtraData(block, extra)
+			executableData = beacon.BlockToExecutableData(block)
+		}
+	}
+	if block == nil || !s.profitBreakdown {
+		return executableData, block, nil, stats(), nil
 	}
 
-	timer := time.NewTimer(4 * time.Second)
+	breakdown, err := s.getProfitBreakdown(block, bundles)
+	if err != nil {
+		log.Error("could not compute profit breakdown", "err", err, "block_hash", block.Hash())
+	}
+	return executableData, block, breakdown, stats(), nil
+}
+
+// buildBlock builds the block for the attributes, also returning the bundles included in the block. The error is the
+// miner's if it could not seal the block, the blocks dropped for the build parameters are logged.
+func (s *EthereumService) buildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *bundleSet, error) {
+	strategy := s.strategy
+	if attrs.BuildParams != nil && attrs.BuildParams.Strategy != "" {
+		strategy = attrs.BuildParams.Strategy
+	}
+
+	var pending map[common.Address]types.Transactions
+	if s.pinMempool || (attrs.BuildParams != nil && attrs.BuildParams.PinMempool) {
+		pending = s.pinnedPending(attrs)
+	}
+
+	var minPriorityFee *big.Int
+	if attrs.BuildParams != nil && attrs.BuildParams.MinPriorityFee != nil && attrs.BuildParams.MinPriorityFee.ToInt().Sign() > 0 {
+		minPriorityFee = attrs.BuildParams.MinPriorityFee.ToInt()
+	}
+	var maxTransactions int
+	if attrs.BuildParams != nil && attrs.BuildParams.MaxTransactions > 0 {
+		maxTransactions = attrs.BuildParams.MaxTransactions
+	}
+
+	// The bundles are kept apart from the pending transactions, so that the other parameters' filters don't break
+	// them up and the miner includes each of them as a unit. The bundles with a transaction the filters would drop
+	// are dropped
+	bundles := newBundleSet(attrs.BuildParams, types.LatestSigner(s.eth.BlockChain().Config()))
+
+	policy := newTxPolicy(attrs.BuildParams)
+	if policy != nil {
+		if pending == nil {
+			pending = s.eth.TxPool().Pending(true)
+		}
+		pending = policy.filter(pending)
+		bundles = bundles.filter(policy.checkBundle)
+	}
+
+	if minPriorityFee != nil || maxTransactions > 0 {
+		// An empty head hash builds on the chain head, as in the miner
+		parent := s.eth.BlockChain().CurrentHeader()
+		if attrs.HeadHash != (common.Hash{}) {
+			parent = s.eth.BlockChain().GetHeaderByHash(attrs.HeadHash)
+		}
+		if parent == nil {
+			log.Error("parent block not found, can't select the transactions", "parent_hash", attrs.HeadHash)
+			return nil, nil, nil, nil
+		}
+		if pending == nil {
+			pending = s.eth.TxPool().Pending(true)
+		}
+
+		chainConfig := s.eth.BlockChain().Config()
+		baseFee := misc.CalcBaseFee(chainConfig, parent)
+		if minPriorityFee != nil {
+			pending = filterByMinPriorityFee(pending, baseFee, minPriorityFee)
+			bundles = bundles.filter(func(b *bundle) error { return checkBundleMinPriorityFee(b, baseFee, minPriorityFee) })
+		}
+		if maxTransactions > 0 {
+			pending = limitTransactions(pending, types.LatestSigner(chainConfig), baseFee, maxTransactions)
+		}
+	}
+
+	build := func(bundles []types.Transactions) (*beacon.ExecutableDataV1, *types.Block, error) {
+		switch strategy {
+		case BuildStrategyCustom:
+			iterations := defaultCustomBuildIterations
+			if attrs.BuildParams != nil && attrs.BuildParams.Iterations > 0 {
+				iterations = attrs.BuildParams.Iterations
+			}
+			if iterations > maxCustomBuildIterations {
+				log.Debug("capping the custom build iterations", "iterations", iterations, "max", maxCustomBuildIterations)
+				iterations = maxCustomBuildIterations
+			}
+			return s.buildBestBlock(attrs, iterations, pending, bundles)
+		case BuildStrategyGetPayload:
+			return s.buildBlockGetPayload(attrs, pending, bundles)
+		default:
+			log.Error("unknown build strategy", "strategy", strategy)
+			return nil, nil, nil
+		}
+	}
+	checkBlock := func(block *types.Block) error {
+		if minPriorityFee != nil {
+			if err := checkMinPriorityFee(block, minPriorityFee); err != nil {
+				return fmt.Errorf("minimum priority fee: %w", err)
+			}
+		}
+		if maxTransactions > 0 {
+			if err := checkMaxTransactions(block, maxTransactions); err != nil {
+				return fmt.Errorf("maximum transactions: %w", err)
+			}
+		}
+		if policy != nil {
+			return policy.check(block, types.LatestSigner(s.eth.BlockChain().Config()))
+		}
+		return nil
+	}
+
+	executableData, block, err := build(bundles.transactions())
+	if block != nil && bundles != nil {
+		if checkErr := bundles.check(block); checkErr != nil {
+			log.Error("built block does not include the bundles intact, building without them", "err", checkErr, "block_hash", block.Hash())
+			bundles = nil
+			executableData, block, err = build(nil)
+		}
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// The block is built again without the bundles if they break the build parameters, as the bundles are included
+	// as a unit past the txpool's filters
+	if block != nil && bundles != nil {
+		if checkErr := checkBlock(block); checkErr != nil {
+			log.Error("built block with the bundles does not respect the build parameters, building without them", "err", checkErr, "block_hash", block.Hash())
+			bundles = nil
+			if executableData, block, err = build(nil); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+	if block != nil {
+		if err := checkBlock(block); err != nil {
+			log.Error("built block does not respect the build parameters", "err", err, "block_hash", block.Hash())
+			return nil, nil, nil, nil
+		}
+	}
+	return executableData, block, bundles, nil
+}
+
+// pinnedPending returns the txpool snapshot of the slot, taking it on the slot's first build.
+func (s *EthereumService) pinnedPending(attrs *BuilderPayloadAttributes) map[common.Address]types.Transactions {
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	if s.snapshot == nil || s.snapshot.slot != attrs.Slot || s.snapshot.parent != attrs.HeadHash {
+		s.snapshot = &mempoolSnapshot{
+			slot:    attrs.Slot,
+			parent:  attrs.HeadHash,
+			pending: s.eth.TxPool().Pending(true),
+		}
+	}
+	return s.snapshot.pending
+}
+
+// sealBlock builds a block from the txpool, or from the pending transactions if pinned, with the bundles at the top.
+func (s *EthereumService) sealBlock(attrs *BuilderPayloadAttributes, pending map[common.Address]types.Transactions, bundles []types.Transactions) (*types.Block, error) {
+	if len(bundles) > 0 {
+		return s.eth.Miner().GetSealingBlockWithBundles(attrs.HeadHash, uint64(attrs.Timestamp), attrs.SuggestedFeeRecipient, attrs.GasLimit, attrs.Random, pending, bundles)
+	}
+	if pending != nil {
+		return s.eth.Miner().GetSealingBlockWithPending(attrs.HeadHash, uint64(attrs.Timestamp), attrs.SuggestedFeeRecipient, attrs.GasLimit, attrs.Random, pending)
+	}
+	return s.eth.Miner().GetSealingBlockSync(attrs.HeadHash, uint64(attrs.Timestamp), attrs.SuggestedFeeRecipient, attrs.GasLimit, attrs.Random, false)
+}
+
+func (s *EthereumService) buildBlockGetPayload(attrs *BuilderPayloadAttributes, pending map[common.Address]types.Transactions, bundles []types.Transactions) (*beacon.ExecutableDataV1, *types.Block, error) {
+	// Generate a full block in the background, keeping the miner's error
+	type sealResult struct {
+		block *types.Block
+		err   error
+	}
+	resCh := make(chan sealResult, 1)
+	go func() {
+		block, err := s.sealBlock(attrs, pending, bundles)
+		resCh <- sealResult{block, err}
+	}()
+
+	timer := time.NewTimer(buildBlockTimeout)
 	defer timer.Stop()
 
 	select {
-	case block := <-resCh:
-		if block == nil {
+	case res := <-resCh:
+		if res.err != nil {
+			return nil, nil, res.err
+		}
+		if res.block == nil {
 			log.Error("received nil block from sealing work")
-			return nil, nil
+			return nil, nil, nil
 		}
-		return beacon.BlockToExecutableData(block), block
+		return beacon.BlockToExecutableData(res.block), res.block, nil
 	case <-timer.C:
-		log.Error("timeout waiting for block", "parent hash", attrs.HeadHash, "slot", attrs.Slot)
-		return nil, nil
+		log.Error("timeout waiting for block", "parent_hash", attrs.HeadHash, "slot", attrs.Slot)
+		return nil, nil, nil
+	}
+}
+
+// buildBestBlock builds the block with each prefix of the bundles, from all of them down to none, up to iterations
+// blocks within the build timeout, and returns the most profitable one. The bundles are included at the top of the
+// block in order, a bundle displacing more valuable txpool transactions lowers the block's profit. Without bundles a
+// single block is built, as the builds would be identical.
+func (s *EthereumService) buildBestBlock(attrs *BuilderPayloadAttributes, iterations int, pending map[common.Address]types.Transactions, bundles []types.Transactions) (*beacon.ExecutableDataV1, *types.Block, error) {
+	deadline := time.Now().Add(buildBlockTimeout)
+
+	if iterations > len(bundles)+1 {
+		iterations = len(bundles) + 1
+	}
+
+	var bestBlock *types.Block
+	var lastErr error
+	for i := 0; i < iterations && time.Now().Before(deadline); i++ {
+		block, err := s.sealBlock(attrs, pending, bundles[:len(bundles)-i])
+		if err != nil || block == nil {
+			log.Error("could not build block", "iteration", i, "bundles", len(bundles)-i, "err", err)
+			if err != nil {
+				lastErr = err
+			}
+			continue
+		}
+
+		if bestBlock == nil || block.Profit.Cmp(bestBlock.Profit) > 0 {
+			bestBlock = block
+		}
+	}
+
+	if bestBlock == nil {
+		log.Error("no block built", "parent_hash", attrs.HeadHash, "slot", attrs.Slot, "iterations", iterations)
+		return nil, nil, lastErr
+	}
+
+	return beacon.BlockToExecutableData(bestBlock), bestBlock, nil
+}
+
+// SimulateBlock validates the block's body and executes it on the parent state, checking the resulting state root,
+// receipts, logs bloom and gas used against the header. Returns ErrBloomMismatch if the bloom does not match the
+// receipts. The header's consensus fields are not verified.
+func (s *EthereumService) SimulateBlock(ctx context.Context, block *types.Block) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	chain := s.eth.BlockChain()
+	parent := chain.GetBlockByHash(block.ParentHash())
+	if parent == nil {
+		return errors.New("parent block not found")
+	}
+
+	if err := chain.Validator().ValidateBody(block); err != nil {
+		return err
 	}
+
+	statedb, err := chain.StateAt(parent.Root())
+	if err != nil {
+		return err
+	}
+
+	receipts, _, usedGas, err := chain.Processor().Process(block, statedb, *chain.GetVMConfig())
+	if err != nil {
+		return err
+	}
+	if types.CreateBloom(receipts) != block.Bloom() {
+		return fmt.Errorf("%w: block %s", ErrBloomMismatch, block.Hash())
+	}
+	return chain.Validator().ValidateState(block, statedb, receipts, usedGas)
+}
+
+// getProfitBreakdown executes the block on its parent state one transaction at a time, to split the coinbase's
+// earnings by transaction.
+func (s *EthereumService) getProfitBreakdown(block *types.Block, bundles *bundleSet) (*ProfitBreakdown, error) {
+	chain := s.eth.BlockChain()
+	parent := chain.GetBlockByHash(block.ParentHash())
+	if parent == nil {
+		return nil, errors.New("parent block not found")
+	}
+
+	statedb, err := chain.StateAt(parent.Root())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		coinbase = block.Coinbase()
+		header   = block.Header()
+		gasPool  = new(core.GasPool).AddGas(block.GasLimit())
+		usedGas  uint64
+		receipts = make(types.Receipts, 0, len(block.Transactions()))
+		deltas   = make([]*big.Int, 0, len(block.Transactions()))
+	)
+	for i, tx := range block.Transactions() {
+		balanceBefore := new(big.Int).Set(statedb.GetBalance(coinbase))
+		statedb.Prepare(tx.Hash(), i)
+		receipt, err := core.ApplyTransaction(chain.Config(), chain, &coinbase, gasPool, statedb, header, tx, &usedGas, *chain.GetVMConfig())
+		if err != nil {
+			return nil, fmt.Errorf("could not apply transaction %d (%s): %w", i, tx.Hash(), err)
+		}
+		receipts = append(receipts, receipt)
+		deltas = append(deltas, new(big.Int).Sub(statedb.GetBalance(coinbase), balanceBefore))
+	}
+	return computeProfitBreakdown(block, receipts, deltas, bundles.hashes(), types.LatestSigner(chain.Config()))
 }
 
 func (s *EthereumService) GetBlockByHash(hash common.Hash) *types.Block {
 	return s.eth.BlockChain().GetBlockByHash(hash)
 }
 
+func (s *EthereumService) CurrentBlock() *types.Block {
+	return s.eth.BlockChain().CurrentBlock()
+}
+
+func (s *EthereumService) GetCanonicalHash(number uint64) common.Hash {
+	return s.eth.BlockChain().GetCanonicalHash(number)
+}
+
 func (s *EthereumService) Synced() bool {
 	return s.eth.Synced()
 }
+
+func (s *EthereumService) ClientVersion() string {
+	return s.clientVersion
+}
@@ -1,6 +1,8 @@
 package builder
 
 import (
+	"context"
+	"crypto/ecdsa"
 	"math/big"
 	"testing"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
@@ -21,17 +24,21 @@ import (
 )
 
 func generatePreMergeChain(n int) (*core.Genesis, []*types.Block) {
+	return generatePreMergeChainWithAlloc(n, core.GenesisAlloc{})
+}
+
+func generatePreMergeChainWithAlloc(n int, alloc core.GenesisAlloc) (*core.Genesis, []*types.Block) {
 	db := rawdb.NewMemoryDatabase()
 	config := params.AllEthashProtocolChanges
 	genesis := &core.Genesis{
 		Config:     config,
-		Alloc:      core.GenesisAlloc{},
+		Alloc:      alloc,
 		ExtraData:  []byte("test genesis"),
 		Timestamp:  9000,
 		BaseFee:    big.NewInt(params.InitialBaseFee),
 		Difficulty: big.NewInt(0),
 	}
-	gblock := genesis.ToBlock()
+	gblock := genesis.MustCommit(db)
 	engine := ethash.NewFaker()
 	blocks, _ := core.GenerateChain(config, gblock, engine, db, n, nil)
 	totalDifficulty := big.NewInt(0)
@@ -89,8 +96,8 @@ func TestBuildBlock(t *testing.T) {
 		Slot:                  uint64(25),
 	}
 
-	service := NewEthereumService(ethservice)
-	executableData, block := service.BuildBlock(testPayloadAttributes)
+	service := NewEthereumService(ethservice, BuildStrategyGetPayload, true, false, "")
+	executableData, block, profitBreakdown := service.BuildBlock(testPayloadAttributes)
 
 	//require.Equal(t, common.Address{0x04, 0x10}, executableData.FeeRecipient)
 	require.Equal(t, common.Hash{0x05, 0x10}, executableData.Random)
@@ -99,4 +106,398 @@ func TestBuildBlock(t *testing.T) {
 	require.Equal(t, block.ParentHash(), parent.Hash())
 	require.Equal(t, block.Hash(), executableData.BlockHash)
 	require.Equal(t, block.Profit.Uint64(), uint64(0))
+	require.NotNil(t, profitBreakdown)
+	require.Equal(t, uint64(0), profitBreakdown.PriorityFees.Uint64())
+
+	// The seal duration excludes the profit breakdown
+	_, _, _, stats, err := service.BuildBlockWithStats(testPayloadAttributes)
+	require.NoError(t, err)
+	require.Greater(t, stats.Build, time.Duration(0))
+	require.GreaterOrEqual(t, stats.Total, stats.Build)
+
+	testPayloadAttributes.BuildParams = &BuildParams{Strategy: BuildStrategyCustom, Iterations: 2}
+	executableData, block, _ = service.BuildBlock(testPayloadAttributes)
+	require.NotNil(t, executableData)
+	require.Equal(t, parent.Hash(), executableData.ParentHash)
+	require.Equal(t, block.Hash(), executableData.BlockHash)
+
+	testPayloadAttributes.BuildParams.PinMempool = true
+	executableData, block, _ = service.BuildBlock(testPayloadAttributes)
+	require.NotNil(t, executableData)
+	require.Equal(t, block.Hash(), executableData.BlockHash)
+	require.NotNil(t, service.snapshot)
+	require.Equal(t, uint64(25), service.snapshot.slot)
+
+	// The snapshot is kept for the slot's builds and retaken for the next slot
+	snapshot := service.snapshot
+	service.BuildBlock(testPayloadAttributes)
+	require.Same(t, snapshot, service.snapshot)
+
+	testPayloadAttributes.Slot = 26
+	service.BuildBlock(testPayloadAttributes)
+	require.NotSame(t, snapshot, service.snapshot)
+	require.Equal(t, uint64(26), service.snapshot.slot)
+
+	testPayloadAttributes.BuildParams = &BuildParams{MinPriorityFee: (*hexutil.Big)(big.NewInt(params.GWei))}
+	executableData, block, _ = service.BuildBlock(testPayloadAttributes)
+	require.NotNil(t, executableData)
+	require.Equal(t, block.Hash(), executableData.BlockHash)
+
+	testPayloadAttributes.BuildParams = &BuildParams{MaxTransactions: 1}
+	executableData, block, _ = service.BuildBlock(testPayloadAttributes)
+	require.NotNil(t, executableData)
+	require.LessOrEqual(t, len(block.Transactions()), 1)
+
+	// Blocks missing a required transaction are dropped
+	testPayloadAttributes.BuildParams = &BuildParams{RequiredTxs: []common.Hash{{0x01}}}
+	executableData, block, _ = service.BuildBlock(testPayloadAttributes)
+	require.Nil(t, executableData)
+	require.Nil(t, block)
+}
+
+func TestSimulateBlock(t *testing.T) {
+	genesis, blocks := generatePreMergeChain(10)
+	n, ethservice := startEthService(t, genesis, blocks)
+	defer n.Close()
+
+	parent := ethservice.BlockChain().CurrentBlock()
+	service := NewEthereumService(ethservice, BuildStrategyGetPayload, false, false, "")
+	_, block, _ := service.BuildBlock(&BuilderPayloadAttributes{
+		Timestamp:             hexutil.Uint64(parent.Time() + 1),
+		Random:                common.Hash{0x05, 0x10},
+		SuggestedFeeRecipient: common.Address{0x04, 0x10},
+		GasLimit:              uint64(4800000),
+		Slot:                  uint64(25),
+	})
+	require.NotNil(t, block)
+	require.NoError(t, service.SimulateBlock(context.Background(), block))
+
+	header := block.Header()
+	header.Root = common.Hash{0x01}
+	require.Error(t, service.SimulateBlock(context.Background(), types.NewBlockWithHeader(header).WithBody(block.Transactions(), nil)))
+
+	header = block.Header()
+	header.Bloom = types.Bloom{0x01}
+	require.ErrorIs(t, service.SimulateBlock(context.Background(), types.NewBlockWithHeader(header).WithBody(block.Transactions(), nil)), ErrBloomMismatch)
+
+	header = block.Header()
+	header.ParentHash = common.Hash{0x01}
+	require.Error(t, service.SimulateBlock(context.Background(), types.NewBlockWithHeader(header).WithBody(block.Transactions(), nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, service.SimulateBlock(ctx, block), context.Canceled)
+}
+
+func TestMinPriorityFee(t *testing.T) {
+	baseFee := big.NewInt(10)
+	accountA, accountB := common.Address{0x0a}, common.Address{0x0b}
+	pending := map[common.Address]types.Transactions{
+		accountA: {
+			types.NewTx(&types.DynamicFeeTx{Nonce: 0, GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(20)}),
+			// Tip capped to 2 by the fee cap, drops the later nonces as well
+			types.NewTx(&types.DynamicFeeTx{Nonce: 1, GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(12)}),
+			types.NewTx(&types.DynamicFeeTx{Nonce: 2, GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(20)}),
+		},
+		accountB: {
+			types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(11)}),
+		},
+	}
+
+	filtered := filterByMinPriorityFee(pending, baseFee, big.NewInt(3))
+	require.Len(t, filtered, 1)
+	require.Len(t, filtered[accountA], 1)
+	require.Equal(t, uint64(0), filtered[accountA][0].Nonce())
+	// The pending transactions are not modified
+	require.Len(t, pending[accountA], 3)
+
+	block := types.NewBlockWithHeader(&types.Header{BaseFee: baseFee}).WithBody(filtered[accountA], nil)
+	require.NoError(t, checkMinPriorityFee(block, big.NewInt(3)))
+
+	block = types.NewBlockWithHeader(&types.Header{BaseFee: baseFee}).WithBody(pending[accountA], nil)
+	require.Error(t, checkMinPriorityFee(block, big.NewInt(3)))
+
+	// The proposer payment is not checked
+	block = types.NewBlockWithHeader(&types.Header{BaseFee: baseFee}).WithBody(append(filtered[accountA], pending[accountB]...), nil)
+	block.Profit = big.NewInt(1)
+	require.NoError(t, checkMinPriorityFee(block, big.NewInt(3)))
+}
+
+func TestMaxTransactions(t *testing.T) {
+	baseFee := big.NewInt(10)
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	keyA, _ := crypto.GenerateKey()
+	keyB, _ := crypto.GenerateKey()
+	signTx := func(key *ecdsa.PrivateKey, nonce uint64, tip int64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: nonce, GasTipCap: big.NewInt(tip), GasFeeCap: big.NewInt(100)})
+	}
+	accountA, accountB := crypto.PubkeyToAddress(keyA.PublicKey), crypto.PubkeyToAddress(keyB.PublicKey)
+	pending := map[common.Address]types.Transactions{
+		accountA: {signTx(keyA, 0, 5), signTx(keyA, 1, 1)},
+		accountB: {signTx(keyB, 0, 3)},
+	}
+
+	// The transactions are taken by tip, in nonce order
+	limited := limitTransactions(pending, signer, baseFee, 2)
+	require.Len(t, limited[accountA], 1)
+	require.Equal(t, uint64(0), limited[accountA][0].Nonce())
+	require.Len(t, limited[accountB], 1)
+	// The pending transactions are not modified
+	require.Len(t, pending[accountA], 2)
+
+	require.Len(t, limitTransactions(pending, signer, baseFee, 10)[accountA], 2)
+
+	block := types.NewBlockWithHeader(&types.Header{BaseFee: baseFee}).WithBody(pending[accountA], nil)
+	require.NoError(t, checkMaxTransactions(block, 2))
+	require.Error(t, checkMaxTransactions(block, 1))
+
+	// The proposer payment is not counted
+	block.Profit = big.NewInt(1)
+	require.NoError(t, checkMaxTransactions(block, 1))
+}
+
+func TestComputeProfitBreakdown(t *testing.T) {
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	coinbaseKey, _ := crypto.GenerateKey()
+	senderKey, _ := crypto.GenerateKey()
+	coinbase, proposer := crypto.PubkeyToAddress(coinbaseKey.PublicKey), common.Address{0x04, 0x10}
+	signTx := func(key *ecdsa.PrivateKey, tx types.TxData) *types.Transaction {
+		return types.MustSignNewTx(key, signer, tx)
+	}
+	txs := types.Transactions{
+		signTx(senderKey, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 0, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(20), Gas: 21000}),
+		// Tip capped to 2 by the fee cap, pays the coinbase 50_000 directly
+		signTx(senderKey, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 1, GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(12), Gas: 60000}),
+		// A bundle's transaction, paying the coinbase 30_000 on top of its tip
+		signTx(senderKey, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 2, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(20), Gas: 21000}),
+		// The payout tips 1 per gas, which goes back to the coinbase
+		signTx(coinbaseKey, &types.LegacyTx{Nonce: 0, To: &proposer, Value: big.NewInt(100_000), GasPrice: big.NewInt(11), Gas: 21000}),
+	}
+	receipts := types.Receipts{{GasUsed: 21000}, {GasUsed: 45000}, {GasUsed: 21000}, {GasUsed: 21000}}
+	coinbaseDeltas := []*big.Int{
+		big.NewInt(2 * 21000),
+		big.NewInt(2*45000 + 50_000),
+		big.NewInt(21000 + 30_000),
+		big.NewInt(-100_000 - 11*21000 + 21000),
+	}
+
+	block := types.NewBlockWithHeader(&types.Header{BaseFee: big.NewInt(10), Coinbase: coinbase}).WithBody(txs, nil)
+	block.Profit = big.NewInt(100_000)
+	bundled := map[common.Hash]struct{}{txs[2].Hash(): {}}
+
+	breakdown, err := computeProfitBreakdown(block, receipts, coinbaseDeltas, bundled, signer)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(42_000+90_000), breakdown.PriorityFees)
+	require.Equal(t, big.NewInt(50_000), breakdown.DirectPayments)
+	require.Equal(t, big.NewInt(51_000), breakdown.BundlePayments)
+	// The payout's base fee, its tip is paid to the coinbase itself
+	require.Equal(t, big.NewInt(210_000), breakdown.PaymentTxFee)
+	// The base fee of the 108000 gas used, including the payment's
+	require.Equal(t, big.NewInt(1_080_000), breakdown.BaseFeeBurned)
+	require.Equal(t, 3, breakdown.PaymentIndex)
+
+	// Without the payout, its transaction is one of the block's
+	block.Profit = big.NewInt(0)
+	breakdown, err = computeProfitBreakdown(block, receipts, coinbaseDeltas, bundled, signer)
+	require.NoError(t, err)
+	require.Zero(t, breakdown.PaymentTxFee.Sign())
+	require.Equal(t, big.NewInt(42_000+90_000+21000), breakdown.PriorityFees)
+	require.Equal(t, -1, breakdown.PaymentIndex)
+
+	_, err = computeProfitBreakdown(block, receipts[:1], coinbaseDeltas, bundled, signer)
+	require.Error(t, err)
+	_, err = computeProfitBreakdown(block, receipts, coinbaseDeltas[:1], bundled, signer)
+	require.Error(t, err)
+}
+
+func TestParseBuildStrategy(t *testing.T) {
+	strategy, err := ParseBuildStrategy("")
+	require.NoError(t, err)
+	require.Equal(t, BuildStrategyGetPayload, strategy)
+
+	strategy, err = ParseBuildStrategy("custom")
+	require.NoError(t, err)
+	require.Equal(t, BuildStrategyCustom, strategy)
+
+	_, err = ParseBuildStrategy("greedy")
+	require.Error(t, err)
+}
+
+func TestBuildBlockProposerPayment(t *testing.T) {
+	senderKey, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(senderKey.PublicKey)
+	genesis, blocks := generatePreMergeChainWithAlloc(10, core.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}})
+	n, ethservice := startEthService(t, genesis, blocks)
+	defer n.Close()
+
+	paymentKey, _ := crypto.GenerateKey()
+	ethservice.SetEtherbase(crypto.PubkeyToAddress(paymentKey.PublicKey))
+
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   genesis.Config.ChainID,
+		Nonce:     0,
+		To:        &common.Address{0x01},
+		Value:     big.NewInt(1),
+		Gas:       params.TxGas,
+		GasFeeCap: big.NewInt(10 * params.GWei),
+		GasTipCap: big.NewInt(params.GWei),
+	}), types.LatestSignerForChainID(genesis.Config.ChainID), senderKey)
+	require.NoError(t, err)
+	require.NoError(t, ethservice.TxPool().AddLocal(tx))
+
+	parent := ethservice.BlockChain().CurrentBlock()
+	feeRecipient := common.Address{0x04, 0x10}
+	attrs := &BuilderPayloadAttributes{
+		Timestamp:             hexutil.Uint64(parent.Time() + 1),
+		Random:                common.Hash{0x05, 0x10},
+		SuggestedFeeRecipient: feeRecipient,
+		GasLimit:              uint64(4800000),
+		Slot:                  uint64(25),
+	}
+	service := NewEthereumServiceWithOptions(ethservice, EthereumServiceOptions{ProposerPaymentKey: paymentKey})
+	executableData, block, _, _, err := service.BuildBlockWithStats(attrs)
+	require.NoError(t, err)
+	require.NotNil(t, block)
+	require.Equal(t, block.Hash(), executableData.BlockHash)
+
+	// The miner pays the proposer as the block's last transaction
+	txs := block.Transactions()
+	require.Len(t, txs, 2)
+	require.Equal(t, tx.Hash(), txs[0].Hash())
+	payment := txs[1]
+	require.Equal(t, feeRecipient, *payment.To())
+	require.Equal(t, block.Profit, payment.Value())
+	require.Positive(t, block.Profit.Sign())
+	require.Equal(t, 2*params.TxGas, block.GasUsed())
+	require.NoError(t, service.SimulateBlock(context.Background(), block))
+
+	// Blocks without earnings are kept without a payment
+	attrs.BuildParams = &BuildParams{DeniedTxs: []common.Hash{tx.Hash()}}
+	_, block, _, _, err = service.BuildBlockWithStats(attrs)
+	require.NoError(t, err)
+	require.NotNil(t, block)
+	require.Empty(t, block.Transactions())
+	require.Zero(t, block.Profit.Sign())
+
+	// The payment must be signed by the etherbase, which earned the fees
+	otherKey, _ := crypto.GenerateKey()
+	ethservice.Miner().SetBuilderTxSigningKey(otherKey)
+	attrs.BuildParams = nil
+	_, block, _, _, err = service.BuildBlockWithStats(attrs)
+	require.ErrorIs(t, err, ErrProposerPayment)
+	require.Nil(t, block)
+}
+
+func TestBuildBlockBundles(t *testing.T) {
+	keyA, _ := crypto.GenerateKey()
+	keyB, _ := crypto.GenerateKey()
+	accountA, accountB := crypto.PubkeyToAddress(keyA.PublicKey), crypto.PubkeyToAddress(keyB.PublicKey)
+	genesis, blocks := generatePreMergeChainWithAlloc(10, core.GenesisAlloc{
+		accountA: {Balance: big.NewInt(params.Ether)},
+		accountB: {Balance: big.NewInt(params.Ether)},
+	})
+	n, ethservice := startEthService(t, genesis, blocks)
+	defer n.Close()
+
+	signer := types.LatestSignerForChainID(genesis.Config.ChainID)
+	signTx := func(key *ecdsa.PrivateKey, nonce uint64, tip int64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   genesis.Config.ChainID,
+			Nonce:     nonce,
+			To:        &common.Address{0x01},
+			Value:     big.NewInt(1),
+			Gas:       params.TxGas,
+			GasFeeCap: big.NewInt(10 * params.GWei),
+			GasTipCap: big.NewInt(tip),
+		})
+	}
+	hint := func(txs ...*types.Transaction) BundleHint {
+		var hint BundleHint
+		for _, tx := range txs {
+			encoded, err := tx.MarshalBinary()
+			require.NoError(t, err)
+			hint.Txs = append(hint.Txs, encoded)
+		}
+		return hint
+	}
+	hashes := func(txs types.Transactions) []common.Hash {
+		hashes := make([]common.Hash, len(txs))
+		for i, tx := range txs {
+			hashes[i] = tx.Hash()
+		}
+		return hashes
+	}
+
+	// The txpool's transaction pays a higher tip than the bundle's, which still goes first
+	pooled := signTx(keyA, 0, 2*params.GWei)
+	require.NoError(t, ethservice.TxPool().AddLocal(pooled))
+	bundleB0, bundleA0 := signTx(keyB, 0, 1), signTx(keyA, 0, 1)
+
+	parent := ethservice.BlockChain().CurrentBlock()
+	attrs := &BuilderPayloadAttributes{
+		Timestamp:             hexutil.Uint64(parent.Time() + 1),
+		Random:                common.Hash{0x05, 0x10},
+		SuggestedFeeRecipient: common.Address{0x04, 0x10},
+		GasLimit:              uint64(4800000),
+		Slot:                  uint64(25),
+		BuildParams:           &BuildParams{Bundles: []BundleHint{hint(bundleB0, bundleA0)}},
+	}
+	service := NewEthereumService(ethservice, BuildStrategyGetPayload, true, false, "")
+
+	// The multi-sender bundle is included in order at the top, replacing the pooled transaction of the same nonce
+	_, block, breakdown := service.BuildBlock(attrs)
+	require.NotNil(t, block)
+	require.Equal(t, hashes(types.Transactions{bundleB0, bundleA0}), hashes(block.Transactions()))
+	require.NoError(t, service.SimulateBlock(context.Background(), block))
+	// The bundle's tips are its payments
+	require.Equal(t, new(big.Int).SetUint64(2*params.TxGas), breakdown.BundlePayments)
+	require.Zero(t, breakdown.PriorityFees.Sign())
+
+	// A bundle failing part way is left out entirely, the block is built without it
+	bundleNonceGap := signTx(keyB, 5, params.GWei)
+	attrs.BuildParams = &BuildParams{Bundles: []BundleHint{hint(bundleA0, bundleNonceGap)}}
+	_, block, breakdown = service.BuildBlock(attrs)
+	require.NotNil(t, block)
+	require.Equal(t, hashes(types.Transactions{pooled}), hashes(block.Transactions()))
+	require.Zero(t, breakdown.BundlePayments.Sign())
+	require.Equal(t, big.NewInt(2*params.GWei*int64(params.TxGas)), breakdown.PriorityFees)
+
+	// The custom strategy includes the bundles the same way
+	attrs.BuildParams = &BuildParams{Strategy: BuildStrategyCustom, Iterations: 1, Bundles: []BundleHint{hint(bundleB0, bundleA0)}}
+	_, block, _ = service.BuildBlock(attrs)
+	require.NotNil(t, block)
+	require.Equal(t, hashes(types.Transactions{bundleB0, bundleA0}), hashes(block.Transactions()))
+
+	// A bundle below the minimum priority fee is dropped before the build, the block is built without it
+	bundleZeroTip := signTx(keyB, 0, 0)
+	attrs.BuildParams = &BuildParams{MinPriorityFee: (*hexutil.Big)(big.NewInt(params.GWei)), Bundles: []BundleHint{hint(bundleZeroTip)}}
+	_, block, breakdown = service.BuildBlock(attrs)
+	require.NotNil(t, block)
+	require.Equal(t, hashes(types.Transactions{pooled}), hashes(block.Transactions()))
+	require.Zero(t, breakdown.BundlePayments.Sign())
+
+	// So is a bundle of a denied sender
+	attrs.BuildParams = &BuildParams{DeniedSenders: []common.Address{accountB}, Bundles: []BundleHint{hint(bundleB0, bundleA0)}}
+	_, block, _ = service.BuildBlock(attrs)
+	require.NotNil(t, block)
+	require.Equal(t, hashes(types.Transactions{pooled}), hashes(block.Transactions()))
+
+	// A bundle taking the block past the maximum transactions is left out by building again without the bundles
+	attrs.BuildParams = &BuildParams{MaxTransactions: 1, Bundles: []BundleHint{hint(bundleB0, bundleA0)}}
+	_, block, _ = service.BuildBlock(attrs)
+	require.NotNil(t, block)
+	require.Equal(t, hashes(types.Transactions{pooled}), hashes(block.Transactions()))
+
+	// With more iterations the custom strategy also builds without the bundle, whose tips are below the pooled
+	// transaction's. The profits are compared with the proposer payment, without which they are all zero
+	paymentKey, _ := crypto.GenerateKey()
+	ethservice.SetEtherbase(crypto.PubkeyToAddress(paymentKey.PublicKey))
+	service = NewEthereumServiceWithOptions(ethservice, EthereumServiceOptions{ProfitBreakdown: true, ProposerPaymentKey: paymentKey})
+	attrs.BuildParams = &BuildParams{Strategy: BuildStrategyCustom, Iterations: 5, Bundles: []BundleHint{hint(bundleB0, bundleA0)}}
+	_, block, breakdown = service.BuildBlock(attrs)
+	require.NotNil(t, block)
+	require.Len(t, block.Transactions(), 2)
+	require.Equal(t, pooled.Hash(), block.Transactions()[0].Hash())
+	require.Positive(t, block.Profit.Sign())
+	require.Zero(t, breakdown.BundlePayments.Sign())
 }
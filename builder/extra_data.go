@@ -0,0 +1,55 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// extraDataSlotPlaceholder is replaced by the slot number in the extra data templates
+const extraDataSlotPlaceholder = "{slot}"
+
+var ErrExtraDataTooLong = errors.New("extra data too long")
+
+// checkExtraData returns an error if the extra data doesn't fit in a block header.
+func checkExtraData(extra []byte) error {
+	if uint64(len(extra)) > params.MaximumExtraDataSize {
+		return fmt.Errorf("%w: %d > %d bytes", ErrExtraDataTooLong, len(extra), params.MaximumExtraDataSize)
+	}
+	return nil
+}
+
+// NewExtraDataRotation returns an extra data provider rotating among the values by slot, with the {slot}
+// placeholder of a value replaced by the slot number. Values that could exceed the extra data size for some slot
+// are rejected.
+func NewExtraDataRotation(values []string) (func(slot uint64) []byte, error) {
+	if len(values) == 0 {
+		return nil, errors.New("no extra data values")
+	}
+	longestSlot := strconv.FormatUint(math.MaxUint64, 10)
+	for _, value := range values {
+		if err := checkExtraData([]byte(strings.ReplaceAll(value, extraDataSlotPlaceholder, longestSlot))); err != nil {
+			return nil, fmt.Errorf("extra data %q: %w", value, err)
+		}
+	}
+
+	return func(slot uint64) []byte {
+		value := values[slot%uint64(len(values))]
+		return []byte(strings.ReplaceAll(value, extraDataSlotPlaceholder, strconv.FormatUint(slot, 10)))
+	}, nil
+}
+
+// withExtraData returns the block with the extra data set in its header. The extra data doesn't affect the block's
+// execution after the merge, so only the block hash changes.
+func withExtraData(block *types.Block, extra []byte) *types.Block {
+	header := types.CopyHeader(block.Header())
+	header.Extra = extra
+	withExtra := block.WithSeal(header)
+	withExtra.Profit = block.Profit
+	return withExtra
+}
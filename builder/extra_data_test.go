@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtraDataRotation(t *testing.T) {
+	provider, err := NewExtraDataRotation([]string{"builder-a", "builder-b {slot}"})
+	require.NoError(t, err)
+	require.Equal(t, []byte("builder-a"), provider(24))
+	require.Equal(t, []byte("builder-b 25"), provider(25))
+	require.Equal(t, []byte("builder-a"), provider(26))
+
+	// Values exceeding 32 bytes for some slot are rejected
+	_, err = NewExtraDataRotation([]string{strings.Repeat("a", 33)})
+	require.ErrorIs(t, err, ErrExtraDataTooLong)
+	_, err = NewExtraDataRotation([]string{strings.Repeat("a", 13) + "{slot}"})
+	require.ErrorIs(t, err, ErrExtraDataTooLong)
+	_, err = NewExtraDataRotation(nil)
+	require.Error(t, err)
+}
+
+func TestBuildBlockExtraData(t *testing.T) {
+	genesis, blocks := generatePreMergeChain(10)
+	n, ethservice := startEthService(t, genesis, blocks)
+	defer n.Close()
+
+	parent := ethservice.BlockChain().CurrentBlock()
+	attrs := &BuilderPayloadAttributes{
+		Timestamp:             hexutil.Uint64(parent.Time() + 1),
+		Random:                common.Hash{0x05, 0x10},
+		SuggestedFeeRecipient: common.Address{0x04, 0x10},
+		GasLimit:              uint64(4800000),
+		Slot:                  uint64(25),
+	}
+
+	extra := []byte("slot 25")
+	service := NewEthereumServiceWithOptions(ethservice, EthereumServiceOptions{ExtraDataProvider: func(slot uint64) []byte { return extra }})
+	executableData, block, _ := service.BuildBlock(attrs)
+	require.NotNil(t, block)
+	require.Equal(t, extra, block.Extra())
+	require.Equal(t, extra, executableData.ExtraData)
+	require.Equal(t, block.Hash(), executableData.BlockHash)
+	require.NotNil(t, block.Profit)
+	require.NoError(t, service.SimulateBlock(context.Background(), block))
+
+	// The miner's extra data is kept if too long
+	extra = []byte(strings.Repeat("a", 33))
+	_, block, _ = service.BuildBlock(attrs)
+	require.NotNil(t, block)
+	require.NotEqual(t, extra, block.Extra())
+}
@@ -0,0 +1,130 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// RelayConstraints are the block constraints published by a relay, zero values are unbounded.
+type RelayConstraints struct {
+	MinGasLimit uint64 `json:"min_gas_limit,string"`
+	MaxGasLimit uint64 `json:"max_gas_limit,string"`
+	// MinValue is the lowest bid value in wei the relay accepts
+	MinValue boostTypes.U256Str `json:"min_value"`
+}
+
+// reportGasLimit logs and records the gas limit chosen for the slot's blocks on the attributes' head, after the
+// smoothing and the relay constraints, with the validator's requested gas limit and the parent's. The EL moves the
+// block gas limit from the parent's towards the chosen one within the per-block adjustment cap, the resulting block
+// gas limit is logged too.
+func reportGasLimit(slot uint64, headHash common.Hash, requested uint64, parentGasLimit uint64, chosen uint64) {
+	blockGasLimit := core.CalcGasLimit(parentGasLimit, chosen)
+	validatorGasLimitGauge.Update(int64(requested))
+	parentGasLimitGauge.Update(int64(parentGasLimit))
+	chosenGasLimitGauge.Update(int64(chosen))
+	blockGasLimitGauge.Update(int64(blockGasLimit))
+	log.Info("chose slot gas limit", "slot", slot, "head_hash", headHash, "gas_limit", chosen, "validator_gas_limit", requested, "parent_gas_limit", parentGasLimit, "block_gas_limit", blockGasLimit)
+}
+
+// reportCandidateGasLimit logs the gas limit chosen for the slot's blocks on a candidate head at debug level, the
+// slot's gauges only record the attributes' head.
+func reportCandidateGasLimit(slot uint64, headHash common.Hash, requested uint64, parentGasLimit uint64, chosen uint64) {
+	log.Debug("chose candidate head gas limit", "slot", slot, "head_hash", headHash, "gas_limit", chosen, "validator_gas_limit", requested, "parent_gas_limit", parentGasLimit, "block_gas_limit", core.CalcGasLimit(parentGasLimit, chosen))
+}
+
+// clampGasLimit clamps the requested gas limit to the intersection of the gas limits reachable from the parent
+// and the relay constraints. If the relay range can't be reached in one block, the requested gas limit is clamped
+// to the relay range only and the EL moves towards it.
+func clampGasLimit(requested uint64, parentGasLimit uint64, constraints RelayConstraints) uint64 {
+	if constraints.MinGasLimit == 0 && constraints.MaxGasLimit == 0 {
+		return requested
+	}
+
+	low, high := constraints.MinGasLimit, constraints.MaxGasLimit
+	if high == 0 {
+		high = ^uint64(0)
+	}
+
+	delta := parentGasLimit / params.GasLimitBoundDivisor
+	if delta > 0 {
+		delta--
+	}
+	if parentGasLimit > delta && parentGasLimit-delta > low && parentGasLimit-delta <= high {
+		low = parentGasLimit - delta
+	}
+	if parentGasLimit+delta < high && parentGasLimit+delta >= low {
+		high = parentGasLimit + delta
+	}
+
+	if requested < low {
+		return low
+	}
+	if requested > high {
+		return high
+	}
+	return requested
+}
+
+// smoothGasLimit moves the gas limit from the parent's by the factor of the gap to the requested gas limit, by at
+// least 1 and at most what the parent reaches in one block. The requested gas limit is returned unless the factor
+// is within (0, 1).
+func smoothGasLimit(requested uint64, parentGasLimit uint64, factor float64) uint64 {
+	if factor <= 0 || factor >= 1 || requested == parentGasLimit {
+		return requested
+	}
+
+	gap := requested - parentGasLimit
+	if requested < parentGasLimit {
+		gap = parentGasLimit - requested
+	}
+
+	step := uint64(factor * float64(gap))
+	if step == 0 {
+		step = 1
+	}
+	if delta := parentGasLimit / params.GasLimitBoundDivisor; delta > 1 && step > delta-1 {
+		step = delta - 1
+	}
+
+	if requested < parentGasLimit {
+		return parentGasLimit - step
+	}
+	return parentGasLimit + step
+}
+
+// checkRegisteredGasLimit cross-checks the slot's validator gas limit against the validator's standing registration
+// with the relay once per slot, logging the discrepancies. Relays without the registration are not checked.
+func (b *Builder) checkRegisteredGasLimit(slot uint64, pubkey boostTypes.PublicKey, gasLimit uint64) {
+	for {
+		checked := atomic.LoadUint64(&b.gasLimitCheckedSlot)
+		if slot <= checked {
+			return
+		}
+		if atomic.CompareAndSwapUint64(&b.gasLimitCheckedSlot, checked, slot) {
+			break
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), relayConstraintsTimeout)
+	defer cancel()
+
+	registered, err := b.relay.GetRegisteredGasLimit(ctx, pubkey)
+	if errors.Is(err, ErrRegistrationNotFound) {
+		return
+	}
+	if err != nil {
+		log.Debug("could not get registered gas limit from relay", "err", err, "slot", slot, "pubkey", pubkey.String())
+		return
+	}
+	if registered != gasLimit {
+		gasLimitMismatchCounter.Inc(1)
+		log.Warn("slot's validator gas limit differs from the registered gas limit", "slot", slot, "pubkey", pubkey.String(), "gas_limit", gasLimit, "registered_gas_limit", registered)
+	}
+}
@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClampGasLimit(t *testing.T) {
+	// Parent 30M can move by 29_295 per block
+	parent := uint64(30_000_000)
+
+	// No constraints
+	require.Equal(t, uint64(25_000_000), clampGasLimit(25_000_000, parent, RelayConstraints{}))
+
+	// Requested within relay range
+	require.Equal(t, uint64(30_000_000), clampGasLimit(30_000_000, parent, RelayConstraints{MinGasLimit: 29_000_000, MaxGasLimit: 31_000_000}))
+
+	// Requested above relay max, reachable from parent
+	require.Equal(t, uint64(30_010_000), clampGasLimit(35_000_000, parent, RelayConstraints{MaxGasLimit: 30_010_000}))
+
+	// Requested above relay max, relay max above what the parent reaches
+	require.Equal(t, uint64(30_029_295), clampGasLimit(35_000_000, parent, RelayConstraints{MaxGasLimit: 31_000_000}))
+
+	// Requested below relay min, clamped to the relay min
+	require.Equal(t, uint64(29_990_000), clampGasLimit(20_000_000, parent, RelayConstraints{MinGasLimit: 29_990_000}))
+
+	// Relay range not reachable in one block
+	require.Equal(t, uint64(36_000_000), clampGasLimit(30_000_000, parent, RelayConstraints{MinGasLimit: 36_000_000, MaxGasLimit: 40_000_000}))
+	require.Equal(t, uint64(20_000_000), clampGasLimit(30_000_000, parent, RelayConstraints{MinGasLimit: 10_000_000, MaxGasLimit: 20_000_000}))
+}
+
+func TestSmoothGasLimit(t *testing.T) {
+	parent := uint64(30_000_000)
+
+	// Disabled
+	require.Equal(t, uint64(30_010_000), smoothGasLimit(30_010_000, parent, 0))
+	require.Equal(t, uint64(30_010_000), smoothGasLimit(30_010_000, parent, 1))
+
+	// A fraction of the gap
+	require.Equal(t, uint64(30_002_500), smoothGasLimit(30_010_000, parent, 0.25))
+	require.Equal(t, uint64(29_997_500), smoothGasLimit(29_990_000, parent, 0.25))
+
+	// Capped to what the parent reaches in one block
+	require.Equal(t, uint64(30_029_295), smoothGasLimit(40_000_000, parent, 0.5))
+	require.Equal(t, uint64(29_970_705), smoothGasLimit(20_000_000, parent, 0.5))
+
+	// Small gaps still converge
+	require.Equal(t, uint64(30_000_001), smoothGasLimit(30_000_002, parent, 0.1))
+	require.Equal(t, parent, smoothGasLimit(parent, parent, 0.1))
+}
+
+type gasLimitCountingRelay struct {
+	*testRelay
+	queried int32
+}
+
+func (r *gasLimitCountingRelay) GetRegisteredGasLimit(ctx context.Context, pubkey boostTypes.PublicKey) (uint64, error) {
+	atomic.AddInt32(&r.queried, 1)
+	return r.testRelay.GetRegisteredGasLimit(ctx, pubkey)
+}
+
+func TestCheckRegisteredGasLimit(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	testRelay.registeredGasLimit = 30_000_000
+	relay := &gasLimitCountingRelay{testRelay: testRelay}
+	builder.relay = relay
+
+	// Checked once per slot, past slots are not checked
+	builder.checkRegisteredGasLimit(25, boostTypes.PublicKey{0x01}, 25_000_000)
+	builder.checkRegisteredGasLimit(25, boostTypes.PublicKey{0x01}, 25_000_000)
+	builder.checkRegisteredGasLimit(24, boostTypes.PublicKey{0x01}, 25_000_000)
+	require.Equal(t, int32(1), atomic.LoadInt32(&relay.queried))
+
+	builder.checkRegisteredGasLimit(26, boostTypes.PublicKey{0x01}, 30_000_000)
+	require.Equal(t, int32(2), atomic.LoadInt32(&relay.queried))
+	require.Equal(t, uint64(26), atomic.LoadUint64(&builder.gasLimitCheckedSlot))
+}
@@ -0,0 +1,158 @@
+package builder
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var ErrRelaySLAViolated = errors.New("relay disabled for violating the latency SLA")
+
+const (
+	defaultLatencySLAWindow = time.Minute
+	// latencySLAMinSamples is the number of samples in the window needed to disable a relay
+	latencySLAMinSamples = 10
+)
+
+var (
+	relaySubmitLatencyHist = metrics.NewRegisteredHistogram("builder/relay/submit_latency", nil, metrics.NewExpDecaySample(1028, 0.015))
+	relayLatencyP99Gauge   = metrics.NewRegisteredGauge("builder/relay/latency_p99", nil)
+	relaySLADisabledGauge  = metrics.NewRegisteredGauge("builder/relay/sla_disabled", nil)
+)
+
+// LatencyStats are the relay submission latency percentiles over the SLA window.
+type LatencyStats struct {
+	Samples int           `json:"samples"`
+	P50     time.Duration `json:"p50"`
+	P99     time.Duration `json:"p99"`
+}
+
+type latencySample struct {
+	at      time.Time
+	latency time.Duration
+}
+
+// LatencySLA disables a relay once its p99 submission latency exceeds the SLA for the whole window. While disabled,
+// a submission is let through every probeInterval to measure the relay, and the relay is re-enabled once the p99
+// over the window is within the SLA again. A zero SLA only tracks the latency.
+type LatencySLA struct {
+	mu            sync.Mutex
+	sla           time.Duration
+	window        time.Duration
+	probeInterval time.Duration
+
+	samples        []latencySample
+	disabled       bool
+	violatingSince time.Time
+	lastProbe      time.Time
+}
+
+func NewLatencySLA(sla time.Duration, window time.Duration, probeInterval time.Duration) *LatencySLA {
+	if window <= 0 {
+		window = defaultLatencySLAWindow
+	}
+	return &LatencySLA{sla: sla, window: window, probeInterval: probeInterval}
+}
+
+// Allow returns false while the relay is disabled, except for the probe submissions.
+func (s *LatencySLA) Allow(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.disabled {
+		return true
+	}
+	if now.Sub(s.lastProbe) >= s.probeInterval {
+		s.lastProbe = now
+		return true
+	}
+	return false
+}
+
+// Observe records a submission's latency, disabling or re-enabling the relay.
+func (s *LatencySLA) Observe(now time.Time, latency time.Duration) {
+	relaySubmitLatencyHist.Update(latency.Milliseconds())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.samples = append(s.samples, latencySample{at: now, latency: latency})
+	s.prune(now)
+
+	stats := s.stats()
+	relayLatencyP99Gauge.Update(stats.P99.Milliseconds())
+	if s.sla == 0 {
+		return
+	}
+
+	violating := stats.P99 > s.sla
+	switch {
+	case s.disabled && !violating:
+		log.Info("relay re-enabled, latency within the SLA", "p50", stats.P50, "p99", stats.P99, "sla", s.sla)
+		s.disabled = false
+		s.violatingSince = time.Time{}
+		relaySLADisabledGauge.Update(0)
+	case s.disabled:
+	case !violating || stats.Samples < latencySLAMinSamples:
+		s.violatingSince = time.Time{}
+	case s.violatingSince.IsZero():
+		s.violatingSince = now
+	case now.Sub(s.violatingSince) >= s.window:
+		log.Warn("relay disabled, latency violating the SLA", "p50", stats.P50, "p99", stats.P99, "sla", s.sla, "since", s.violatingSince)
+		s.disabled = true
+		s.lastProbe = now
+		relaySLADisabledGauge.Update(1)
+	}
+}
+
+func (s *LatencySLA) Enabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return !s.disabled
+}
+
+func (s *LatencySLA) Stats() LatencyStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stats()
+}
+
+func (s *LatencySLA) prune(now time.Time) {
+	i := 0
+	for i < len(s.samples) && now.Sub(s.samples[i].at) > s.window {
+		i++
+	}
+	s.samples = s.samples[i:]
+}
+
+func (s *LatencySLA) stats() LatencyStats {
+	latencies := make([]time.Duration, len(s.samples))
+	for i, sample := range s.samples {
+		latencies[i] = sample.latency
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	return LatencyStats{
+		Samples: len(latencies),
+		P50:     percentile(latencies, 0.5),
+		P99:     percentile(latencies, 0.99),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies.
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(q*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
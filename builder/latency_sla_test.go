@@ -0,0 +1,62 @@
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencySLA(t *testing.T) {
+	sla := NewLatencySLA(time.Second, time.Minute, 12*time.Second)
+	start := time.Now()
+
+	for i := 0; i < 100; i++ {
+		sla.Observe(start, time.Duration(i+1)*10*time.Millisecond)
+	}
+	stats := sla.Stats()
+	require.Equal(t, 100, stats.Samples)
+	require.Equal(t, 500*time.Millisecond, stats.P50)
+	require.Equal(t, 990*time.Millisecond, stats.P99)
+
+	// Violating the SLA for less than the window
+	now := start
+	for i := 0; i < 10; i++ {
+		now = now.Add(5 * time.Second)
+		sla.Observe(now, 3*time.Second)
+	}
+	require.True(t, sla.Enabled())
+	require.True(t, sla.Allow(now))
+
+	// Violating the SLA for the whole window
+	for i := 0; i < 5; i++ {
+		now = now.Add(5 * time.Second)
+		sla.Observe(now, 3*time.Second)
+	}
+	require.False(t, sla.Enabled())
+	require.False(t, sla.Allow(now.Add(time.Second)))
+
+	// Probed once per probe interval, re-enabled once the slow samples leave the window
+	now = now.Add(12 * time.Second)
+	require.True(t, sla.Allow(now))
+	require.False(t, sla.Allow(now))
+	sla.Observe(now, 100*time.Millisecond)
+	require.False(t, sla.Enabled())
+
+	now = now.Add(time.Minute)
+	require.True(t, sla.Allow(now))
+	sla.Observe(now, 100*time.Millisecond)
+	require.True(t, sla.Enabled())
+	require.Equal(t, LatencyStats{Samples: 2, P50: 100 * time.Millisecond, P99: 100 * time.Millisecond}, sla.Stats())
+}
+
+func TestLatencySLADisabled(t *testing.T) {
+	sla := NewLatencySLA(0, time.Minute, 12*time.Second)
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		now = now.Add(time.Second)
+		sla.Observe(now, time.Hour)
+	}
+	require.True(t, sla.Enabled())
+	require.Equal(t, time.Hour, sla.Stats().P99)
+}
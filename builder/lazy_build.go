@@ -0,0 +1,128 @@
+package builder
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// lazyBuildPollTimeout bounds the poll of the relays' top bids before a lazy build
+const lazyBuildPollTimeout = 500 * time.Millisecond
+
+// LazyBuildPolicy builds the blocks of a slot after its first only when the relays' top bid looks beatable, saving
+// the EL the builds which would not win. Blocks are built as often as always if no relay publishes its top bid.
+type LazyBuildPolicy struct {
+	// MaxDeficit is the wei a competitor's top bid may exceed the last block's profit by and still be competed with,
+	// as the mempool's new transactions may close the gap. Added to MaxDeficitBps
+	MaxDeficit *big.Int
+	// MaxDeficitBps is the basis points of the last block's profit a competitor's top bid may exceed it by
+	MaxDeficitBps uint64
+}
+
+// worthBuilding is the decision function of the lazy builds: a block is built if none was built for the slot yet,
+// if the market's top bid is unknown, or if a competitor holds the top bid by at most the deficit the policy allows
+// over the last block's profit. No block is built while the builder holds the top bid.
+func (p *LazyBuildPolicy) worthBuilding(topBid *TopBid, ownTopBid bool, lastProfit *big.Int) bool {
+	switch {
+	case lastProfit == nil || topBid == nil || topBid.Value == nil:
+		return true
+	case ownTopBid:
+		return false
+	}
+
+	maxDeficit := new(big.Int).Mul(lastProfit, new(big.Int).SetUint64(p.MaxDeficitBps))
+	maxDeficit.Div(maxDeficit, big.NewInt(10_000))
+	if p.MaxDeficit != nil {
+		maxDeficit.Add(maxDeficit, p.MaxDeficit)
+	}
+	deficit := new(big.Int).Sub(topBid.Value, lastProfit)
+	return deficit.Cmp(maxDeficit) <= 0
+}
+
+// lazyBuildState is the profit of the last block built for a slot.
+type lazyBuildState struct {
+	mu         sync.Mutex
+	lastProfit *big.Int
+}
+
+func (s *lazyBuildState) profit() *big.Int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastProfit
+}
+
+func (s *lazyBuildState) setProfit(profit *big.Int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if profit == nil {
+		profit = new(big.Int)
+	}
+	s.lastProfit = profit
+}
+
+// marketTopBid returns the most valuable of the relays' top bids for the slot and whether it is the builder's, nil if
+// no relay published one. The relays are polled in parallel under a single deadline, the late ones are ignored.
+func (b *Builder) marketTopBid(slot uint64) (*TopBid, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), lazyBuildPollTimeout)
+	defer cancel()
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		top *TopBid
+	)
+	for _, relay := range b.relays() {
+		getter, ok := relay.(TopBidGetter)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(relay IRelay, getter TopBidGetter) {
+			defer wg.Done()
+
+			topBid, err := getter.GetTopBid(ctx, slot)
+			if err != nil {
+				name, _ := relayIdentity(relay)
+				log.Debug("could not get relay top bid", "err", err, "relay", name, "slot", slot)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if topBid != nil && topBid.Value != nil && (top == nil || topBid.Value.Cmp(top.Value) > 0) {
+				top = topBid
+			}
+		}(relay, getter)
+	}
+	wg.Wait()
+
+	if top == nil {
+		return nil, false
+	}
+	return top, b.ownsPubkey(top.BuilderPubkey)
+}
+
+// lazyBuildWorth returns whether the slot's next block is worth building under the lazy build policy, always if the
+// builder is not lazy.
+func (b *Builder) lazyBuildWorth(slot uint64, state *lazyBuildState) bool {
+	if b.lazyBuild == nil {
+		return true
+	}
+	lastProfit := state.profit()
+	if lastProfit == nil {
+		return true
+	}
+
+	topBid, own := b.marketTopBid(slot)
+	if b.lazyBuild.worthBuilding(topBid, own, lastProfit) {
+		return true
+	}
+	lazySkippedBuildsCounter.Inc(1)
+	log.Debug("skipping lazy build, the top bid is not worth competing for", "slot", slot, "top_bid", topBid.Value, "own_top_bid", own, "last_profit", lastProfit)
+	return false
+}
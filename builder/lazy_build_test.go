@@ -0,0 +1,93 @@
+package builder
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyBuildPolicy(t *testing.T) {
+	policy := &LazyBuildPolicy{MaxDeficit: big.NewInt(5), MaxDeficitBps: 1_000}
+	competitor := func(value int64) *TopBid { return &TopBid{Value: big.NewInt(value)} }
+	lastProfit := big.NewInt(100)
+
+	// The first block of a slot is always built, as are the blocks without a known top bid
+	require.True(t, policy.worthBuilding(competitor(1_000), false, nil))
+	require.True(t, policy.worthBuilding(nil, false, lastProfit))
+	require.True(t, policy.worthBuilding(&TopBid{}, false, lastProfit))
+
+	// The market bids rise past the allowed deficit of 5 wei plus 10% of the last profit
+	for _, bid := range []struct {
+		value int64
+		worth bool
+	}{
+		{value: 50, worth: true},
+		{value: 100, worth: true},
+		{value: 115, worth: true},
+		{value: 116, worth: false},
+		{value: 1_000, worth: false},
+	} {
+		require.Equal(t, bid.worth, policy.worthBuilding(competitor(bid.value), false, lastProfit), bid.value)
+	}
+
+	// No block is built while the builder holds the top bid
+	require.False(t, policy.worthBuilding(competitor(100), true, lastProfit))
+
+	// Without a deficit allowed, only the top bids up to the last profit are competed with
+	strict := &LazyBuildPolicy{}
+	require.True(t, strict.worthBuilding(competitor(100), false, lastProfit))
+	require.False(t, strict.worthBuilding(competitor(101), false, lastProfit))
+}
+
+func TestLazyBuildWorth(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{LazyBuild: &LazyBuildPolicy{MaxDeficit: big.NewInt(5)}})
+	relay := &topBidRelay{testRelay: testRelay}
+	builder.relay = relay
+	setTopBid := func(pubkey boostTypes.PublicKey, value int64) {
+		relay.mu.Lock()
+		defer relay.mu.Unlock()
+		relay.topBid = &TopBid{BuilderPubkey: pubkey, Value: big.NewInt(value)}
+	}
+
+	var state lazyBuildState
+	require.True(t, builder.lazyBuildWorth(25, &state))
+	state.setProfit(big.NewInt(10))
+	// No top bid published yet
+	require.True(t, builder.lazyBuildWorth(25, &state))
+
+	competitorPubkey := boostTypes.PublicKey{0x01}
+	setTopBid(competitorPubkey, 15)
+	require.True(t, builder.lazyBuildWorth(25, &state))
+	setTopBid(competitorPubkey, 16)
+	require.False(t, builder.lazyBuildWorth(25, &state))
+	setTopBid(builder.builderPublicKey, 10)
+	require.False(t, builder.lazyBuildWorth(25, &state))
+
+	// The blocks are always built when the builder is not lazy
+	builder.lazyBuild = nil
+	setTopBid(competitorPubkey, 1_000)
+	require.True(t, builder.lazyBuildWorth(25, &state))
+}
+
+func TestMarketTopBid(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	newRelay := func(value int64, delay time.Duration) IRelay {
+		return &topBidRelay{testRelay: &testRelay{}, topBid: &TopBid{Value: big.NewInt(value)}, delay: delay}
+	}
+
+	// The relays are polled in parallel, the unresponsive one only delays the poll by its deadline
+	builder.relay = NewMultiRelay([]IRelay{
+		newRelay(10, 300*time.Millisecond),
+		newRelay(30, 300*time.Millisecond),
+		newRelay(20, 300*time.Millisecond),
+		newRelay(1_000, time.Minute),
+	}, ValidatorConflictRecent)
+	start := time.Now()
+	topBid, own := builder.marketTopBid(25)
+	require.Less(t, time.Since(start), 2*lazyBuildPollTimeout)
+	require.Equal(t, big.NewInt(30), topBid.Value)
+	require.False(t, own)
+}
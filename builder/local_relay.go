@@ -2,6 +2,7 @@ package builder
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -44,6 +45,7 @@ type LocalRelay struct {
 	bestHeader   *boostTypes.ExecutionPayloadHeader
 	bestPayload  *boostTypes.ExecutionPayload
 	profit       boostTypes.U256Str
+	payloads     *PayloadStore
 
 	indexTemplate *template.Template
 	fd            ForkData
@@ -74,12 +76,14 @@ func NewLocalRelay(sk *bls.SecretKey, beaconClient IBeaconClient, builderSigning
 
 		enableBeaconChecks: enableBeaconChecks,
 
+		payloads: NewPayloadStore(),
+
 		indexTemplate: indexTemplate,
 		fd:            fd,
 	}
 }
 
-func (r *LocalRelay) SubmitBlock(msg *boostTypes.BuilderSubmitBlockRequest) error {
+func (r *LocalRelay) SubmitBlock(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) error {
 	payloadHeader, err := boostTypes.PayloadToPayloadHeader(msg.ExecutionPayload)
 	if err != nil {
 		log.Error("could not convert payload to header", "err", err)
@@ -92,6 +96,9 @@ func (r *LocalRelay) SubmitBlock(msg *boostTypes.BuilderSubmitBlockRequest) erro
 	r.profit = msg.Message.Value
 	r.bestDataLock.Unlock()
 
+	r.payloads.Prune(msg.Message.Slot)
+	r.payloads.Add(msg.Message.Slot, msg.ExecutionPayload)
+
 	return nil
 }
 
@@ -185,6 +192,22 @@ func (r *LocalRelay) GetValidatorForSlot(nextSlot uint64) (ValidatorData, error)
 	return ValidatorData{}, errors.New("missing validator")
 }
 
+// GetConstraints returns no constraints, the local relay accepts any gas limit.
+func (r *LocalRelay) GetConstraints(ctx context.Context) (RelayConstraints, error) {
+	return RelayConstraints{}, nil
+}
+
+func (r *LocalRelay) GetRegisteredGasLimit(ctx context.Context, pubkey boostTypes.PublicKey) (uint64, error) {
+	r.validatorsLock.RLock()
+	defer r.validatorsLock.RUnlock()
+
+	vd, ok := r.validators[PubkeyHex(pubkey.String())]
+	if !ok {
+		return 0, ErrRegistrationNotFound
+	}
+	return vd.GasLimit, nil
+}
+
 func (r *LocalRelay) handleGetHeader(w http.ResponseWriter, req *http.Request) {
 	vars := mux.Vars(req)
 	slot, err := strconv.Atoi(vars["slot"])
@@ -288,7 +311,7 @@ func (r *LocalRelay) handleGetPayload(w http.ResponseWriter, req *http.Request)
 	bestPayload := r.bestPayload
 	r.bestDataLock.Unlock()
 
-	log.Info("Received blinded block", "payload", payload, "bestHeader", bestHeader)
+	log.Info("Received blinded block", "payload", payload, "best_header", bestHeader)
 
 	if bestHeader == nil || bestPayload == nil {
 		respondError(w, http.StatusInternalServerError, "no payloads")
@@ -296,8 +319,12 @@ func (r *LocalRelay) handleGetPayload(w http.ResponseWriter, req *http.Request)
 	}
 
 	if !ExecutionPayloadHeaderEqual(bestHeader, payload.Message.Body.ExecutionPayloadHeader) {
-		respondError(w, http.StatusBadRequest, "unknown payload")
-		return
+		// The best header could have been replaced since the bid was served, look up earlier submissions
+		bestPayload, err = r.payloads.GetForHeader(payload.Message.Body.ExecutionPayloadHeader)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "unknown payload")
+			return
+		}
 	}
 
 	response := boostTypes.GetPayloadResponse{
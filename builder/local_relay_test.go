@@ -2,6 +2,7 @@ package builder
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -30,6 +31,7 @@ func newTestBackend(t *testing.T, forkchoiceData *beacon.ExecutableDataV1, block
 	localRelay := NewLocalRelay(sk, beaconClient, bDomain, cDomain, ForkData{}, true)
 	ethService := &testEthereumService{synced: true, testExecutableData: forkchoiceData, testBlock: block}
 	backend := NewBuilder(sk, beaconClient, localRelay, bDomain, ethService)
+	backend.Start()
 	// service := NewService("127.0.0.1:31545", backend)
 
 	return backend, localRelay, validator
@@ -66,6 +68,12 @@ func TestValidatorRegistration(t *testing.T) {
 	require.Contains(t, relay.validators, PubkeyHex(v.Pk.String()))
 	require.Equal(t, ValidatorData{Pubkey: PubkeyHex(v.Pk.String()), FeeRecipient: payload[0].Message.FeeRecipient, GasLimit: payload[0].Message.GasLimit, Timestamp: payload[0].Message.Timestamp}, relay.validators[PubkeyHex(v.Pk.String())])
 
+	gasLimit, err := relay.GetRegisteredGasLimit(context.Background(), payload[0].Message.Pubkey)
+	require.NoError(t, err)
+	require.Equal(t, uint64(15_000_000), gasLimit)
+	_, err = relay.GetRegisteredGasLimit(context.Background(), boostTypes.PublicKey{0x01})
+	require.ErrorIs(t, err, ErrRegistrationNotFound)
+
 	rr = testRequest(t, relay, "POST", "/eth/v1/builder/validators", payload)
 	require.Equal(t, http.StatusOK, rr.Code)
 
@@ -115,14 +123,15 @@ func TestGetHeader(t *testing.T) {
 	forkchoiceData := &beacon.ExecutableDataV1{
 		ParentHash:    common.HexToHash("0xafafafa"),
 		FeeRecipient:  common.Address{0x01},
+		StateRoot:     common.Hash{0x07, 0x16},
+		ReceiptsRoot:  common.Hash{0x08, 0x20},
 		BlockHash:     common.HexToHash("0xbfbfbfb"),
+		Number:        10,
 		BaseFeePerGas: big.NewInt(12),
 		ExtraData:     []byte{},
 		LogsBloom:     []byte{0x00, 0x05, 0x10},
 	}
-	forkchoiceBlock := &types.Block{
-		Profit: big.NewInt(10),
-	}
+	forkchoiceBlock := newTestBlock(10)
 
 	backend, relay, validator := newTestBackend(t, forkchoiceData, forkchoiceBlock)
 
@@ -140,7 +149,9 @@ func TestGetHeader(t *testing.T) {
 	require.Equal(t, ``, rr.Body.String())
 	require.Equal(t, 204, rr.Code)
 
-	backend.OnPayloadAttribute(&BuilderPayloadAttributes{})
+	attrs := newTestAttributes(1)
+	attrs.HeadHash = forkchoiceData.ParentHash
+	backend.OnPayloadAttribute(attrs)
 
 	path = fmt.Sprintf("/eth/v1/builder/header/%d/%s/%s", 0, forkchoiceData.ParentHash.Hex(), validator.Pk.String())
 	rr = testRequest(t, relay, "GET", path, nil)
@@ -174,18 +185,21 @@ func TestGetPayload(t *testing.T) {
 	forkchoiceData := &beacon.ExecutableDataV1{
 		ParentHash:    common.HexToHash("0xafafafa"),
 		FeeRecipient:  common.Address{0x01},
+		StateRoot:     common.Hash{0x07, 0x16},
+		ReceiptsRoot:  common.Hash{0x08, 0x20},
 		BlockHash:     common.HexToHash("0xbfbfbfb"),
+		Number:        10,
 		BaseFeePerGas: big.NewInt(12),
 		ExtraData:     []byte{},
 	}
-	forkchoiceBlock := &types.Block{
-		Profit: big.NewInt(10),
-	}
+	forkchoiceBlock := newTestBlock(10)
 
 	backend, relay, validator := newTestBackend(t, forkchoiceData, forkchoiceBlock)
 
 	registerValidator(t, validator, relay)
-	backend.OnPayloadAttribute(&BuilderPayloadAttributes{})
+	attrs := newTestAttributes(1)
+	attrs.HeadHash = forkchoiceData.ParentHash
+	backend.OnPayloadAttribute(attrs)
 
 	path := fmt.Sprintf("/eth/v1/builder/header/%d/%s/%s", 0, forkchoiceData.ParentHash.Hex(), validator.Pk.String())
 	rr := testRequest(t, relay, "GET", path, nil)
@@ -0,0 +1,107 @@
+package builder
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// Profit components are recorded in gwei to fit the int64 samples
+	profitPriorityFeesHist   = metrics.NewRegisteredHistogram("builder/profit/priority_fees", nil, metrics.NewExpDecaySample(1028, 0.015))
+	profitDirectPaymentsHist = metrics.NewRegisteredHistogram("builder/profit/direct_payments", nil, metrics.NewExpDecaySample(1028, 0.015))
+	profitBundlePaymentsHist = metrics.NewRegisteredHistogram("builder/profit/bundle_payments", nil, metrics.NewExpDecaySample(1028, 0.015))
+	profitPaymentTxFeeHist   = metrics.NewRegisteredHistogram("builder/profit/payment_tx_fee", nil, metrics.NewExpDecaySample(1028, 0.015))
+	// The base fee burned by each block, in gwei, and its number of transactions besides the proposer payment
+	feeBaseFeeBurnedHist = metrics.NewRegisteredHistogram("builder/fees/base_fee_burned", nil, metrics.NewExpDecaySample(1028, 0.015))
+	feeTxCountHist       = metrics.NewRegisteredHistogram("builder/fees/tx_count", nil, metrics.NewExpDecaySample(1028, 0.015))
+
+	// The time the EL spent building each block, each relay's block submission, and from the receipt of the payload
+	// attributes to the slot's first submission
+	elBuildTimer         = metrics.NewRegisteredTimer("builder/build/el_duration", nil)
+	relaySubmitTimer     = metrics.NewRegisteredTimer("builder/relay/submit_duration", nil)
+	firstSubmissionTimer = metrics.NewRegisteredTimer("builder/attributes/first_submission_latency", nil)
+	// The time the EL spent building each block by the block's number of transactions, in fixed buckets
+	elBuildSmallTimer  = metrics.NewRegisteredTimer("builder/build/el_duration_by_txs/0_100", nil)
+	elBuildMediumTimer = metrics.NewRegisteredTimer("builder/build/el_duration_by_txs/100_500", nil)
+	elBuildLargeTimer  = metrics.NewRegisteredTimer("builder/build/el_duration_by_txs/500_plus", nil)
+	// Counts the builds for which the EL returned no block, separately from the submission errors
+	noPayloadFromELCounter = metrics.NewRegisteredCounter("builder/build/no_payload", nil)
+	// Counts the builds dropped at the slot deadline waiting for the concurrent builds limit
+	droppedBuildsCounter = metrics.NewRegisteredCounter("builder/build/dropped", nil)
+	// Counts the built blocks dropped for failing the local simulation
+	simulationFailedCounter = metrics.NewRegisteredCounter("builder/build/simulation_failed", nil)
+	// Counts the signed submissions dropped as their signature did not verify
+	invalidSignatureCounter = metrics.NewRegisteredCounter("builder/build/invalid_signature", nil)
+	// Counts the speculative blocks submitted for the next slot, and those discarded as the attributes differed
+	speculativeSubmittedCounter = metrics.NewRegisteredCounter("builder/speculative/submitted", nil)
+	speculativeDiscardedCounter = metrics.NewRegisteredCounter("builder/speculative/discarded", nil)
+	// Counts the beacon node validations that could not be done, skipped or dropping the slot by the beacon policy
+	beaconDegradedCounter = metrics.NewRegisteredCounter("builder/beacon/degraded", nil)
+	// Counts the payload attributes skipped while the builder is paused
+	pausedSlotsCounter = metrics.NewRegisteredCounter("builder/paused_slots", nil)
+	// Counts the submission events not posted to the webhook, as the queue was full or the retries failed
+	webhookDroppedCounter = metrics.NewRegisteredCounter("builder/webhook/dropped", nil)
+	// Counts the submissions not archived, as the archive queue was full or the sink failed
+	archiveFailedCounter = metrics.NewRegisteredCounter("builder/archive/failed", nil)
+	// Counts the submissions all relays rejected, likely a systemic issue worth alerting on
+	allRelaysRejectedCounter = metrics.NewRegisteredCounter("builder/relay/all_rejected", nil)
+	// Counts the relay submissions skipped as they would exceed the slot's byte budget
+	slotByteBudgetExceededCounter = metrics.NewRegisteredCounter("builder/relay/byte_budget_exceeded", nil)
+	// Counts the submission events not sent to a subscriber, as it did not keep up
+	submissionFeedDroppedCounter = metrics.NewRegisteredCounter("builder/submission_feed/dropped", nil)
+	// Counts the submissions retried by the relays' retry policies
+	retriedSubmissionsCounter = metrics.NewRegisteredCounter("builder/relay/submissions_retried", nil)
+	// Counts the signing domain checks finding the builder's domain differs from a relay's
+	signingDomainMismatchCounter = metrics.NewRegisteredCounter("builder/signing_domain_mismatch", nil)
+	// Counts the submitted blocks built on a candidate head rather than the attributes' head
+	candidateHeadBlocksCounter = metrics.NewRegisteredCounter("builder/candidate_heads/blocks", nil)
+	// Counts the builds skipped in lazy mode as the relays' top bid was not worth competing for
+	lazySkippedBuildsCounter = metrics.NewRegisteredCounter("builder/lazy/skipped_builds", nil)
+	// The gas limits of the last slot: the validator's requested, the parent's, the chosen after the smoothing and the
+	// relay constraints, and the block's the EL moves to towards the chosen
+	validatorGasLimitGauge = metrics.NewRegisteredGauge("builder/gas_limit/validator", nil)
+	parentGasLimitGauge    = metrics.NewRegisteredGauge("builder/gas_limit/parent", nil)
+	chosenGasLimitGauge    = metrics.NewRegisteredGauge("builder/gas_limit/chosen", nil)
+	blockGasLimitGauge     = metrics.NewRegisteredGauge("builder/gas_limit/block", nil)
+	// Counts the submissions suppressed as the same block was already being submitted to the relay
+	duplicateSubmissionsCounter = metrics.NewRegisteredCounter("builder/relay/duplicate_submissions", nil)
+	// Counts the 429 responses of the relays, and the submissions suppressed while backing off from them
+	rateLimitedCounter            = metrics.NewRegisteredCounter("builder/relay/rate_limited", nil)
+	rateLimitedSubmissionsCounter = metrics.NewRegisteredCounter("builder/relay/rate_limited_submissions", nil)
+	// Counts the slots whose validator gas limit differs from the validator's standing registration
+	gasLimitMismatchCounter = metrics.NewRegisteredCounter("builder/relay/gas_limit_mismatch", nil)
+	// The resubmitter's queued iterations, and its workers and how many are running an iteration
+	resubmitQueueDepthGauge     = metrics.NewRegisteredGauge("builder/resubmitter/queue_depth", nil)
+	resubmitWorkersGauge        = metrics.NewRegisteredGauge("builder/resubmitter/workers", nil)
+	resubmitBusyWorkersGauge    = metrics.NewRegisteredGauge("builder/resubmitter/busy_workers", nil)
+	droppedResubmissionsCounter = metrics.NewRegisteredCounter("builder/resubmitter/dropped", nil)
+	// Counts the panics recovered in the builds
+	panicsCounter = metrics.NewRegisteredCounter("builder/panics", nil)
+	// Counts the slots started without their payload attributes, once past the missed slots threshold
+	missedSlotsCounter = metrics.NewRegisteredCounter("builder/attributes/missed_slots", nil)
+	// Counts the canaries submitted, and those the relays did not accept
+	canarySubmissionsCounter = metrics.NewRegisteredCounter("builder/canary/submitted", nil)
+	canaryRejectedCounter    = metrics.NewRegisteredCounter("builder/canary/rejected", nil)
+)
+
+// elBuildTimerByTxs returns the EL build timer of the bucket of the block's number of transactions.
+func elBuildTimerByTxs(txs int) metrics.Timer {
+	switch {
+	case txs < 100:
+		return elBuildSmallTimer
+	case txs < 500:
+		return elBuildMediumTimer
+	default:
+		return elBuildLargeTimer
+	}
+}
+
+var gwei = big.NewInt(1_000_000_000)
+
+func weiToGwei(wei *big.Int) int64 {
+	if wei == nil {
+		return 0
+	}
+	return new(big.Int).Div(wei, gwei).Int64()
+}
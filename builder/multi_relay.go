@@ -0,0 +1,321 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+var ErrValidatorConflict = errors.New("relays disagree on the validator registration")
+
+// ValidatorConflictPolicy selects how MultiRelay reconciles relays returning different registrations for a slot.
+type ValidatorConflictPolicy string
+
+const (
+	// ValidatorConflictRecent builds with the most recent registration
+	ValidatorConflictRecent ValidatorConflictPolicy = "recent"
+	// ValidatorConflictFail drops the slot
+	ValidatorConflictFail ValidatorConflictPolicy = "fail"
+)
+
+func ParseValidatorConflictPolicy(policy string) (ValidatorConflictPolicy, error) {
+	switch ValidatorConflictPolicy(policy) {
+	case "":
+		return ValidatorConflictRecent, nil
+	case ValidatorConflictRecent, ValidatorConflictFail:
+		return ValidatorConflictPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unknown validator conflict policy %q", policy)
+	}
+}
+
+// MultiRelay gets the validator registrations from and submits the blocks to several relays,
+// so that each slot is built once for all of them.
+type MultiRelay struct {
+	relays         []IRelay
+	conflictPolicy ValidatorConflictPolicy
+
+	// batchSize is the number of relays submitted to at once, all of them if zero
+	batchSize int
+	stagger   time.Duration
+}
+
+// MultiRelayOptions are the optional settings of the MultiRelay, the zero value keeps the defaults.
+type MultiRelayOptions struct {
+	ConflictPolicy ValidatorConflictPolicy
+	// SubmissionBatchSize is the number of relays a block is submitted to at once, the submissions go out in waves
+	// of this many relays SubmissionStagger apart, in the order of the relays. All relays are submitted to at once
+	// if zero
+	SubmissionBatchSize int
+	// SubmissionStagger is the wait between two waves of submissions, skipped if the wait would end past the
+	// start of the submission's slot. The waves go out at once if zero
+	SubmissionStagger time.Duration
+}
+
+func NewMultiRelay(relays []IRelay, conflictPolicy ValidatorConflictPolicy) *MultiRelay {
+	return NewMultiRelayWithOptions(relays, MultiRelayOptions{ConflictPolicy: conflictPolicy})
+}
+
+func NewMultiRelayWithOptions(relays []IRelay, opts MultiRelayOptions) *MultiRelay {
+	conflictPolicy := opts.ConflictPolicy
+	if conflictPolicy == "" {
+		conflictPolicy = ValidatorConflictRecent
+	}
+	return &MultiRelay{
+		relays:         relays,
+		conflictPolicy: conflictPolicy,
+		batchSize:      opts.SubmissionBatchSize,
+		stagger:        opts.SubmissionStagger,
+	}
+}
+
+// GetValidatorForSlot queries all relays, returning an error if none has a registration for the slot. Conflicting
+// registrations are logged and reconciled with the conflict policy.
+func (m *MultiRelay) GetValidatorForSlot(nextSlot uint64) (ValidatorData, error) {
+	registrations := make([]ValidatorData, len(m.relays))
+	errs := make([]error, len(m.relays))
+
+	var wg sync.WaitGroup
+	for i, relay := range m.relays {
+		wg.Add(1)
+		go func(i int, relay IRelay) {
+			defer wg.Done()
+			registrations[i], errs[i] = relay.GetValidatorForSlot(nextSlot)
+		}(i, relay)
+	}
+	wg.Wait()
+
+	var found []ValidatorData
+	var foundRelays []string
+	for i, err := range errs {
+		if err == nil {
+			found = append(found, registrations[i])
+			name, _ := relayIdentity(m.relays[i])
+			foundRelays = append(foundRelays, name)
+		}
+	}
+	if len(found) == 0 {
+		return ValidatorData{}, fmt.Errorf("validator not found in any relay: %w", errs[0])
+	}
+
+	for i, vd := range found[1:] {
+		if vd.Pubkey == found[0].Pubkey && vd.FeeRecipient == found[0].FeeRecipient && vd.GasLimit == found[0].GasLimit {
+			continue
+		}
+
+		log.Warn("relays disagree on the validator registration", "slot", nextSlot, "policy", m.conflictPolicy,
+			"relay", foundRelays[0], "pubkey", found[0].Pubkey, "fee_recipient", found[0].FeeRecipient, "gas_limit", found[0].GasLimit, "timestamp", found[0].Timestamp,
+			"other_relay", foundRelays[i+1], "other_pubkey", vd.Pubkey, "other_fee_recipient", vd.FeeRecipient, "other_gas_limit", vd.GasLimit, "other_timestamp", vd.Timestamp)
+		if m.conflictPolicy == ValidatorConflictFail {
+			return ValidatorData{}, fmt.Errorf("%w for slot %d", ErrValidatorConflict, nextSlot)
+		}
+	}
+
+	return mostRecentRegistration(found...), nil
+}
+
+// RelayOutcome is a relay's outcome of a block submission, Err is nil if the relay accepted the block.
+type RelayOutcome struct {
+	Relay string
+	Err   error
+	// Skipped is set if the block was not submitted to the relay, Err tells why
+	Skipped bool
+}
+
+// SubmissionResult are the relays' outcomes of a block submission, in the order the relays were configured in
+// regardless of the order their submissions completed in.
+type SubmissionResult []RelayOutcome
+
+// Submitted reports whether any relay accepted the block.
+func (r SubmissionResult) Submitted() bool {
+	for _, outcome := range r {
+		if outcome.Err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns nil if any relay accepted the block, otherwise the first relay's submission error, or the first skip
+// error if all relays were skipped.
+func (r SubmissionResult) Err() error {
+	if r.Submitted() {
+		return nil
+	}
+	var firstSkipErr error
+	for _, outcome := range r {
+		if !outcome.Skipped {
+			return outcome.Err
+		}
+		if firstSkipErr == nil {
+			firstSkipErr = outcome.Err
+		}
+	}
+	return firstSkipErr
+}
+
+// Rejected reports whether no relay accepted the block and the relays it was submitted to all rejected it, answering
+// with a 4xx, see isRejection. The skipped relays are not counted, and the network errors, timeouts and 5xx are not
+// rejections.
+func (r SubmissionResult) Rejected() bool {
+	rejected := false
+	for _, outcome := range r {
+		switch {
+		case outcome.Skipped:
+		case !isRejection(outcome.Err):
+			return false
+		default:
+			rejected = true
+		}
+	}
+	return rejected
+}
+
+// RateLimited reports whether no relay accepted the block and the relays it was submitted to were all rate limited,
+// answering with a 429 or backed off from without a request. The skipped relays are not counted.
+func (r SubmissionResult) RateLimited() bool {
+	rateLimited := false
+	for _, outcome := range r {
+		switch {
+		case outcome.Skipped:
+		case !isRateLimited(outcome.Err):
+			return false
+		default:
+			rateLimited = true
+		}
+	}
+	return rateLimited
+}
+
+// isRateLimited reports whether the error is the relay's 429 response to the submission, or the submission was
+// suppressed while backing off from one.
+func isRateLimited(err error) bool {
+	if errors.Is(err, ErrRelayRateLimited) {
+		return true
+	}
+	var respErr *RelayResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusTooManyRequests
+}
+
+// isRejection reports whether the error is the relay's 4xx response to the submission, besides the 429 of the rate
+// limits.
+func isRejection(err error) bool {
+	var respErr *RelayResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+	return respErr.StatusCode >= 400 && respErr.StatusCode < 500 && respErr.StatusCode != http.StatusTooManyRequests
+}
+
+func (r SubmissionResult) String() string {
+	outcomes := make([]string, len(r))
+	for i, outcome := range r {
+		switch {
+		case outcome.Err == nil:
+			outcomes[i] = outcome.Relay + ": submitted"
+		case outcome.Skipped:
+			outcomes[i] = fmt.Sprintf("%s: skipped (%v)", outcome.Relay, outcome.Err)
+		default:
+			outcomes[i] = fmt.Sprintf("%s: failed (%v)", outcome.Relay, outcome.Err)
+		}
+	}
+	return strings.Join(outcomes, ", ")
+}
+
+// SubmitBlock submits the block to all relays, succeeding if any relay accepted it.
+func (m *MultiRelay) SubmitBlock(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) error {
+	return m.submitBlockTo(ctx, func(IRelay) (*boostTypes.BuilderSubmitBlockRequest, error) { return msg, nil }).Err()
+}
+
+// submitBlockTo submits to each relay the block returned by prepare, skipping the relays for which it returns an error.
+// The submissions run concurrently, in staggered waves if a batch size is set, and their outcomes are returned in the
+// order of the relays.
+func (m *MultiRelay) submitBlockTo(ctx context.Context, prepare func(IRelay) (*boostTypes.BuilderSubmitBlockRequest, error)) SubmissionResult {
+	result := make(SubmissionResult, len(m.relays))
+
+	var wg sync.WaitGroup
+	sent := 0
+	for i, relay := range m.relays {
+		result[i].Relay, _ = relayIdentity(relay)
+		msg, err := prepare(relay)
+		if err != nil {
+			result[i].Err, result[i].Skipped = err, true
+			continue
+		}
+		if m.startsWave(sent) {
+			m.waitStagger(ctx)
+		}
+		sent++
+		wg.Add(1)
+		go func(i int, relay IRelay, msg *boostTypes.BuilderSubmitBlockRequest) {
+			defer wg.Done()
+			start := time.Now()
+			result[i].Err = relay.SubmitBlock(ctx, msg)
+			relaySubmitTimer.UpdateSince(start)
+		}(i, relay, msg)
+	}
+	wg.Wait()
+
+	for _, outcome := range result {
+		if outcome.Err != nil && !outcome.Skipped {
+			log.Warn("could not submit block to relay", "err", outcome.Err, "relay", outcome.Relay)
+		}
+	}
+	submissionID, _ := SubmissionIDFromContext(ctx)
+	log.Debug("submitted block to relays", "submission_id", submissionID, "result", result)
+	return result
+}
+
+// GetConstraints returns the intersection of the relays' gas limit constraints, ignoring the relays failing to return
+// theirs. The minimum value is the lowest of the relays', as a bid is submitted to the relays whose minimum it meets.
+func (m *MultiRelay) GetConstraints(ctx context.Context) (RelayConstraints, error) {
+	var merged RelayConstraints
+	var firstErr error
+	answered := false
+	for _, relay := range m.relays {
+		constraints, err := relay.GetConstraints(ctx)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if !answered || constraints.MinValue.BigInt().Cmp(merged.MinValue.BigInt()) < 0 {
+			merged.MinValue = constraints.MinValue
+		}
+		answered = true
+
+		if constraints.MinGasLimit > merged.MinGasLimit {
+			merged.MinGasLimit = constraints.MinGasLimit
+		}
+		if constraints.MaxGasLimit != 0 && (merged.MaxGasLimit == 0 || constraints.MaxGasLimit < merged.MaxGasLimit) {
+			merged.MaxGasLimit = constraints.MaxGasLimit
+		}
+	}
+	if !answered {
+		return RelayConstraints{}, firstErr
+	}
+	return merged, nil
+}
+
+// GetRegisteredGasLimit returns the gas limit registered with the first relay having a registration for the validator.
+func (m *MultiRelay) GetRegisteredGasLimit(ctx context.Context, pubkey boostTypes.PublicKey) (uint64, error) {
+	err := ErrRegistrationNotFound
+	for _, relay := range m.relays {
+		gasLimit, relayErr := relay.GetRegisteredGasLimit(ctx, pubkey)
+		if relayErr == nil {
+			return gasLimit, nil
+		}
+		if !errors.Is(relayErr, ErrRegistrationNotFound) {
+			err = relayErr
+		}
+	}
+	return 0, err
+}
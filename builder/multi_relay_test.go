@@ -0,0 +1,131 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiRelayGetValidatorForSlot(t *testing.T) {
+	older := ValidatorData{Pubkey: "0x01", FeeRecipient: boostTypes.Address{0x01}, GasLimit: 30_000_000, Timestamp: 10}
+	newer := ValidatorData{Pubkey: "0x01", FeeRecipient: boostTypes.Address{0x02}, GasLimit: 30_000_000, Timestamp: 20}
+	missing := &testRelay{validatorErr: errors.New("validator not found")}
+
+	// Agreeing relays
+	relay := NewMultiRelay([]IRelay{&testRelay{validator: older}, missing, &testRelay{validator: older}}, ValidatorConflictFail)
+	vd, err := relay.GetValidatorForSlot(25)
+	require.NoError(t, err)
+	require.Equal(t, older, vd)
+	require.Equal(t, uint64(25), missing.requestedSlot)
+
+	// Most recent registration
+	relay = NewMultiRelay([]IRelay{&testRelay{validator: older}, &testRelay{validator: newer}}, ValidatorConflictRecent)
+	vd, err = relay.GetValidatorForSlot(25)
+	require.NoError(t, err)
+	require.Equal(t, newer, vd)
+
+	// Fail on conflict
+	relay = NewMultiRelay([]IRelay{&testRelay{validator: older}, &testRelay{validator: newer}}, ValidatorConflictFail)
+	_, err = relay.GetValidatorForSlot(25)
+	require.ErrorIs(t, err, ErrValidatorConflict)
+
+	relay = NewMultiRelay([]IRelay{missing, &testRelay{validatorErr: errors.New("validator not found")}}, ValidatorConflictRecent)
+	_, err = relay.GetValidatorForSlot(25)
+	require.Error(t, err)
+}
+
+func TestMultiRelaySubmitBlock(t *testing.T) {
+	first := &testRelay{}
+	failing := &testRelay{submitErr: errors.New("relay unavailable")}
+	relay := NewMultiRelay([]IRelay{first, failing}, ValidatorConflictRecent)
+
+	msg := &boostTypes.BuilderSubmitBlockRequest{}
+	require.NoError(t, relay.SubmitBlock(context.Background(), msg))
+	require.Equal(t, msg, first.submittedMsg)
+
+	first.submitErr = errors.New("relay unavailable")
+	require.Error(t, relay.SubmitBlock(context.Background(), msg))
+}
+
+// delayedRelay completes the submissions after the delay
+type delayedRelay struct {
+	*testRelay
+	delay time.Duration
+}
+
+func (r *delayedRelay) SubmitBlock(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) error {
+	time.Sleep(r.delay)
+	return r.testRelay.SubmitBlock(ctx, msg)
+}
+
+func TestMultiRelaySubmissionResultOrder(t *testing.T) {
+	// The slow relay completes last but comes first in the result
+	slow := &delayedRelay{testRelay: &testRelay{submitErr: errors.New("slow relay unavailable")}, delay: 50 * time.Millisecond}
+	fast := &testRelay{submitErr: errors.New("fast relay unavailable")}
+	skipped := &testRelay{}
+	relay := NewMultiRelay([]IRelay{slow, fast, skipped}, ValidatorConflictRecent)
+
+	msg := &boostTypes.BuilderSubmitBlockRequest{}
+	result := relay.submitBlockTo(context.Background(), func(r IRelay) (*boostTypes.BuilderSubmitBlockRequest, error) {
+		if r == skipped {
+			return nil, ErrRelayDisabled
+		}
+		return msg, nil
+	})
+	require.False(t, result.Submitted())
+	require.EqualError(t, result.Err(), "slow relay unavailable")
+	require.Equal(t, "unknown: failed (slow relay unavailable), unknown: failed (fast relay unavailable), unknown: skipped (relay disabled)", result.String())
+
+	slow.submitErr = nil
+	result = relay.submitBlockTo(context.Background(), func(IRelay) (*boostTypes.BuilderSubmitBlockRequest, error) { return msg, nil })
+	require.True(t, result.Submitted())
+	require.NoError(t, result.Err())
+	require.Equal(t, "unknown: submitted, unknown: failed (fast relay unavailable), unknown: submitted", result.String())
+}
+
+func TestMultiRelayGetConstraints(t *testing.T) {
+	relay := NewMultiRelay([]IRelay{
+		&testRelay{constraints: RelayConstraints{MinGasLimit: 29_000_000, MaxGasLimit: 31_000_000}},
+		&testRelay{},
+		&testRelay{constraints: RelayConstraints{MinGasLimit: 29_500_000, MaxGasLimit: 32_000_000}},
+	}, ValidatorConflictRecent)
+
+	constraints, err := relay.GetConstraints(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, RelayConstraints{MinGasLimit: 29_500_000, MaxGasLimit: 31_000_000}, constraints)
+
+	// The bids are submitted to the relays whose minimum value they meet, the lowest minimum applies
+	relay = NewMultiRelay([]IRelay{newTestRelayWithMinValue(t, 100), newTestRelayWithMinValue(t, 10)}, ValidatorConflictRecent)
+	constraints, err = relay.GetConstraints(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10), constraints.MinValue.BigInt())
+}
+
+func TestMultiRelayGetRegisteredGasLimit(t *testing.T) {
+	relay := NewMultiRelay([]IRelay{&testRelay{}, &testRelay{registeredGasLimit: 30_000_000}}, ValidatorConflictRecent)
+	gasLimit, err := relay.GetRegisteredGasLimit(context.Background(), boostTypes.PublicKey{0x01})
+	require.NoError(t, err)
+	require.Equal(t, uint64(30_000_000), gasLimit)
+
+	relay = NewMultiRelay([]IRelay{&testRelay{}, &testRelay{}}, ValidatorConflictRecent)
+	_, err = relay.GetRegisteredGasLimit(context.Background(), boostTypes.PublicKey{0x01})
+	require.ErrorIs(t, err, ErrRegistrationNotFound)
+}
+
+func TestParseValidatorConflictPolicy(t *testing.T) {
+	policy, err := ParseValidatorConflictPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, ValidatorConflictRecent, policy)
+
+	policy, err = ParseValidatorConflictPolicy("fail")
+	require.NoError(t, err)
+	require.Equal(t, ValidatorConflictFail, policy)
+
+	_, err = ParseValidatorConflictPolicy("first")
+	require.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var ErrNotSynced = errors.New("backend not Synced")
+
+// NotSyncedPolicy selects what the builder does when the EL is not synced on new payload attributes.
+type NotSyncedPolicy string
+
+const (
+	// NotSyncedFail drops the slot
+	NotSyncedFail NotSyncedPolicy = "fail"
+	// NotSyncedWait waits up to NotSyncedMaxWait, bounded by the slot deadline, for the EL to sync
+	NotSyncedWait NotSyncedPolicy = "wait"
+	// NotSyncedFallback builds the slot with FallbackEthService if it is synced
+	NotSyncedFallback NotSyncedPolicy = "fallback"
+)
+
+const (
+	defaultNotSyncedMaxWait = 2 * time.Second
+	syncedPollInterval      = 50 * time.Millisecond
+)
+
+func ParseNotSyncedPolicy(policy string) (NotSyncedPolicy, error) {
+	switch NotSyncedPolicy(policy) {
+	case "":
+		return NotSyncedFail, nil
+	case NotSyncedFail, NotSyncedWait, NotSyncedFallback:
+		return NotSyncedPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unknown not synced policy %q", policy)
+	}
+}
+
+// syncedEthService returns the EL to build the slot with according to the not synced policy.
+func (b *Builder) syncedEthService(deadline time.Time) (IEthereumService, error) {
+	if b.eth.Synced() {
+		return b.eth, nil
+	}
+
+	switch b.notSyncedPolicy {
+	case NotSyncedWait:
+		waitUntil := time.Now().Add(b.notSyncedMaxWait)
+		if deadline.Before(waitUntil) {
+			waitUntil = deadline
+		}
+
+		for time.Now().Before(waitUntil) {
+			time.Sleep(syncedPollInterval)
+			if b.eth.Synced() {
+				log.Info("backend synced, continuing building")
+				return b.eth, nil
+			}
+		}
+		return nil, ErrNotSynced
+	case NotSyncedFallback:
+		if b.fallbackEth != nil && b.fallbackEth.Synced() {
+			log.Info("backend not synced, building with the fallback backend")
+			return b.fallbackEth, nil
+		}
+		return nil, ErrNotSynced
+	default:
+		return nil, ErrNotSynced
+	}
+}
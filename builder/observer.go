@@ -0,0 +1,166 @@
+package builder
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+const (
+	// observerPollInterval is the interval the relays' top bids are polled at in observer mode
+	observerPollInterval = time.Second
+	// observedSlots is the number of most recent slots whose observed bids are kept
+	observedSlots = 64
+)
+
+// TopBid is the most valuable bid a relay received for a slot.
+type TopBid struct {
+	BuilderPubkey boostTypes.PublicKey `json:"builderPubkey"`
+	BlockHash     boostTypes.Hash      `json:"blockHash"`
+	Value         *big.Int             `json:"value"`
+}
+
+// TopBidGetter is implemented by relays publishing the bids they received.
+type TopBidGetter interface {
+	// GetTopBid returns nil if the relay received no bid for the slot.
+	GetTopBid(ctx context.Context, slot uint64) (*TopBid, error)
+}
+
+// ObservedBid is a relay's top bid for a slot, recorded in observer mode whenever it changes.
+type ObservedBid struct {
+	TopBid
+	Relay          string    `json:"relay"`
+	Slot           uint64    `json:"slot"`
+	ProposerPubkey PubkeyHex `json:"proposerPubkey"`
+	ObservedAt     time.Time `json:"observedAt"`
+}
+
+// bidObservations keeps the bids observed for the recent slots.
+type bidObservations struct {
+	mu    sync.Mutex
+	slots map[uint64][]ObservedBid
+	// polled are the recent slots whose top bids are or were polled
+	polled map[uint64]struct{}
+}
+
+func newBidObservations() *bidObservations {
+	return &bidObservations{slots: make(map[uint64][]ObservedBid), polled: make(map[uint64]struct{})}
+}
+
+// startPolling marks the slot as polled, returning false if it already was.
+func (o *bidObservations) startPolling(slot uint64) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, found := o.polled[slot]; found {
+		return false
+	}
+	o.polled[slot] = struct{}{}
+	return true
+}
+
+// record adds the bid unless it is the relay's top bid for the slot already, returning whether it was added.
+func (o *bidObservations) record(bid ObservedBid) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	bids := o.slots[bid.Slot]
+	for i := len(bids) - 1; i >= 0; i-- {
+		if bids[i].Relay != bid.Relay {
+			continue
+		}
+		if bids[i].BlockHash == bid.BlockHash && bids[i].Value.Cmp(bid.Value) == 0 {
+			return false
+		}
+		break
+	}
+	o.slots[bid.Slot] = append(bids, bid)
+	return true
+}
+
+// pruneBefore drops the bids observed for the slots before the slot.
+func (o *bidObservations) pruneBefore(slot uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for observedSlot := range o.slots {
+		if observedSlot < slot {
+			delete(o.slots, observedSlot)
+		}
+	}
+	for polledSlot := range o.polled {
+		if polledSlot < slot {
+			delete(o.polled, polledSlot)
+		}
+	}
+}
+
+// get returns the bids observed for the slot, in the order they were observed.
+func (o *bidObservations) get(slot uint64) []ObservedBid {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]ObservedBid(nil), o.slots[slot]...)
+}
+
+// ObservedBids returns the changes of the relays' top bids observed for the slot in observer mode, oldest first.
+func (b *Builder) ObservedBids(slot uint64) []ObservedBid {
+	return b.observations.get(slot)
+}
+
+// observeSlot polls the relays' top bids for the slot until the slot deadline, in place of building for it. The slot
+// is polled once, from its first attributes.
+func (b *Builder) observeSlot(attrs *BuilderPayloadAttributes) error {
+	vd, err := b.relay.GetValidatorForSlot(attrs.Slot)
+	if err != nil {
+		log.Info("could not get validator of the observed slot", "err", err, "slot", attrs.Slot)
+		return err
+	}
+	if !b.observations.startPolling(attrs.Slot) {
+		log.Debug("slot already observed", "slot", attrs.Slot)
+		return nil
+	}
+
+	deadline := time.Now().Add(b.slotDuration())
+	go func() {
+		for {
+			b.observeTopBids(attrs.Slot, vd.Pubkey)
+			if time.Until(deadline) < observerPollInterval {
+				return
+			}
+			time.Sleep(observerPollInterval)
+		}
+	}()
+	return nil
+}
+
+// observeTopBids records the top bids of the relays publishing them, logging those that changed.
+func (b *Builder) observeTopBids(slot uint64, proposer PubkeyHex) {
+	for _, relay := range b.relays() {
+		getter, ok := relay.(TopBidGetter)
+		if !ok {
+			continue
+		}
+		name, _ := relayIdentity(relay)
+
+		ctx, cancel := context.WithTimeout(context.Background(), observerPollInterval)
+		topBid, err := getter.GetTopBid(ctx, slot)
+		cancel()
+		if err != nil {
+			log.Debug("could not get relay top bid", "err", err, "relay", name, "slot", slot)
+			continue
+		}
+		if topBid == nil {
+			continue
+		}
+
+		bid := ObservedBid{TopBid: *topBid, Relay: name, Slot: slot, ProposerPubkey: proposer, ObservedAt: time.Now()}
+		if b.observations.record(bid) {
+			log.Info("observed relay top bid", "relay", name, "slot", slot, "proposer", proposer, "builder_pubkey", topBid.BuilderPubkey, "block_hash", topBid.BlockHash, "value", topBid.Value)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package builder
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+type topBidRelay struct {
+	*testRelay
+	mu     sync.Mutex
+	topBid *TopBid
+	// delay is the time the top bid is served after, unless the context is done first
+	delay time.Duration
+}
+
+func (r *topBidRelay) GetTopBid(ctx context.Context, slot uint64) (*TopBid, error) {
+	if r.delay > 0 {
+		select {
+		case <-time.After(r.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.topBid, nil
+}
+
+func TestObserverMode(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{Observer: true})
+	relay := &topBidRelay{testRelay: testRelay, topBid: &TopBid{BlockHash: boostTypes.Hash{0x01}, Value: big.NewInt(10)}}
+	builder.relay = relay
+
+	// The slot is observed instead of built
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.Equal(t, uint64(25), testRelay.requestedSlot)
+	require.Nil(t, testRelay.submittedMsg)
+	require.Eventually(t, func() bool { return len(builder.ObservedBids(25)) == 1 }, time.Second, 10*time.Millisecond)
+
+	bid := builder.ObservedBids(25)[0]
+	require.Equal(t, "unknown", bid.Relay)
+	require.Equal(t, testRelay.validator.Pubkey, bid.ProposerPubkey)
+	require.Equal(t, big.NewInt(10), bid.Value)
+
+	// Changes of the top bid are recorded
+	relay.mu.Lock()
+	relay.topBid = &TopBid{BlockHash: boostTypes.Hash{0x02}, Value: big.NewInt(20)}
+	relay.mu.Unlock()
+	require.Eventually(t, func() bool { return len(builder.ObservedBids(25)) == 2 }, 3*observerPollInterval, 10*time.Millisecond)
+	require.Equal(t, big.NewInt(20), builder.ObservedBids(25)[1].Value)
+
+	// The slot's later attributes don't poll it again
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.False(t, builder.observations.startPolling(25))
+
+	// Served over the RPC
+	api := NewBuilderAPI(builder)
+	bids, err := api.ObservedBids(25)
+	require.NoError(t, err)
+	require.Equal(t, builder.ObservedBids(25), bids)
+
+	api = NewBuilderAPI(&Builder{})
+	_, err = api.ObservedBids(25)
+	require.Error(t, err)
+}
+
+func TestBidObservations(t *testing.T) {
+	observations := newBidObservations()
+	bid := ObservedBid{TopBid: TopBid{BlockHash: boostTypes.Hash{0x01}, Value: big.NewInt(10)}, Relay: "relay", Slot: 10}
+	require.True(t, observations.record(bid))
+	require.False(t, observations.record(bid))
+
+	// Tracked per relay
+	other := bid
+	other.Relay = "other"
+	require.True(t, observations.record(other))
+	require.False(t, observations.record(bid))
+	require.Len(t, observations.get(10), 2)
+
+	later := bid
+	later.Slot = 11
+	require.True(t, observations.record(later))
+	require.True(t, observations.startPolling(10))
+	require.False(t, observations.startPolling(10))
+	observations.pruneBefore(11)
+	require.Empty(t, observations.get(10))
+	require.Len(t, observations.get(11), 1)
+	require.True(t, observations.startPolling(10))
+}
+
+func TestRemoteRelayGetTopBid(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/relay/v1/data/bidtraces/builder_blocks_received" || r.URL.Query().Get("slot") != "25" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[
+			{"slot": "25", "block_hash": "0x0100000000000000000000000000000000000000000000000000000000000000", "value": "10"},
+			{"slot": "25", "block_hash": "0x0200000000000000000000000000000000000000000000000000000000000000", "value": "30"},
+			{"slot": "25", "block_hash": "0x0300000000000000000000000000000000000000000000000000000000000000", "value": "20"}
+		]`))
+	}))
+	defer srv.Close()
+
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+
+	topBid, err := relay.GetTopBid(context.Background(), 25)
+	require.NoError(t, err)
+	require.Equal(t, boostTypes.Hash{0x02}, topBid.BlockHash)
+	require.Equal(t, big.NewInt(30), topBid.Value)
+
+	topBid, err = relay.GetTopBid(context.Background(), 26)
+	require.NoError(t, err)
+	require.Nil(t, topBid)
+}
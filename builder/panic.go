@@ -0,0 +1,26 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrPanic is returned in place of a panic recovered in the builder
+var ErrPanic = errors.New("recovered panic")
+
+// recoverPanic converts a panic into an ErrPanic set in errp, logging the stack. It must be deferred.
+func recoverPanic(errp *error, in string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	panicsCounter.Inc(1)
+	log.Error("recovered panic", "in", in, "panic", r, "stack", string(debug.Stack()))
+	if errp != nil {
+		*errp = fmt.Errorf("%w in %s: %v", ErrPanic, in, r)
+	}
+}
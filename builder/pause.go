@@ -0,0 +1,126 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrPaused is returned for the blocks not submitted while the builder is paused
+var ErrPaused = errors.New("builder paused")
+
+// MaintenanceWindow is a scheduled period during which the builder is paused.
+type MaintenanceWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (w MaintenanceWindow) contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// ParseMaintenanceWindows parses comma separated start/end pairs of RFC 3339 times.
+func ParseMaintenanceWindows(windows string) ([]MaintenanceWindow, error) {
+	var parsed []MaintenanceWindow
+	for _, window := range strings.Split(windows, ",") {
+		start, end, found := strings.Cut(strings.TrimSpace(window), "/")
+		if !found {
+			return nil, fmt.Errorf("invalid maintenance window %q, expected start/end", window)
+		}
+		startTime, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window start %q: %w", start, err)
+		}
+		endTime, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window end %q: %w", end, err)
+		}
+		if !endTime.After(startTime) {
+			return nil, fmt.Errorf("maintenance window %q ends before it starts", window)
+		}
+		parsed = append(parsed, MaintenanceWindow{Start: startTime, End: endTime})
+	}
+	return parsed, nil
+}
+
+// pauseState is the builder paused manually, by the maintenance windows or after all relays rejected a submission.
+type pauseState struct {
+	mu      sync.Mutex
+	manual  bool
+	windows []MaintenanceWindow
+	// rejectedUntil is the end of the pause after all relays rejected a submission
+	rejectedUntil time.Time
+}
+
+// reason returns why the builder is paused at the time, empty if it is not.
+func (p *pauseState) reason(now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.manual {
+		return "paused"
+	}
+	if now.Before(p.rejectedUntil) {
+		return "all relays rejected"
+	}
+	for _, window := range p.windows {
+		if window.contains(now) {
+			return "maintenance window"
+		}
+	}
+	return ""
+}
+
+func (p *pauseState) set(paused bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.manual = paused
+}
+
+func (p *pauseState) pauseRejected(until time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if until.After(p.rejectedUntil) {
+		p.rejectedUntil = until
+	}
+}
+
+// Pause stops building and submitting blocks until Resume, the payload attributes received meanwhile are skipped.
+func (b *Builder) Pause() {
+	b.pause.set(true)
+	log.Info("builder paused")
+}
+
+// Resume resumes building and submitting blocks after Pause, from the next payload attributes. The builder stays
+// paused during the maintenance windows.
+func (b *Builder) Resume() {
+	b.pause.set(false)
+	log.Info("builder resumed")
+}
+
+// Paused returns whether the builder is paused, by Pause, a maintenance window or after all relays rejected a
+// submission.
+func (b *Builder) Paused() bool {
+	return b.pause.reason(time.Now()) != ""
+}
+
+// BuilderStatus is the operational status of the builder.
+type BuilderStatus struct {
+	Paused bool `json:"paused"`
+	// PauseReason is why the builder is paused, "paused", "maintenance window" or "all relays rejected", empty if it
+	// is not
+	PauseReason string      `json:"pauseReason,omitempty"`
+	Relays      []RelayInfo `json:"relays"`
+}
+
+// Status returns the operational status of the builder.
+func (b *Builder) Status() BuilderStatus {
+	reason := b.pause.reason(time.Now())
+	return BuilderStatus{Paused: reason != "", PauseReason: reason, Relays: b.Relays()}
+}
@@ -0,0 +1,85 @@
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPause(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SingleShot: true})
+
+	// The attributes are skipped without an error while paused
+	builder.Pause()
+	require.True(t, builder.Paused())
+	require.Equal(t, "paused", builder.Status().PauseReason)
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.Nil(t, testRelay.submittedMsg)
+
+	executableData := builder.eth.(*testEthereumService).testExecutableData
+	err := builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25)
+	require.ErrorIs(t, err, ErrPaused)
+	require.Nil(t, testRelay.submittedMsg)
+
+	builder.Resume()
+	require.False(t, builder.Paused())
+	require.False(t, builder.Status().Paused)
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(26)))
+	require.NotNil(t, testRelay.submittedMsg)
+	require.Equal(t, uint64(26), testRelay.submittedMsg.Message.Slot)
+}
+
+func TestPauseMaintenanceWindow(t *testing.T) {
+	now := time.Now()
+	window := MaintenanceWindow{Start: now.Add(-time.Minute), End: now.Add(time.Minute)}
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{MaintenanceWindows: []MaintenanceWindow{window}})
+
+	require.Equal(t, BuilderStatus{Paused: true, PauseReason: "maintenance window", Relays: builder.Relays()}, builder.Status())
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.Nil(t, testRelay.submittedMsg)
+
+	// Resume doesn't end the maintenance window
+	builder.Resume()
+	require.True(t, builder.Paused())
+
+	require.False(t, window.contains(window.End))
+	require.True(t, window.contains(window.Start))
+}
+
+func TestParseMaintenanceWindows(t *testing.T) {
+	windows, err := ParseMaintenanceWindows("2023-01-02T10:00:00Z/2023-01-02T11:00:00Z, 2023-01-03T10:00:00Z/2023-01-03T10:30:00Z")
+	require.NoError(t, err)
+	require.Len(t, windows, 2)
+	require.Equal(t, time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC), windows[0].Start)
+	require.Equal(t, time.Date(2023, 1, 3, 10, 30, 0, 0, time.UTC), windows[1].End)
+
+	for _, invalid := range []string{"2023-01-02T10:00:00Z", "2023-01-02T11:00:00Z/2023-01-02T10:00:00Z", "yesterday/today"} {
+		_, err = ParseMaintenanceWindows(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestBuilderAPI(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("builder", NewBuilderAPI(builder)))
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	require.NoError(t, client.Call(nil, "builder_pause"))
+	require.True(t, builder.Paused())
+	var status BuilderStatus
+	require.NoError(t, client.Call(&status, "builder_status"))
+	require.True(t, status.Paused)
+	require.NoError(t, client.Call(nil, "builder_resume"))
+	require.False(t, builder.Paused())
+
+	// The service's lifecycle is not served
+	for _, method := range []string{"builder_start", "builder_stop", "builder_enablePprof"} {
+		require.Error(t, client.Call(nil, method), method)
+	}
+}
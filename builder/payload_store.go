@@ -0,0 +1,91 @@
+package builder
+
+import (
+	"errors"
+	"sync"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+var (
+	ErrPayloadNotFound = errors.New("payload not found")
+	ErrPayloadMismatch = errors.New("payload does not match blinded header")
+)
+
+type submittedPayload struct {
+	slot    uint64
+	payload *boostTypes.ExecutionPayload
+}
+
+// PayloadStore keeps the execution payloads of submitted blocks keyed by block hash,
+// so that a winning bid can be unblinded when the signed blinded block comes back.
+type PayloadStore struct {
+	mu       sync.RWMutex
+	payloads map[boostTypes.Hash]submittedPayload
+}
+
+func NewPayloadStore() *PayloadStore {
+	return &PayloadStore{
+		payloads: make(map[boostTypes.Hash]submittedPayload),
+	}
+}
+
+func (s *PayloadStore) Add(slot uint64, payload *boostTypes.ExecutionPayload) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.payloads[payload.BlockHash] = submittedPayload{slot: slot, payload: payload}
+}
+
+func (s *PayloadStore) Get(blockHash boostTypes.Hash) (*boostTypes.ExecutionPayload, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sp, found := s.payloads[blockHash]
+	if !found {
+		return nil, false
+	}
+	return sp.payload, true
+}
+
+// GetForHeader returns the stored payload for the blinded header, checking that the full payload matches it.
+func (s *PayloadStore) GetForHeader(header *boostTypes.ExecutionPayloadHeader) (*boostTypes.ExecutionPayload, error) {
+	if header == nil {
+		return nil, ErrPayloadNotFound
+	}
+
+	payload, found := s.Get(header.BlockHash)
+	if !found {
+		return nil, ErrPayloadNotFound
+	}
+
+	payloadHeader, err := boostTypes.PayloadToPayloadHeader(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ExecutionPayloadHeaderEqual(payloadHeader, header) {
+		return nil, ErrPayloadMismatch
+	}
+
+	return payload, nil
+}
+
+// Prune evicts payloads submitted for slots before the given slot.
+func (s *PayloadStore) Prune(slot uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for blockHash, sp := range s.payloads {
+		if sp.slot < slot {
+			delete(s.payloads, blockHash)
+		}
+	}
+}
+
+func (s *PayloadStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.payloads)
+}
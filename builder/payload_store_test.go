@@ -0,0 +1,51 @@
+package builder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/beacon"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadStore(t *testing.T) {
+	store := NewPayloadStore()
+
+	payload, err := executableDataToExecutionPayload(&beacon.ExecutableDataV1{
+		ParentHash:    common.Hash{0x01},
+		StateRoot:     common.Hash{0x03},
+		ReceiptsRoot:  common.Hash{0x04},
+		BlockHash:     common.Hash{0x02},
+		BaseFeePerGas: big.NewInt(12),
+		ExtraData:     []byte{},
+	})
+	require.NoError(t, err)
+
+	header, err := boostTypes.PayloadToPayloadHeader(payload)
+	require.NoError(t, err)
+
+	_, err = store.GetForHeader(header)
+	require.ErrorIs(t, err, ErrPayloadNotFound)
+
+	store.Add(10, payload)
+
+	res, err := store.GetForHeader(header)
+	require.NoError(t, err)
+	require.Equal(t, payload, res)
+
+	// Header with the same block hash but different contents
+	wrongHeader := *header
+	wrongHeader.GasUsed += 1
+	_, err = store.GetForHeader(&wrongHeader)
+	require.ErrorIs(t, err, ErrPayloadMismatch)
+
+	store.Prune(10)
+	require.Equal(t, 1, store.Len())
+
+	store.Prune(11)
+	require.Equal(t, 0, store.Len())
+	_, found := store.Get(payload.BlockHash)
+	require.False(t, found)
+}
@@ -0,0 +1,24 @@
+package builder
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// newPprofServer serves Go's pprof handlers under /debug/pprof/ on the address. The profiles expose the process
+// internals, the address should not be reachable from outside.
+func newPprofServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
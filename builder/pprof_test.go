@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPprofServer(t *testing.T) {
+	srv := newPprofServer("127.0.0.1:0")
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/pprof/goroutine", "/debug/pprof/cmdline"} {
+		rr := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rr.Code, path)
+	}
+
+	rr := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPprofServerStopped(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	builder, err := NewBuilderFromConfig(newTestConfig(t))
+	require.NoError(t, err)
+	service := &Service{builder: builder}
+	service.enablePprof(addr)
+	require.NoError(t, service.Start())
+	get := func() error {
+		resp, err := http.Get("http://" + addr + "/debug/pprof/")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	require.Eventually(t, func() bool { return get() == nil }, time.Second, 10*time.Millisecond)
+
+	// The server is shut down with the service
+	require.NoError(t, service.Stop())
+	require.Error(t, get())
+}
@@ -0,0 +1,63 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrPrevRandaoConflict is returned for the payload attributes whose prevRandao is set and differs from the override,
+// as the override would build blocks invalid for the beacon chain the attributes come from
+var ErrPrevRandaoConflict = errors.New("payload attributes' prevRandao differs from the override")
+
+// ParsePrevRandao parses the prevRandao override, 32 bytes in hex. Returns nil if empty.
+func ParsePrevRandao(value string) (*common.Hash, error) {
+	if value == "" {
+		return nil, nil
+	}
+	decoded, err := hexutil.Decode(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prevRandao %q: %w", value, err)
+	}
+	if len(decoded) != common.HashLength {
+		return nil, fmt.Errorf("invalid prevRandao %q: %d bytes instead of %d", value, len(decoded), common.HashLength)
+	}
+	prevRandao := common.BytesToHash(decoded)
+	return &prevRandao, nil
+}
+
+// overridePrevRandao replaces the attributes' prevRandao with the override, if set. Returns ErrPrevRandaoConflict if
+// the attributes' prevRandao is set and differs from the override.
+func (b *Builder) overridePrevRandao(attrs *BuilderPayloadAttributes) error {
+	if b.prevRandaoOverride == nil {
+		return nil
+	}
+	if attrs.Random != (common.Hash{}) && attrs.Random != *b.prevRandaoOverride {
+		return fmt.Errorf("%w: slot %d, attributes %s, override %s", ErrPrevRandaoConflict, attrs.Slot, attrs.Random, *b.prevRandaoOverride)
+	}
+	log.Debug("overriding the attributes' prevRandao", "slot", attrs.Slot, "prev_randao", attrs.Random, "override", *b.prevRandaoOverride)
+	attrs.Random = *b.prevRandaoOverride
+	return nil
+}
+
+// prevRandao returns the prevRandao of the slot's block following the head: the override if set, else the head
+// state's randao mix from the beacon node. If the beacon node has no randao mix, the beacon policy applies: lenient
+// falls back to the head block's prevRandao, which is only the slot's on networks with a constant randao.
+func (b *Builder) prevRandao(ctx context.Context, slot uint64, head *types.Block) (common.Hash, error) {
+	if b.prevRandaoOverride != nil {
+		return *b.prevRandaoOverride, nil
+	}
+	randao, err := b.beaconClient.GetRandao(ctx)
+	if err == nil {
+		return randao, nil
+	}
+	if err = b.beaconValidationFailed("prevRandao", slot, err); err != nil {
+		return common.Hash{}, err
+	}
+	return head.MixDigest(), nil
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrevRandao(t *testing.T) {
+	prevRandao, err := ParsePrevRandao("")
+	require.NoError(t, err)
+	require.Nil(t, prevRandao)
+
+	prevRandao, err = ParsePrevRandao("0x" + strings.Repeat("ab", 32))
+	require.NoError(t, err)
+	require.Equal(t, common.HexToHash("0x"+strings.Repeat("ab", 32)), *prevRandao)
+
+	_, err = ParsePrevRandao("0x" + strings.Repeat("ab", 31))
+	require.ErrorContains(t, err, "31 bytes instead of 32")
+
+	_, err = ParsePrevRandao("0xzz")
+	require.Error(t, err)
+}
+
+func TestPrevRandaoOverride(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	builder.beaconClient.(*testBeaconClient).randao = common.Hash{0x01}
+
+	attrs := newTestAttributes(25)
+	attrs.Random = common.Hash{0x02}
+	require.NoError(t, builder.overridePrevRandao(attrs))
+	require.Equal(t, common.Hash{0x02}, attrs.Random)
+	prevRandao, err := builder.prevRandao(context.Background(), 25, newTestBlock(10))
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{0x01}, prevRandao)
+
+	override := common.Hash{0x03}
+	builder, _ = newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{PrevRandaoOverride: &override, DevNetwork: true})
+	builder.beaconClient.(*testBeaconClient).randao = common.Hash{0x01}
+	prevRandao, err = builder.prevRandao(context.Background(), 25, newTestBlock(10))
+	require.NoError(t, err)
+	require.Equal(t, override, prevRandao)
+
+	// The attributes without a prevRandao, or with the override's, get the override
+	attrs.Random = common.Hash{}
+	require.NoError(t, builder.overridePrevRandao(attrs))
+	require.Equal(t, override, attrs.Random)
+	require.NoError(t, builder.overridePrevRandao(attrs))
+
+	// Another prevRandao comes from a beacon chain, the attributes are refused
+	attrs.Random = common.Hash{0x02}
+	require.ErrorIs(t, builder.overridePrevRandao(attrs), ErrPrevRandaoConflict)
+	require.ErrorIs(t, builder.OnPayloadAttribute(attrs), ErrPrevRandaoConflict)
+	require.Equal(t, common.Hash{0x02}, attrs.Random)
+}
@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrProposerMismatch is returned for slots whose validator registered with the relay is not the slot's proposer
+var ErrProposerMismatch = errors.New("registered validator is not the slot's proposer")
+
+// proposerIndex returns the validator index of the slot's proposer, checking that the proposer duty is for the
+// validator registered with the relay. Returns ErrProposerMismatch if it is not, or the beacon node's error if the
+// duties could not be fetched.
+func (b *Builder) proposerIndex(slot uint64, pubkey PubkeyHex) (uint64, error) {
+	proposer, err := b.beaconClient.getProposerForSlot(slot)
+	if err != nil {
+		return 0, err
+	}
+	if !strings.EqualFold(string(proposer), string(pubkey)) {
+		return 0, fmt.Errorf("%w: registered %s, proposer %s", ErrProposerMismatch, pubkey, proposer)
+	}
+	return b.beaconClient.getProposerIndexForSlot(slot)
+}
+
+// ProposerIndexHeader carries the validator index of the slot's proposer to the relay, as the bid trace of the current
+// fork only has the proposer's pubkey
+const ProposerIndexHeader = "X-Builder-Proposer-Index"
+
+type proposerIndexKey struct{}
+
+func withProposerIndex(ctx context.Context, proposerIndex uint64) context.Context {
+	return context.WithValue(ctx, proposerIndexKey{}, proposerIndex)
+}
+
+// ProposerIndexFromContext returns the validator index of the submission's proposer, if the beacon node had it.
+func ProposerIndexFromContext(ctx context.Context) (uint64, bool) {
+	proposerIndex, ok := ctx.Value(proposerIndexKey{}).(uint64)
+	return proposerIndex, ok
+}
+
+// proposerIndexTransport sets the proposer index header on requests made with a submission context.
+type proposerIndexTransport struct {
+	base http.RoundTripper
+}
+
+func (t proposerIndexTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	proposerIndex, ok := ProposerIndexFromContext(req.Context())
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set(ProposerIndexHeader, strconv.FormatUint(proposerIndex, 10))
+	return t.base.RoundTrip(req)
+}
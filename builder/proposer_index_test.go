@@ -0,0 +1,29 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposerIndex(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	builder.beaconClient.(*testBeaconClient).validatorIndex = 42
+
+	validatorIndex, err := builder.proposerIndex(25, testRelay.validator.Pubkey)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), validatorIndex)
+
+	// The index is sent with the submissions
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.NotNil(t, testRelay.proposerIndex)
+	require.Equal(t, uint64(42), *testRelay.proposerIndex)
+	testRelay.submittedMsg = nil
+
+	// The slots of validators other than the proposer are dropped
+	testRelay.validator.Pubkey = PubkeyHex(NewRandomValidator().Pk.String())
+	_, err = builder.proposerIndex(25, testRelay.validator.Pubkey)
+	require.ErrorIs(t, err, ErrProposerMismatch)
+	require.ErrorIs(t, builder.OnPayloadAttribute(newTestAttributes(25)), ErrProposerMismatch)
+	require.Nil(t, testRelay.submittedMsg)
+}
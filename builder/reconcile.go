@@ -0,0 +1,209 @@
+package builder
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+const (
+	// reconcileDelaySlots is the number of slots after a slot starts before its block is expected in the EL
+	reconcileDelaySlots = 2
+	// maxReconcileAttempts bounds the retries of a slot whose winner or canonical block is not known yet
+	maxReconcileAttempts = 16
+)
+
+var (
+	reconcileLandedCounter = metrics.NewRegisteredCounter("builder/reconcile/landed", nil)
+	reconcileMissedCounter = metrics.NewRegisteredCounter("builder/reconcile/missed", nil)
+)
+
+// DeliveredPayload is the payload a relay delivered to a slot's proposer.
+type DeliveredPayload struct {
+	BuilderPubkey boostTypes.PublicKey
+	BlockHash     boostTypes.Hash
+}
+
+// DeliveredPayloadGetter is implemented by relays publishing the payloads delivered to proposers.
+type DeliveredPayloadGetter interface {
+	// GetDeliveredPayload returns nil if no payload was delivered for the slot.
+	GetDeliveredPayload(ctx context.Context, slot uint64) (*DeliveredPayload, error)
+}
+
+type slotSubmissions struct {
+	// blocks maps the submitted block hashes to their block numbers
+	blocks   map[boostTypes.Hash]uint64
+	won      *boostTypes.Hash
+	readyAt  time.Time
+	attempts int
+}
+
+// submissionHistory keeps the blocks submitted for recent slots until they are reconciled.
+type submissionHistory struct {
+	mu    sync.Mutex
+	slots map[uint64]*slotSubmissions
+}
+
+func newSubmissionHistory() *submissionHistory {
+	return &submissionHistory{slots: make(map[uint64]*slotSubmissions)}
+}
+
+func (h *submissionHistory) Add(slot uint64, blockHash boostTypes.Hash, blockNumber uint64, readyAt time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	submissions, found := h.slots[slot]
+	if !found {
+		submissions = &slotSubmissions{blocks: make(map[boostTypes.Hash]uint64), readyAt: readyAt}
+		h.slots[slot] = submissions
+	}
+	submissions.blocks[blockHash] = blockNumber
+}
+
+// MarkWon records that the block was unblinded, which means it won the slot's auction.
+func (h *submissionHistory) MarkWon(slot uint64, blockHash boostTypes.Hash) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	submissions, found := h.slots[slot]
+	if !found {
+		return
+	}
+	if _, submitted := submissions.blocks[blockHash]; submitted {
+		submissions.won = &blockHash
+	}
+}
+
+// ready returns the slots due for reconciliation.
+func (h *submissionHistory) ready(now time.Time) map[uint64]*slotSubmissions {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	res := make(map[uint64]*slotSubmissions)
+	for slot, submissions := range h.slots {
+		if !now.Before(submissions.readyAt) {
+			res[slot] = submissions
+		}
+	}
+	return res
+}
+
+// retry keeps the slot for another attempt, returning false and dropping it once out of attempts.
+func (h *submissionHistory) retry(slot uint64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	submissions, found := h.slots[slot]
+	if !found {
+		return false
+	}
+	submissions.attempts++
+	if submissions.attempts >= maxReconcileAttempts {
+		delete(h.slots, slot)
+		return false
+	}
+	return true
+}
+
+func (h *submissionHistory) remove(slot uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.slots, slot)
+}
+
+// pruneBefore drops the submissions of the slots before the slot, whether reconciled or not.
+func (h *submissionHistory) pruneBefore(slot uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for submittedSlot := range h.slots {
+		if submittedSlot < slot {
+			delete(h.slots, submittedSlot)
+		}
+	}
+}
+
+func (h *submissionHistory) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.slots)
+}
+
+func (b *Builder) runReconciliation() {
+	ticker := time.NewTicker(b.slotDuration())
+	defer ticker.Stop()
+
+	for range ticker.C {
+		b.reconcile(time.Now())
+	}
+}
+
+// reconcile checks whether the winning blocks of past slots landed on-chain.
+func (b *Builder) reconcile(now time.Time) {
+	for slot, submissions := range b.history.ready(now) {
+		b.history.mu.Lock()
+		won := submissions.won
+		b.history.mu.Unlock()
+
+		if won == nil {
+			delivered, err := b.deliveredPayload(slot)
+			if err != nil {
+				log.Info("could not get delivered payload from relay", "err", err, "slot", slot)
+				b.history.retry(slot)
+				continue
+			}
+			if delivered == nil || !b.ownsPubkey(delivered.BuilderPubkey) {
+				// Not won by this builder
+				b.history.remove(slot)
+				continue
+			}
+			won = &delivered.BlockHash
+		}
+
+		b.history.mu.Lock()
+		blockNumber, submitted := submissions.blocks[*won]
+		b.history.mu.Unlock()
+		if !submitted {
+			log.Warn("relay delivered a block of the builder which was not submitted", "slot", slot, "block_hash", won.String())
+			b.history.remove(slot)
+			continue
+		}
+
+		canonicalHash := b.eth.GetCanonicalHash(blockNumber)
+		if canonicalHash == (common.Hash{}) {
+			// The EL did not import the block number yet
+			if !b.history.retry(slot) {
+				log.Warn("bid won but the block number was not imported", "slot", slot, "block_hash", won.String(), "block_number", blockNumber)
+				reconcileMissedCounter.Inc(1)
+			}
+			continue
+		}
+
+		if canonicalHash == common.Hash(*won) {
+			log.Info("won block landed on-chain", "slot", slot, "block_hash", won.String(), "block_number", blockNumber)
+			reconcileLandedCounter.Inc(1)
+		} else {
+			log.Warn("bid won but block did not land on-chain", "slot", slot, "block_hash", won.String(), "block_number", blockNumber, "canonical_hash", canonicalHash)
+			reconcileMissedCounter.Inc(1)
+		}
+		b.history.remove(slot)
+	}
+}
+
+func (b *Builder) deliveredPayload(slot uint64) (*DeliveredPayload, error) {
+	getter, ok := b.relay.(DeliveredPayloadGetter)
+	if !ok {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), registrationRequestTimeout)
+	defer cancel()
+	return getter.GetDeliveredPayload(ctx, slot)
+}
@@ -0,0 +1,82 @@
+package builder
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/beacon"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+type deliveringRelay struct {
+	testRelay
+	delivered map[uint64]*DeliveredPayload
+}
+
+func (r *deliveringRelay) GetDeliveredPayload(ctx context.Context, slot uint64) (*DeliveredPayload, error) {
+	return r.delivered[slot], nil
+}
+
+func TestReconcile(t *testing.T) {
+	ethService := newTestEthereumService()
+	ethService.canonicalHashes = map[uint64]common.Hash{}
+	builder, _ := newTestBuilderWithOptions(t, ethService, BuilderOptions{})
+	relay := &deliveringRelay{delivered: map[uint64]*DeliveredPayload{}}
+	builder.relay = relay
+
+	submit := func(slot uint64, blockHash common.Hash, blockNumber uint64) {
+		executableData := &beacon.ExecutableDataV1{StateRoot: common.Hash{0x07, 0x16}, ReceiptsRoot: common.Hash{0x08, 0x20}, BlockHash: blockHash, Number: blockNumber, BaseFeePerGas: big.NewInt(16), Transactions: [][]byte{}}
+		require.NoError(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, slot))
+	}
+
+	// Slot 10 won and landed, slot 11 won but another block landed, slot 12 lost, slot 13 not imported yet
+	submit(10, common.Hash{0x10}, 100)
+	relay.delivered[10] = &DeliveredPayload{BuilderPubkey: builder.builderPublicKey, BlockHash: boostTypes.Hash{0x10}}
+	ethService.canonicalHashes[100] = common.Hash{0x10}
+
+	submit(11, common.Hash{0x11}, 101)
+	relay.delivered[11] = &DeliveredPayload{BuilderPubkey: builder.builderPublicKey, BlockHash: boostTypes.Hash{0x11}}
+	ethService.canonicalHashes[101] = common.Hash{0xff}
+
+	submit(12, common.Hash{0x12}, 102)
+	relay.delivered[12] = &DeliveredPayload{BlockHash: boostTypes.Hash{0xee}}
+
+	submit(13, common.Hash{0x13}, 103)
+	relay.delivered[13] = &DeliveredPayload{BuilderPubkey: builder.builderPublicKey, BlockHash: boostTypes.Hash{0x13}}
+
+	require.Equal(t, 4, builder.history.Len())
+
+	// Not due yet
+	builder.reconcile(time.Now())
+	require.Equal(t, 4, builder.history.Len())
+
+	later := time.Now().Add(10 * builder.slotDuration())
+	builder.reconcile(later)
+	require.Equal(t, 1, builder.history.Len())
+
+	ethService.canonicalHashes[103] = common.Hash{0x13}
+	builder.reconcile(later)
+	require.Equal(t, 0, builder.history.Len())
+}
+
+func TestSubmissionHistoryMarkWon(t *testing.T) {
+	history := newSubmissionHistory()
+	readyAt := time.Now()
+
+	history.Add(10, boostTypes.Hash{0x01}, 100, readyAt)
+	history.MarkWon(10, boostTypes.Hash{0x02})
+	require.Nil(t, history.ready(readyAt)[10].won)
+
+	history.MarkWon(10, boostTypes.Hash{0x01})
+	require.Equal(t, boostTypes.Hash{0x01}, *history.ready(readyAt)[10].won)
+
+	for i := 0; i < maxReconcileAttempts-1; i++ {
+		require.True(t, history.retry(10))
+	}
+	require.False(t, history.retry(10))
+	require.Equal(t, 0, history.Len())
+}
@@ -0,0 +1,146 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+const registrationRequestTimeout = 5 * time.Second
+
+// ErrBuilderRegistrationUnsupported is returned by the relays without the builder registration endpoints, which are
+// not part of the relay API
+var ErrBuilderRegistrationUnsupported = errors.New("relay does not support builder registrations")
+
+// BuilderRegistrar is implemented by relays which keep track of the builders allowed to submit blocks.
+type BuilderRegistrar interface {
+	IsBuilderRegistered(ctx context.Context, pubkey boostTypes.PublicKey) (bool, error)
+	// RegisterBuilder registers the builder pubkey, signed with the builder key in the builder domain.
+	// The registration uses the validator registration message format with an empty fee recipient and gas limit.
+	RegisterBuilder(ctx context.Context, registration *boostTypes.SignedValidatorRegistration) error
+}
+
+type registrationState int
+
+const (
+	registrationUnknown registrationState = iota
+	registrationRegistered
+	registrationUnregistered
+)
+
+func (s registrationState) String() string {
+	switch s {
+	case registrationRegistered:
+		return "registered"
+	case registrationUnregistered:
+		return "unregistered"
+	default:
+		return "unknown"
+	}
+}
+
+// registrationMonitor periodically checks that the builder is registered with the relay, re-registering it if not.
+// It stops once the relay turns out not to support the builder registrations.
+type registrationMonitor struct {
+	registrar BuilderRegistrar
+	interval  time.Duration
+
+	builderSecretKey     *bls.SecretKey
+	builderPublicKey     boostTypes.PublicKey
+	builderSigningDomain boostTypes.Domain
+
+	state registrationState
+}
+
+// registrationMonitors returns a monitor for each relay keeping track of the builders, the relay members of a
+// MultiRelay each with their own, registering the key the relay's submissions are signed with.
+func (b *Builder) registrationMonitors(interval time.Duration) []*registrationMonitor {
+	var monitors []*registrationMonitor
+	for _, relay := range relayMembers(b.relay) {
+		registrar, ok := relay.(BuilderRegistrar)
+		if !ok {
+			continue
+		}
+		key := b.keyFor(relay)
+		monitors = append(monitors, &registrationMonitor{
+			registrar:            registrar,
+			interval:             interval,
+			builderSecretKey:     key.sk,
+			builderPublicKey:     key.pk,
+			builderSigningDomain: b.builderSigningDomain,
+		})
+	}
+	return monitors
+}
+
+func (m *registrationMonitor) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for m.check() {
+		<-ticker.C
+	}
+}
+
+// check returns false if the relay does not support the builder registrations.
+func (m *registrationMonitor) check() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), registrationRequestTimeout)
+	defer cancel()
+
+	registered, err := m.registrar.IsBuilderRegistered(ctx, m.builderPublicKey)
+	if err != nil {
+		log.Warn("could not check builder registration", "err", err, "pubkey", m.builderPublicKey.String())
+		return true
+	}
+
+	if !registered {
+		err = m.register(ctx)
+		if errors.Is(err, ErrBuilderRegistrationUnsupported) {
+			log.Info("relay does not support builder registrations, not monitoring the registration", "err", err, "pubkey", m.builderPublicKey.String())
+			return false
+		}
+		if err != nil {
+			log.Error("could not register builder", "err", err, "pubkey", m.builderPublicKey.String())
+			m.setState(registrationUnregistered)
+			return true
+		}
+		log.Info("registered builder with relay", "pubkey", m.builderPublicKey.String())
+	}
+
+	m.setState(registrationRegistered)
+	return true
+}
+
+func (m *registrationMonitor) register(ctx context.Context) error {
+	msg := boostTypes.RegisterValidatorRequestMessage{
+		Timestamp: uint64(time.Now().Unix()),
+		Pubkey:    m.builderPublicKey,
+	}
+
+	signature, err := boostTypes.SignMessage(&msg, m.builderSigningDomain, m.builderSecretKey)
+	if err != nil {
+		return err
+	}
+
+	return m.registrar.RegisterBuilder(ctx, &boostTypes.SignedValidatorRegistration{
+		Message:   &msg,
+		Signature: signature,
+	})
+}
+
+func (m *registrationMonitor) setState(state registrationState) {
+	if m.state == state {
+		return
+	}
+
+	if state == registrationRegistered {
+		log.Info("builder registration state changed", "from", m.state, "to", state, "pubkey", m.builderPublicKey.String())
+	} else {
+		log.Warn("builder registration state changed", "from", m.state, "to", state, "pubkey", m.builderPublicKey.String())
+	}
+	m.state = state
+}
@@ -0,0 +1,105 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+type testRegistrar struct {
+	registered    bool
+	checkErr      error
+	registerErr   error
+	registrations []*boostTypes.SignedValidatorRegistration
+}
+
+func (r *testRegistrar) IsBuilderRegistered(ctx context.Context, pubkey boostTypes.PublicKey) (bool, error) {
+	return r.registered, r.checkErr
+}
+
+func (r *testRegistrar) RegisterBuilder(ctx context.Context, registration *boostTypes.SignedValidatorRegistration) error {
+	if r.registerErr != nil {
+		return r.registerErr
+	}
+	r.registrations = append(r.registrations, registration)
+	r.registered = true
+	return nil
+}
+
+func TestRegistrationMonitor(t *testing.T) {
+	sk, err := bls.GenerateRandomSecretKey()
+	require.NoError(t, err)
+	var pk boostTypes.PublicKey
+	pk.FromSlice(bls.PublicKeyFromSecretKey(sk).Compress())
+	bDomain := boostTypes.ComputeDomain(boostTypes.DomainTypeAppBuilder, [4]byte{0x02, 0x0, 0x0, 0x0}, boostTypes.Hash{})
+
+	registrar := &testRegistrar{}
+	monitor := &registrationMonitor{
+		registrar:            registrar,
+		builderSecretKey:     sk,
+		builderPublicKey:     pk,
+		builderSigningDomain: bDomain,
+	}
+
+	// Relay forgot the builder and re-registration fails
+	registrar.registerErr = errors.New("registration failed")
+	monitor.check()
+	require.Equal(t, registrationUnregistered, monitor.state)
+
+	// Re-registered
+	registrar.registerErr = nil
+	monitor.check()
+	require.Equal(t, registrationRegistered, monitor.state)
+	require.Len(t, registrar.registrations, 1)
+	require.Equal(t, pk, registrar.registrations[0].Message.Pubkey)
+	ok, err := boostTypes.VerifySignature(registrar.registrations[0].Message, bDomain, pk[:], registrar.registrations[0].Signature[:])
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Still registered, no new registration
+	monitor.check()
+	require.Equal(t, registrationRegistered, monitor.state)
+	require.Len(t, registrar.registrations, 1)
+
+	// A failed check keeps the last known state
+	registrar.checkErr = errors.New("relay unavailable")
+	registrar.registered = false
+	require.True(t, monitor.check())
+	require.Equal(t, registrationRegistered, monitor.state)
+	require.Len(t, registrar.registrations, 1)
+
+	// The monitor stops for the relays without the builder registrations
+	registrar.checkErr = nil
+	registrar.registerErr = ErrBuilderRegistrationUnsupported
+	require.False(t, monitor.check())
+}
+
+func TestRegistrationMonitors(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	srvA, srvB := httptest.NewServer(mux.NewRouter()), httptest.NewServer(mux.NewRouter())
+	t.Cleanup(srvA.Close)
+	t.Cleanup(srvB.Close)
+	relayA, err := NewRemoteRelay(srvA.URL, nil)
+	require.NoError(t, err)
+	relayB, err := NewRemoteRelay(srvB.URL, nil)
+	require.NoError(t, err)
+	relayKey := newBuilderKey(NewTestSecretKey([]byte("relay key")))
+	builder.relay = NewMultiRelay([]IRelay{relayA, relayB, &testRelay{}}, ValidatorConflictRecent)
+	builder.relayKeys = map[string]builderKey{relayB.host: relayKey}
+
+	// One monitor per member relay keeping track of the builders, with the relay's key
+	monitors := builder.registrationMonitors(time.Minute)
+	require.Len(t, monitors, 2)
+	require.Same(t, relayA, monitors[0].registrar)
+	require.Equal(t, builder.builderPublicKey, monitors[0].builderPublicKey)
+	require.Same(t, relayB, monitors[1].registrar)
+	require.Equal(t, relayKey.pk, monitors[1].builderPublicKey)
+	require.Equal(t, relayKey.sk, monitors[1].builderSecretKey)
+}
@@ -1,10 +1,15 @@
 package builder
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -16,23 +21,61 @@ import (
 )
 
 type testRelay struct {
-	validator     ValidatorData
-	requestedSlot uint64
-	submittedMsg  *boostTypes.BuilderSubmitBlockRequest
+	validator    ValidatorData
+	validatorErr error
+	constraints  RelayConstraints
+	// registeredGasLimit is 0 if the validator is not registered
+	registeredGasLimit uint64
+	requestedSlot      uint64
+	submittedMsg       *boostTypes.BuilderSubmitBlockRequest
+	submissionID       string
+	clientVersion      string
+	// proposerIndex is the submission's proposer index, nil if it had none
+	proposerIndex *uint64
+	submitErr     error
 }
 
-func (r *testRelay) SubmitBlock(msg *boostTypes.BuilderSubmitBlockRequest) error {
+func (r *testRelay) SubmitBlock(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) error {
+	if r.submitErr != nil {
+		return r.submitErr
+	}
 	r.submittedMsg = msg
+	r.submissionID, _ = SubmissionIDFromContext(ctx)
+	r.clientVersion, _ = ClientVersionFromContext(ctx)
+	r.proposerIndex = nil
+	if proposerIndex, ok := ProposerIndexFromContext(ctx); ok {
+		r.proposerIndex = &proposerIndex
+	}
 	return nil
 }
 func (r *testRelay) GetValidatorForSlot(nextSlot uint64) (ValidatorData, error) {
 	r.requestedSlot = nextSlot
+	if r.validatorErr != nil {
+		return ValidatorData{}, r.validatorErr
+	}
 	return r.validator, nil
 }
+func (r *testRelay) GetConstraints(ctx context.Context) (RelayConstraints, error) {
+	return r.constraints, nil
+}
+func (r *testRelay) GetRegisteredGasLimit(ctx context.Context, pubkey boostTypes.PublicKey) (uint64, error) {
+	if r.registeredGasLimit == 0 {
+		return 0, ErrRegistrationNotFound
+	}
+	return r.registeredGasLimit, nil
+}
 
 type RemoteRelay struct {
 	endpoint string
-	client   http.Client
+	// url is the endpoint with any credentials redacted, used in logs and errors
+	url    string
+	host   string
+	client http.Client
+	// deadlineClient sends the submissions and the validators requests, bounded by the relay's own timeouts rather
+	// than the client's
+	deadlineClient http.Client
+	// proxyURL is the proxy the requests go through, nil if there is none
+	proxyURL *url.URL
 
 	localRelay *LocalRelay
 
@@ -40,23 +83,183 @@ type RemoteRelay struct {
 	validatorSyncOngoing bool
 	lastRequestedSlot    uint64
 	validatorSlotMap     map[uint64]ValidatorData
+
+	constraintsLock      sync.Mutex
+	constraints          RelayConstraints
+	constraintsFetchedAt time.Time
+	// minValue is the configured minimum bid value, nil if not set
+	minValue *big.Int
+
+	codecLock sync.Mutex
+	codec     Codec
+
+	cancelBids bool
+
+	submitTimeout     time.Duration
+	validatorsTimeout time.Duration
+	errorBodyLimit    int
+	rateLimit         rateLimiter
+	retry             RetryPolicy
+
+	disableHTTP2 bool
+	tlsConfig    *tls.Config
+	protoLock    sync.Mutex
+	// proto is the protocol of the last response, logged when it changes
+	proto string
 }
 
-func NewRemoteRelay(endpoint string, localRelay *LocalRelay) *RemoteRelay {
+// NewRemoteRelay returns an error if the endpoint is not a valid http(s) URL.
+// The relay is not contacted besides a first attempt to get the validators, see CheckReachable.
+func NewRemoteRelay(endpoint string, localRelay *LocalRelay) (*RemoteRelay, error) {
+	return NewRemoteRelayWithOptions(endpoint, localRelay, RemoteRelayOptions{})
+}
+
+// NewRemoteRelayWithOptions returns an error if the endpoint is not a valid http(s) URL or the headers are invalid.
+// The relay is not contacted besides a first attempt to get the validators, see CheckReachable.
+func NewRemoteRelayWithOptions(endpoint string, localRelay *LocalRelay, opts RemoteRelayOptions) (*RemoteRelay, error) {
+	relayURL, err := validateRelayURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("relay %s: %w", relayURL.Redacted(), err)
+	}
+
+	var proxyURL *url.URL
+	if opts.ProxyURL != "" {
+		proxyURL, _ = validateProxyURL(opts.ProxyURL)
+	}
+
+	// The requests are logged for debugging once all headers are set
+	var base http.RoundTripper = newDNSRetryTransport(relayTransport(proxyURL, opts.TLSConfig, opts.DisableHTTP2))
+	if opts.DebugLog != "" && opts.DebugLog != RelayDebugLogOff {
+		log.Warn("relay debug logging enabled, for debugging only: every request to the relay is logged", "relay", relayURL.Redacted(), "mode", opts.DebugLog)
+		base = newDebugLogTransport(base, opts.DebugLog, opts.Headers)
+	}
+	// The submission ID, proposer index and EL version are set before the extra headers so that signers cover them
+	var transport http.RoundTripper = headerTransport{base: base, headers: opts.Headers, signer: opts.Signer}
+	if opts.ClientVersionHeader {
+		transport = clientVersionTransport{base: transport}
+	}
+	transport = submissionIDTransport{base: transport}
+	transport = proposerIndexTransport{base: transport}
+
+	codec := opts.Codec
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+
+	submitTimeout := opts.SubmitTimeout
+	if submitTimeout <= 0 {
+		submitTimeout = defaultRelaySubmitTimeout
+	}
+	validatorsTimeout := opts.ValidatorsTimeout
+	if validatorsTimeout <= 0 {
+		validatorsTimeout = defaultRelayValidatorsTimeout
+	}
+	errorBodyLimit := opts.ErrorBodyLimit
+	if errorBodyLimit <= 0 {
+		errorBodyLimit = defaultRelayErrorBodyLimit
+	}
+	rateLimitBackoff := opts.RateLimitBackoff
+	if rateLimitBackoff <= 0 {
+		rateLimitBackoff = defaultRateLimitBackoff
+	}
+
 	r := &RemoteRelay{
-		endpoint:             endpoint,
-		client:               http.Client{Timeout: time.Second},
+		endpoint:             strings.TrimSuffix(endpoint, "/"),
+		url:                  relayURL.Redacted(),
+		host:                 relayURL.Host,
+		client:               http.Client{Timeout: time.Second, Transport: transport},
+		deadlineClient:       http.Client{Transport: transport},
+		proxyURL:             proxyURL,
 		localRelay:           localRelay,
 		validatorSyncOngoing: false,
 		lastRequestedSlot:    0,
 		validatorSlotMap:     make(map[uint64]ValidatorData),
+		codec:                codec,
+		cancelBids:           opts.CancelBids,
+		minValue:             opts.MinValue,
+		submitTimeout:        submitTimeout,
+		validatorsTimeout:    validatorsTimeout,
+		errorBodyLimit:       errorBodyLimit,
+		rateLimit:            rateLimiter{defaultBackoff: rateLimitBackoff},
+		retry:                opts.Retry,
+		disableHTTP2:         opts.DisableHTTP2,
+		tlsConfig:            opts.TLSConfig,
+	}
+
+	err = r.updateValidatorsMap(0, 3)
+	if err != nil {
+		log.Error("could not connect to remote relay, continuing anyway", "err", err, "relay", r.url)
 	}
+	return r, nil
+}
 
-	err := r.updateValidatorsMap(0, 3)
+func validateRelayURL(endpoint string) (*url.URL, error) {
+	relayURL, err := url.Parse(endpoint)
 	if err != nil {
-		log.Error("could not connect to remote relay, continuing anyway", "err", err)
+		return nil, fmt.Errorf("invalid relay URL %q: %w", endpoint, err)
+	}
+	if relayURL.Scheme != "http" && relayURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid relay URL %s: scheme must be http or https", relayURL.Redacted())
+	}
+	if relayURL.Host == "" {
+		return nil, fmt.Errorf("invalid relay URL %s: missing host", relayURL.Redacted())
 	}
-	return r
+	return relayURL, nil
+}
+
+// CheckReachable probes the relay's status endpoint, returning an error if the relay is unreachable or unhealthy.
+func (r *RemoteRelay) CheckReachable(ctx context.Context) error {
+	code, err := server.SendHTTPRequest(ctx, r.client, http.MethodGet, r.endpoint+"/eth/v1/builder/status", nil, nil)
+	if err != nil {
+		return r.relayError(err)
+	}
+	if code > 299 {
+		return fmt.Errorf("non-ok response code %d from relay %s", code, r.url)
+	}
+	return nil
+}
+
+func (r *RemoteRelay) relayError(err error) error {
+	if r.proxyURL != nil {
+		return fmt.Errorf("relay %s via proxy %s: %w", r.url, r.proxyURL.Redacted(), err)
+	}
+	return fmt.Errorf("relay %s: %w", r.url, err)
+}
+
+const (
+	constraintsRefreshInterval    = 5 * time.Minute
+	defaultRelaySubmitTimeout     = 6 * time.Second
+	defaultRelayValidatorsTimeout = 3 * time.Second
+	defaultRelayErrorBodyLimit    = 1024
+)
+
+// RelayResponseError is a non-ok response of the relay to a submission, with the relay's response body truncated to
+// the relay's error body limit.
+type RelayResponseError struct {
+	StatusCode int
+	Body       string
+	// Truncated is set if the body was longer than the limit
+	Truncated bool
+}
+
+func (e *RelayResponseError) Error() string {
+	if e.Truncated {
+		return fmt.Sprintf("HTTP error response: %d / %s... (truncated)", e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("HTTP error response: %d / %s", e.StatusCode, e.Body)
+}
+
+// readResponseError reads up to limit bytes of the response body into a RelayResponseError.
+func readResponseError(resp *http.Response, limit int) *RelayResponseError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	truncated := len(body) > limit
+	if truncated {
+		body = body[:limit]
+	}
+	return &RelayResponseError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body)), Truncated: truncated}
 }
 
 type GetValidatorRelayResponse []struct {
@@ -81,10 +284,10 @@ func (r *RemoteRelay) updateValidatorsMap(currentSlot uint64, retries int) error
 	r.validatorSyncOngoing = true
 	r.validatorsLock.Unlock()
 
-	log.Info("requesting ", "currentSlot", currentSlot)
+	log.Info("requesting ", "current_slot", currentSlot)
 	newMap, err := r.getSlotValidatorMapFromRelay()
 	for err != nil && retries > 0 {
-		log.Error("could not get validators map from relay, retrying", "err", err)
+		log.Error("could not get validators map from relay, retrying", "err", err, "relay", r.url)
 		time.Sleep(time.Second)
 		newMap, err = r.getSlotValidatorMapFromRelay()
 		retries -= 1
@@ -93,7 +296,7 @@ func (r *RemoteRelay) updateValidatorsMap(currentSlot uint64, retries int) error
 	r.validatorSyncOngoing = false
 	if err != nil {
 		r.validatorsLock.Unlock()
-		log.Error("could not get validators map from relay", "err", err)
+		log.Error("could not get validators map from relay", "err", err, "relay", r.url)
 		return err
 	}
 
@@ -101,7 +304,7 @@ func (r *RemoteRelay) updateValidatorsMap(currentSlot uint64, retries int) error
 	r.lastRequestedSlot = currentSlot
 	r.validatorsLock.Unlock()
 
-	log.Info("Updated validators", "new", newMap, "for slot", currentSlot)
+	log.Info("Updated validators", "validators", newMap, "slot", currentSlot)
 
 	return nil
 }
@@ -116,7 +319,7 @@ func (r *RemoteRelay) GetValidatorForSlot(nextSlot uint64) (ValidatorData, error
 		go func() {
 			err := r.updateValidatorsMap(nextSlot, 1)
 			if err != nil {
-				log.Error("could not update validators map", "err", err)
+				log.Error("could not update validators map", "err", err, "relay", r.url)
 			}
 		}()
 	}
@@ -127,6 +330,10 @@ func (r *RemoteRelay) GetValidatorForSlot(nextSlot uint64) (ValidatorData, error
 	if r.localRelay != nil {
 		localValidator, err := r.localRelay.GetValidatorForSlot(nextSlot)
 		if err == nil {
+			if found && mostRecentRegistration(localValidator, vd) != localValidator {
+				log.Info("Local validator registration older than the relay's, ignored", "slot", nextSlot, "validator", localValidator)
+				return vd, nil
+			}
 			log.Info("Validator registration overwritten by local data", "slot", nextSlot, "validator", localValidator)
 			return localValidator, nil
 		}
@@ -136,43 +343,302 @@ func (r *RemoteRelay) GetValidatorForSlot(nextSlot uint64) (ValidatorData, error
 		return vd, nil
 	}
 
-	return ValidatorData{}, errors.New("validator not found")
+	return ValidatorData{}, fmt.Errorf("validator not found in relay %s", r.url)
 }
 
-func (r *RemoteRelay) SubmitBlock(msg *boostTypes.BuilderSubmitBlockRequest) error {
-	code, err := server.SendHTTPRequest(context.TODO(), *http.DefaultClient, http.MethodPost, r.endpoint+"/relay/v1/builder/blocks", msg, nil)
+// SubmitBlock encodes the submission with the relay's codec. Relays rejecting the codec's content type
+// with 415 Unsupported Media Type are switched to JSON, and the submission is re-sent. The failed submissions are
+// retried by the relay's retry policy. The submission, re-sent or not, is bounded by the relay's submit timeout.
+func (r *RemoteRelay) SubmitBlock(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) error {
+	if remaining, limited := r.rateLimit.limited(time.Now()); limited {
+		rateLimitedSubmissionsCounter.Inc(1)
+		return r.relayError(fmt.Errorf("%w, backing off for %s", ErrRelayRateLimited, remaining.Round(time.Millisecond)))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.submitTimeout)
+	defer cancel()
+
+	submissionID, _ := SubmissionIDFromContext(ctx)
+	clientVersion, _ := ClientVersionFromContext(ctx)
+
+	code, err := r.submitOnce(ctx, msg)
+	for retry := 1; retry < r.retry.Attempts && retryable(ctx, code, err); retry++ {
+		if !r.retry.wait(ctx, retry) {
+			break
+		}
+		retriedSubmissionsCounter.Inc(1)
+		log.Warn("retrying the submission", "submission_id", submissionID, "relay", r.url, "retry", retry, "err", err)
+		code, err = r.submitOnce(ctx, msg)
+	}
+
+	var respErr *RelayResponseError
+	if errors.As(err, &respErr) {
+		log.Error("relay rejected the submission", "submission_id", submissionID, "relay", r.url, "status", respErr.StatusCode, "body", respErr.Body, "block_hash", msg.Message.BlockHash, "slot", msg.Message.Slot)
+	}
 	if err != nil {
-		return err
+		return r.relayError(err)
 	}
 	if code > 299 {
-		return fmt.Errorf("non-ok response code %d from relay ", code)
+		return fmt.Errorf("non-ok response code %d from relay %s", code, r.url)
 	}
 
-	log.Info("submitted block", "msg", msg)
+	log.Info("submitted block", "submission_id", submissionID, "el_version", clientVersion, "relay", r.url, "submission", msg)
 
-	if r.localRelay != nil {
-		r.localRelay.SubmitBlock(msg)
+	if r.localRelay != nil && !isCanary(ctx) {
+		r.localRelay.SubmitBlock(ctx, msg)
 	}
 
 	return nil
 }
 
+// submitOnce posts the submission with the relay's codec, falling back to JSON if the relay doesn't support it.
+// Relays rejecting the codec's content type with 415 are switched to JSON. Relays answering 400 to a non-JSON
+// submission may not decode it, the submission is re-sent as JSON and the relay is switched to JSON if it accepts it.
+func (r *RemoteRelay) submitOnce(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) (int, error) {
+	codec, path := r.submissionCodec(), r.submissionPath()
+	code, err := r.postSubmission(ctx, codec, path, msg)
+	if _, isJSON := codec.(JSONCodec); isJSON || (code != http.StatusUnsupportedMediaType && code != http.StatusBadRequest) {
+		return code, err
+	}
+
+	if code == http.StatusUnsupportedMediaType {
+		log.Warn("relay does not support the submission encoding, falling back to json", "relay", r.url, "codec", fmt.Sprintf("%T", codec))
+		r.setCodec(JSONCodec{})
+		return r.postSubmission(ctx, JSONCodec{}, path, msg)
+	}
+
+	jsonCode, jsonErr := r.postSubmission(ctx, JSONCodec{}, path, msg)
+	if jsonErr == nil && jsonCode <= 299 {
+		log.Warn("relay rejected the submission encoding but accepted json, falling back to json", "relay", r.url, "codec", fmt.Sprintf("%T", codec), "err", err)
+		r.setCodec(JSONCodec{})
+	}
+	return jsonCode, jsonErr
+}
+
+func (r *RemoteRelay) setCodec(codec Codec) {
+	r.codecLock.Lock()
+	defer r.codecLock.Unlock()
+
+	r.codec = codec
+}
+
+func (r *RemoteRelay) submissionCodec() Codec {
+	r.codecLock.Lock()
+	defer r.codecLock.Unlock()
+
+	return r.codec
+}
+
+func (r *RemoteRelay) postSubmission(ctx context.Context, codec Codec, path string, msg *boostTypes.BuilderSubmitBlockRequest) (int, error) {
+	body, contentType, err := codec.Encode(msg)
+	if err != nil {
+		return 0, fmt.Errorf("could not encode submission: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("could not prepare request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := r.deadlineClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	r.observeProtocol(resp)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		backoff := r.rateLimit.backOff(resp.Header.Get("Retry-After"), time.Now())
+		rateLimitedCounter.Inc(1)
+		log.Warn("relay rate limited the submissions, backing off", "relay", r.url, "backoff", backoff)
+	}
+	if resp.StatusCode > 299 {
+		return resp.StatusCode, readResponseError(resp, r.errorBodyLimit)
+	}
+	return resp.StatusCode, nil
+}
+
+// GetConstraints returns the relay's block constraints, refreshed every constraintsRefreshInterval.
+// Relays not publishing constraints are unconstrained, besides the configured minimum value.
+func (r *RemoteRelay) GetConstraints(ctx context.Context) (RelayConstraints, error) {
+	r.constraintsLock.Lock()
+	defer r.constraintsLock.Unlock()
+
+	if !r.constraintsFetchedAt.IsZero() && time.Since(r.constraintsFetchedAt) < constraintsRefreshInterval {
+		return r.constraints, nil
+	}
+
+	var dst RelayConstraints
+	code, err := server.SendHTTPRequest(ctx, r.client, http.MethodGet, r.endpoint+"/relay/v1/builder/constraints", nil, &dst)
+	switch {
+	case code == http.StatusNotFound || code == http.StatusNoContent:
+		dst = RelayConstraints{}
+	case err != nil:
+		return RelayConstraints{}, r.relayError(err)
+	case code > 299:
+		return RelayConstraints{}, fmt.Errorf("non-ok response code %d from relay %s", code, r.url)
+	}
+	if r.minValue != nil && r.minValue.Cmp(dst.MinValue.BigInt()) > 0 {
+		if err := dst.MinValue.FromBig(r.minValue); err != nil {
+			return RelayConstraints{}, err
+		}
+	}
+
+	r.constraints = dst
+	r.constraintsFetchedAt = time.Now()
+	return dst, nil
+}
+
+// GetRegisteredGasLimit uses the relay's data API to get the validator's latest registration.
+func (r *RemoteRelay) GetRegisteredGasLimit(ctx context.Context, pubkey boostTypes.PublicKey) (uint64, error) {
+	var dst boostTypes.SignedValidatorRegistration
+	code, err := server.SendHTTPRequest(ctx, r.client, http.MethodGet, r.endpoint+"/relay/v1/data/validator_registration?pubkey="+pubkey.String(), nil, &dst)
+	if code == http.StatusNotFound || code == http.StatusNoContent {
+		return 0, ErrRegistrationNotFound
+	}
+	if err != nil {
+		return 0, r.relayError(err)
+	}
+	if code > 299 {
+		return 0, fmt.Errorf("non-ok response code %d from relay %s", code, r.url)
+	}
+	if dst.Message == nil {
+		return 0, ErrRegistrationNotFound
+	}
+
+	return dst.Message.GasLimit, nil
+}
+
+// IsBuilderRegistered reports the builder as unregistered on a 404, which is also what the relays without the builder
+// registration endpoints answer. RegisterBuilder tells them apart.
+func (r *RemoteRelay) IsBuilderRegistered(ctx context.Context, pubkey boostTypes.PublicKey) (bool, error) {
+	code, err := server.SendHTTPRequest(ctx, r.client, http.MethodGet, r.endpoint+"/relay/v1/builder/builders/"+pubkey.String(), nil, nil)
+	if code == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, r.relayError(err)
+	}
+	if code > 299 {
+		return false, fmt.Errorf("non-ok response code %d from relay %s", code, r.url)
+	}
+
+	return true, nil
+}
+
+// RegisterBuilder returns ErrBuilderRegistrationUnsupported if the relay has no builder registration endpoint, as
+// the relays implementing only the relay API.
+func (r *RemoteRelay) RegisterBuilder(ctx context.Context, registration *boostTypes.SignedValidatorRegistration) error {
+	code, err := server.SendHTTPRequest(ctx, r.client, http.MethodPost, r.endpoint+"/relay/v1/builder/builders", registration, nil)
+	if code == http.StatusNotFound || code == http.StatusMethodNotAllowed {
+		return fmt.Errorf("%w: relay %s", ErrBuilderRegistrationUnsupported, r.url)
+	}
+	if err != nil {
+		return r.relayError(err)
+	}
+	if code > 299 {
+		return fmt.Errorf("non-ok response code %d from relay %s", code, r.url)
+	}
+
+	return nil
+}
+
+// CancelsBids reports whether the submissions are sent with cancellations, each replacing the builder's previous
+// submission for the slot.
+func (r *RemoteRelay) CancelsBids() bool {
+	r.codecLock.Lock()
+	defer r.codecLock.Unlock()
+
+	return r.cancelBids
+}
+
+// submissionPath is the path of the relay's submission endpoint, with the cancellations if enabled.
+func (r *RemoteRelay) submissionPath() string {
+	if r.CancelsBids() {
+		return "/relay/v1/builder/blocks?cancellations=1"
+	}
+	return "/relay/v1/builder/blocks"
+}
+
+type deliveredPayloadResponse []struct {
+	Slot          uint64               `json:"slot,string"`
+	BuilderPubkey boostTypes.PublicKey `json:"builder_pubkey"`
+	BlockHash     boostTypes.Hash      `json:"block_hash"`
+}
+
+// GetDeliveredPayload uses the relay's data API to get the payload delivered to the slot's proposer.
+func (r *RemoteRelay) GetDeliveredPayload(ctx context.Context, slot uint64) (*DeliveredPayload, error) {
+	var dst deliveredPayloadResponse
+	code, err := server.SendHTTPRequest(ctx, r.client, http.MethodGet, fmt.Sprintf("%s/relay/v1/data/bidtraces/proposer_payload_delivered?slot=%d", r.endpoint, slot), nil, &dst)
+	if err != nil {
+		return nil, r.relayError(err)
+	}
+	if code > 299 {
+		return nil, fmt.Errorf("non-ok response code %d from relay %s", code, r.url)
+	}
+
+	for _, delivered := range dst {
+		if delivered.Slot == slot {
+			return &DeliveredPayload{BuilderPubkey: delivered.BuilderPubkey, BlockHash: delivered.BlockHash}, nil
+		}
+	}
+	return nil, nil
+}
+
+type receivedBidsResponse []struct {
+	Slot          uint64               `json:"slot,string"`
+	BuilderPubkey boostTypes.PublicKey `json:"builder_pubkey"`
+	BlockHash     boostTypes.Hash      `json:"block_hash"`
+	Value         string               `json:"value"`
+}
+
+// GetTopBid uses the relay's data API to get the most valuable of the bids the relay received for the slot.
+func (r *RemoteRelay) GetTopBid(ctx context.Context, slot uint64) (*TopBid, error) {
+	var dst receivedBidsResponse
+	code, err := server.SendHTTPRequest(ctx, r.client, http.MethodGet, fmt.Sprintf("%s/relay/v1/data/bidtraces/builder_blocks_received?slot=%d", r.endpoint, slot), nil, &dst)
+	if err != nil {
+		return nil, r.relayError(err)
+	}
+	if code > 299 {
+		return nil, fmt.Errorf("non-ok response code %d from relay %s", code, r.url)
+	}
+
+	var top *TopBid
+	for _, received := range dst {
+		if received.Slot != slot {
+			continue
+		}
+		value, ok := new(big.Int).SetString(received.Value, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid bid value %q from relay %s", received.Value, r.url)
+		}
+		if top == nil || value.Cmp(top.Value) > 0 {
+			top = &TopBid{BuilderPubkey: received.BuilderPubkey, BlockHash: received.BlockHash, Value: value}
+		}
+	}
+	return top, nil
+}
+
 func (r *RemoteRelay) getSlotValidatorMapFromRelay() (map[uint64]ValidatorData, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.validatorsTimeout)
+	defer cancel()
+
 	var dst GetValidatorRelayResponse
-	code, err := server.SendHTTPRequest(context.TODO(), *http.DefaultClient, http.MethodGet, r.endpoint+"/relay/v1/builder/validators", nil, &dst)
+	code, err := server.SendHTTPRequest(ctx, r.deadlineClient, http.MethodGet, r.endpoint+"/relay/v1/builder/validators", nil, &dst)
 	if err != nil {
-		return nil, err
+		return nil, r.relayError(err)
 	}
 
 	if code > 299 {
-		return nil, fmt.Errorf("non-ok response code %d from relay", code)
+		return nil, fmt.Errorf("non-ok response code %d from relay %s", code, r.url)
 	}
 
 	res := make(map[uint64]ValidatorData)
 	for _, data := range dst {
 		feeRecipientBytes, err := hexutil.Decode(data.Entry.Message.FeeRecipient)
 		if err != nil {
-			log.Error("Ill-formatted fee_recipient from relay", "data", data)
+			log.Error("Ill-formatted fee_recipient from relay", "relay", r.url, "data", data)
 			continue
 		}
 		var feeRecipient boostTypes.Address
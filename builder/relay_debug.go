@@ -0,0 +1,126 @@
+package builder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// RelayDebugLog selects the logging of the relay requests and responses. It is meant for debugging the
+// incompatibilities with a relay only: the logs are large, and the full bodies include the submitted blocks.
+type RelayDebugLog string
+
+const (
+	// RelayDebugLogOff does not log the requests
+	RelayDebugLogOff RelayDebugLog = "off"
+	// RelayDebugLogHashed logs the requests and responses with the SHA-256 hash and size of their bodies
+	RelayDebugLogHashed RelayDebugLog = "hashed"
+	// RelayDebugLogFull logs the requests and responses with their full bodies
+	RelayDebugLogFull RelayDebugLog = "full"
+)
+
+func ParseRelayDebugLog(mode string) (RelayDebugLog, error) {
+	switch RelayDebugLog(mode) {
+	case "":
+		return RelayDebugLogOff, nil
+	case RelayDebugLogOff, RelayDebugLogHashed, RelayDebugLogFull:
+		return RelayDebugLog(mode), nil
+	default:
+		return "", fmt.Errorf("unknown relay debug log mode %q", mode)
+	}
+}
+
+// redactedHeaders are the headers carrying credentials, never logged besides their presence
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// debugLogTransport logs the relay requests and responses, redacting the credentials.
+type debugLogTransport struct {
+	base http.RoundTripper
+	mode RelayDebugLog
+	// redacted are the canonical names of the headers logged redacted
+	redacted map[string]struct{}
+}
+
+// newDebugLogTransport returns the transport logging the requests in the mode, with the credential headers and the
+// extra headers redacted as the extra headers typically authenticate the builder.
+func newDebugLogTransport(base http.RoundTripper, mode RelayDebugLog, extraHeaders map[string]string) debugLogTransport {
+	redacted := make(map[string]struct{}, len(redactedHeaders)+len(extraHeaders))
+	for _, name := range redactedHeaders {
+		redacted[textproto.CanonicalMIMEHeaderKey(name)] = struct{}{}
+	}
+	for name := range extraHeaders {
+		redacted[textproto.CanonicalMIMEHeaderKey(name)] = struct{}{}
+	}
+	return debugLogTransport{base: base, mode: mode, redacted: redacted}
+}
+
+func (t debugLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	log.Info("relay debug: request", append([]interface{}{"method", req.Method, "url", req.URL.Redacted(), "headers", t.headers(req.Header)}, t.body(body)...)...)
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		log.Info("relay debug: request failed", "method", req.Method, "url", req.URL.Redacted(), "err", err, "duration", time.Since(start))
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	log.Info("relay debug: response", append([]interface{}{"method", req.Method, "url", req.URL.Redacted(), "status", resp.StatusCode, "protocol", resp.Proto, "duration", time.Since(start), "headers", t.headers(resp.Header)}, t.body(respBody)...)...)
+	return resp, nil
+}
+
+// headers formats the headers sorted by name, with the values of the redacted headers replaced.
+func (t debugLogTransport) headers(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	formatted := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if _, found := t.redacted[textproto.CanonicalMIMEHeaderKey(name)]; found {
+			value = "[redacted]"
+		}
+		formatted = append(formatted, name+": "+value)
+	}
+	return strings.Join(formatted, "; ")
+}
+
+// body returns the log context of the body: its size, and its hash or the body itself, hex encoded if binary.
+func (t debugLogTransport) body(body []byte) []interface{} {
+	if t.mode == RelayDebugLogHashed {
+		return []interface{}{"body_size", len(body), "body_sha256", fmt.Sprintf("%x", sha256.Sum256(body))}
+	}
+	if utf8.Valid(body) {
+		return []interface{}{"body_size", len(body), "body", string(body)}
+	}
+	return []interface{}{"body_size", len(body), "body", hexutil.Encode(body)}
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRelayDebugLog(t *testing.T) {
+	mode, err := ParseRelayDebugLog("")
+	require.NoError(t, err)
+	require.Equal(t, RelayDebugLogOff, mode)
+
+	mode, err = ParseRelayDebugLog("hashed")
+	require.NoError(t, err)
+	require.Equal(t, RelayDebugLogHashed, mode)
+
+	_, err = ParseRelayDebugLog("verbose")
+	require.Error(t, err)
+}
+
+func TestDebugLogTransport(t *testing.T) {
+	transport := newDebugLogTransport(http.DefaultTransport, RelayDebugLogFull, map[string]string{"x-api-key": "secret"})
+
+	// The credentials and the extra headers are redacted
+	header := http.Header{}
+	header.Set("Authorization", "Bearer token")
+	header.Set("X-Api-Key", "secret")
+	header.Set("Content-Type", "application/json")
+	require.Equal(t, "Authorization: [redacted]; Content-Type: application/json; X-Api-Key: [redacted]", transport.headers(header))
+
+	require.Equal(t, []interface{}{"body_size", 2, "body", "{}"}, transport.body([]byte("{}")))
+	require.Equal(t, []interface{}{"body_size", 2, "body", "0xff00"}, transport.body([]byte{0xff, 0x00}))
+	transport.mode = RelayDebugLogHashed
+	require.Equal(t, []interface{}{"body_size", 2, "body_sha256", "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}, transport.body([]byte("{}")))
+
+	// The logged bodies still reach the relay and the caller
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.Write(append([]byte("echo "), body...))
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, strings.NewReader("submission"))
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "echo submission", string(body))
+}
@@ -0,0 +1,63 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	dnsRetries      = 3
+	dnsRetryBackoff = 100 * time.Millisecond
+)
+
+// dnsRetryTransport retries the requests failing to resolve the relay's host, with a backoff doubling from
+// dnsRetryBackoff. Only DNS errors are retried, since the request never reached the relay. Requests with a body
+// which cannot be rewound are not retried.
+type dnsRetryTransport struct {
+	base    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+func newDNSRetryTransport(base http.RoundTripper) dnsRetryTransport {
+	return dnsRetryTransport{base: base, retries: dnsRetries, backoff: dnsRetryBackoff}
+}
+
+func (t dnsRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := t.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+
+		var dnsErr *net.DNSError
+		if err == nil || !errors.As(err, &dnsErr) {
+			return resp, err
+		}
+		if attempt == t.retries || (req.Body != nil && req.GetBody == nil) {
+			return nil, fmt.Errorf("could not resolve relay host %s: %w", dnsErr.Name, err)
+		}
+
+		log.Debug("could not resolve relay host, retrying", "err", err, "host", dnsErr.Name, "attempt", attempt+1, "retry_in", backoff)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, fmt.Errorf("could not resolve relay host %s: %w", dnsErr.Name, err)
+		case <-timer.C:
+		}
+		backoff *= 2
+
+		if req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
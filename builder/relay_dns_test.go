@@ -0,0 +1,64 @@
+package builder
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// flakyDNSTransport fails to resolve the host the first failures times, recording the bodies of the requests.
+type flakyDNSTransport struct {
+	failures int
+	attempts int
+	bodies   []string
+}
+
+func (t *flakyDNSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.attempts++
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		t.bodies = append(t.bodies, string(body))
+	}
+	if t.attempts <= t.failures {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "server misbehaving", Name: req.URL.Hostname(), IsTemporary: true}}
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestDNSRetryTransport(t *testing.T) {
+	base := &flakyDNSTransport{failures: 2}
+	transport := dnsRetryTransport{base: base, retries: 3, backoff: time.Millisecond}
+
+	req, err := http.NewRequest(http.MethodPost, "http://relay.example.com/relay/v1/builder/blocks", bytes.NewReader([]byte("submission")))
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 3, base.attempts)
+	require.Equal(t, []string{"submission", "submission", "submission"}, base.bodies)
+
+	// The error names the host once the retries are exhausted
+	base = &flakyDNSTransport{failures: 10}
+	transport.base = base
+	req, err = http.NewRequest(http.MethodGet, "http://relay.example.com/relay/v1/builder/validators", nil)
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	require.ErrorContains(t, err, "could not resolve relay host relay.example.com")
+	var dnsErr *net.DNSError
+	require.ErrorAs(t, err, &dnsErr)
+	require.Equal(t, 4, base.attempts)
+
+	// Bodies which cannot be rewound are not retried
+	base = &flakyDNSTransport{failures: 10}
+	transport.base = base
+	req, err = http.NewRequest(http.MethodPost, "http://relay.example.com/relay/v1/builder/blocks", io.NopCloser(bytes.NewReader([]byte("submission"))))
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	require.Error(t, err)
+	require.Equal(t, 1, base.attempts)
+}
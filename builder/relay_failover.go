@@ -0,0 +1,51 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// ErrFailoverFailed is returned when none of the failover relays accepted a failed submission
+var ErrFailoverFailed = errors.New("no failover relay accepted the submission")
+
+// submitToFailoverRelays re-submits the signed block of a failed submission to the failover relays in order until
+// one accepts it, without rebuilding it, and returns the name of the relay which accepted it. Each relay is sent the
+// submission signed with its key, skipping the relays whose minimum value is above the bid.
+func (b *Builder) submitToFailoverRelays(ctx context.Context, req *boostTypes.BuilderSubmitBlockRequest) (string, error) {
+	value := req.Message.Value.BigInt()
+
+	var failures []string
+	for _, relay := range b.failoverRelays {
+		name, _ := relayIdentity(relay)
+		err := checkMinValue(ctx, relay, b.netValue(relay, value))
+		if err == nil {
+			var relayReq *boostTypes.BuilderSubmitBlockRequest
+			relayReq, err = b.signedFor(relay, req)
+			if err == nil {
+				err = b.submitUnlessInFlight(ctx, relay, relayReq)
+			}
+		}
+		if err == nil {
+			return name, nil
+		}
+		log.Debug("failover relay did not accept the submission", "err", err, "relay", name, "block_hash", req.Message.BlockHash)
+		failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+	}
+	return "", fmt.Errorf("%w: %s", ErrFailoverFailed, strings.Join(failures, "; "))
+}
+
+// submitUnlessInFlight submits the block to the relay unless it is already in flight to it.
+func (b *Builder) submitUnlessInFlight(ctx context.Context, relay IRelay, req *boostTypes.BuilderSubmitBlockRequest) error {
+	blockHash := req.ExecutionPayload.BlockHash
+	if !b.inFlight.start(relay, blockHash) {
+		duplicateSubmissionsCounter.Inc(1)
+		return ErrSubmissionInFlight
+	}
+	defer b.inFlight.done(relay, blockHash)
+	return relay.SubmitBlock(ctx, req)
+}
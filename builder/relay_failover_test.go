@@ -0,0 +1,36 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailoverRelays(t *testing.T) {
+	flakyFailover := &testRelay{submitErr: errors.New("relay unavailable")}
+	failover := &testRelay{}
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{FailoverRelays: []IRelay{flakyFailover, failover}})
+
+	executableData := newTestExecutableData()
+
+	// The failover relays are not submitted to while the relay accepts the submissions
+	require.NoError(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25))
+	require.NotNil(t, testRelay.submittedMsg)
+	require.Nil(t, failover.submittedMsg)
+
+	// The signed block is sent to the failover relays in order
+	testRelay.submitErr = errors.New("relay unavailable")
+	require.NoError(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 26))
+	require.NotNil(t, failover.submittedMsg)
+	require.Equal(t, uint64(26), failover.submittedMsg.Message.Slot)
+	require.Equal(t, builder.builderPublicKey, failover.submittedMsg.Message.BuilderPubkey)
+
+	failover.submitErr = errors.New("relay unavailable")
+	err := builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 27)
+	require.ErrorContains(t, err, "relay unavailable")
+	_, err = builder.submitToFailoverRelays(context.Background(), failover.submittedMsg)
+	require.ErrorIs(t, err, ErrFailoverFailed)
+}
@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RelayFee is the fee a relay deducts from the bid value before it reaches the proposer: Bps basis points of the
+// value plus Fixed wei.
+type RelayFee struct {
+	Bps   uint64
+	Fixed *big.Int
+}
+
+// NetValue returns the value the proposer receives from a bid of the value, but not less than zero.
+func (f RelayFee) NetValue(value *big.Int) *big.Int {
+	net := new(big.Int).Set(value)
+	if f.Bps > 0 {
+		fee := new(big.Int).Mul(value, new(big.Int).SetUint64(f.Bps))
+		net.Sub(net, fee.Quo(fee, big.NewInt(10_000)))
+	}
+	if f.Fixed != nil {
+		net.Sub(net, f.Fixed)
+	}
+	if net.Sign() < 0 {
+		net.SetInt64(0)
+	}
+	return net
+}
+
+// ParseRelayFees parses "relay=bps:wei" pairs into the fees of the relays by relay name in Relays, where bps is the
+// fee in basis points of the bid value and wei the fixed fee. The fixed fee may be omitted.
+func ParseRelayFees(pairs []string) (map[string]RelayFee, error) {
+	fees := make(map[string]RelayFee, len(pairs))
+	for _, pair := range pairs {
+		relay, fee, found := strings.Cut(pair, "=")
+		relay = strings.TrimSpace(relay)
+		if !found || relay == "" {
+			return nil, fmt.Errorf("invalid relay fee %q, expected relay=bps:wei", pair)
+		}
+
+		bpsStr, fixedStr, hasFixed := strings.Cut(strings.TrimSpace(fee), ":")
+		bps, err := strconv.ParseUint(bpsStr, 10, 64)
+		if err != nil || bps > 10_000 {
+			return nil, fmt.Errorf("invalid relay fee %q, expected up to 10000 basis points", pair)
+		}
+		relayFee := RelayFee{Bps: bps}
+		if hasFixed {
+			fixed, ok := new(big.Int).SetString(fixedStr, 10)
+			if !ok || fixed.Sign() < 0 {
+				return nil, fmt.Errorf("invalid relay fee %q, expected a fixed fee in wei", pair)
+			}
+			relayFee.Fixed = fixed
+		}
+		fees[relay] = relayFee
+	}
+	return fees, nil
+}
+
+// netValue returns the value the proposer receives from a bid of the value through the relay.
+func (b *Builder) netValue(relay IRelay, value *big.Int) *big.Int {
+	name, _ := relayIdentity(relay)
+	fee, found := b.relayFees[name]
+	if !found {
+		return value
+	}
+	return fee.NetValue(value)
+}
+
+// anyRelayAcceptsValue reports whether any enabled relay accepts a bid of the value, net of the relay's fee.
+func (b *Builder) anyRelayAcceptsValue(ctx context.Context, value *big.Int) bool {
+	for _, relay := range b.relays() {
+		if b.toggles.enabled(relay) && b.netValue(relay, value).Cmp(relayMinValue(ctx, relay)) >= 0 {
+			return true
+		}
+	}
+	return false
+}
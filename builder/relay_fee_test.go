@@ -0,0 +1,51 @@
+package builder
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayFeeNetValue(t *testing.T) {
+	require.Equal(t, big.NewInt(1000), RelayFee{}.NetValue(big.NewInt(1000)))
+	require.Equal(t, big.NewInt(950), RelayFee{Bps: 500}.NetValue(big.NewInt(1000)))
+	require.Equal(t, big.NewInt(940), RelayFee{Bps: 500, Fixed: big.NewInt(10)}.NetValue(big.NewInt(1000)))
+	require.Zero(t, RelayFee{Fixed: big.NewInt(2000)}.NetValue(big.NewInt(1000)).Sign())
+}
+
+func TestParseRelayFees(t *testing.T) {
+	fees, err := ParseRelayFees([]string{"relay.example.com=50", " other.example.com = 100:1000000 "})
+	require.NoError(t, err)
+	require.Equal(t, map[string]RelayFee{
+		"relay.example.com": {Bps: 50},
+		"other.example.com": {Bps: 100, Fixed: big.NewInt(1_000_000)},
+	}, fees)
+
+	for _, pair := range []string{"relay.example.com", "=50", "relay.example.com=10001", "relay.example.com=50:-1", "relay.example.com=0.5"} {
+		_, err = ParseRelayFees([]string{pair})
+		require.Error(t, err, pair)
+	}
+}
+
+func TestMinValueNetOfRelayFee(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	relay := newTestRelayWithMinValue(t, 100)
+	builder.relay = relay
+
+	// The test relays are all named unknown
+	builder.relayFees = map[string]RelayFee{"unknown": {Bps: 1000}}
+
+	req := newTestSubmitBlockRequest(t, nil)
+	require.NoError(t, req.Message.Value.FromBig(big.NewInt(105)))
+	require.ErrorIs(t, builder.submitBlock("submission", "", nil, req, 25), ErrBidBelowMinValue)
+	require.Nil(t, relay.submittedMsg)
+
+	require.NoError(t, req.Message.Value.FromBig(big.NewInt(120)))
+	require.NoError(t, builder.submitBlock("submission", "", nil, req, 25))
+	require.Equal(t, req, relay.submittedMsg)
+
+	require.True(t, builder.anyRelayAcceptsValue(context.Background(), big.NewInt(112)))
+	require.False(t, builder.anyRelayAcceptsValue(context.Background(), big.NewInt(110)))
+}
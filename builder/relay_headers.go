@@ -0,0 +1,139 @@
+package builder
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// RequestSigner signs a relay request, for example by setting an HMAC header over the body.
+// It is called after the extra headers are set, with the request body which is nil for requests without one.
+type RequestSigner func(req *http.Request, body []byte) error
+
+// RemoteRelayOptions are the optional settings of the remote relay, the zero value keeps the defaults.
+type RemoteRelayOptions struct {
+	// Headers are set on every request to the relay
+	Headers map[string]string
+	// RequiredHeaders must be set in Headers, for relays which reject unauthenticated requests
+	RequiredHeaders []string
+	// Signer signs every request to the relay if set
+	Signer RequestSigner
+	// Codec encodes the block submissions, JSON if nil
+	Codec Codec
+	// ProxyURL is the http, https or socks5 proxy all requests to the relay go through, if set
+	ProxyURL string
+	// CancelBids sends the submissions with cancellations, the relay taking the builder's latest submission for a slot
+	// as its bid even if lower
+	CancelBids bool
+	// ClientVersionHeader sends the version of the EL which built the block with the submissions
+	ClientVersionHeader bool
+	// MinValue is the lowest bid value in wei submitted to the relay, raising the minimum published in its constraints
+	MinValue *big.Int
+	// SubmitTimeout bounds each block submission, 6s if zero
+	SubmitTimeout time.Duration
+	// ValidatorsTimeout bounds each fetch of the validators, which are looked up before the builds, 3s if zero
+	ValidatorsTimeout time.Duration
+	// ErrorBodyLimit is the number of bytes of the relay's response body kept in the errors of rejected submissions,
+	// 1024 if zero
+	ErrorBodyLimit int
+	// RateLimitBackoff is how long the submissions are suppressed after a 429 response without a valid Retry-After
+	// header, 1s if zero
+	RateLimitBackoff time.Duration
+	// Retry is the retry policy of the failed submissions, not retried if unset
+	Retry RetryPolicy
+	// DisableHTTP2 submits over HTTP/1.1, for the relays or proxies not supporting HTTP/2. HTTP/2 is otherwise
+	// negotiated with the https relays, multiplexing the submissions over a single connection
+	DisableHTTP2 bool
+	// TLSConfig configures the TLS connections to the relay, for example to trust a private CA. The system
+	// configuration is used if nil
+	TLSConfig *tls.Config
+	// DebugLog logs every request to the relay and its response, for debugging only. The credential headers and the
+	// extra headers are redacted. Not logged if unset
+	DebugLog RelayDebugLog
+}
+
+func (o *RemoteRelayOptions) validate() error {
+	if o.ProxyURL != "" {
+		if _, err := validateProxyURL(o.ProxyURL); err != nil {
+			return err
+		}
+	}
+
+	for name, value := range o.Headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("invalid relay header name %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid value for relay header %s", name)
+		}
+	}
+
+	for _, required := range o.RequiredHeaders {
+		found := false
+		for name, value := range o.Headers {
+			if textproto.CanonicalMIMEHeaderKey(name) == textproto.CanonicalMIMEHeaderKey(required) && value != "" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("required relay header %s not set", required)
+		}
+	}
+	return nil
+}
+
+// ParseRelayHeaders parses "Name=Value" pairs into relay headers.
+func ParseRelayHeaders(pairs []string) (map[string]string, error) {
+	headers := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid relay header %q, expected Name=Value", pair)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// headerTransport sets the extra headers on relay requests and signs them.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+	signer  RequestSigner
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.headers) == 0 && t.signer == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+
+	if t.signer != nil {
+		var body []byte
+		if req.Body != nil && req.Body != http.NoBody {
+			var err error
+			body, err = io.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		if err := t.signer(req, body); err != nil {
+			return nil, fmt.Errorf("could not sign relay request: %w", err)
+		}
+	}
+
+	return t.base.RoundTrip(req)
+}
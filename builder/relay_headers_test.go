@@ -0,0 +1,73 @@
+package builder
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteRelayHeaders(t *testing.T) {
+	secret := []byte("secret")
+	sign := func(body []byte) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	requests := make(chan *http.Request, 1)
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "key", r.Header.Get("X-Api-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(r)
+
+	opts := RemoteRelayOptions{
+		Headers:         map[string]string{"X-Api-Key": "key"},
+		RequiredHeaders: []string{"x-api-key"},
+		Signer: func(req *http.Request, body []byte) error {
+			req.Header.Set("X-Signature", sign(body))
+			return nil
+		},
+	}
+	relay, err := NewRemoteRelayWithOptions(srv.URL, nil, opts)
+	require.NoError(t, err)
+
+	err = relay.SubmitBlock(withSubmissionID(context.Background(), "test-submission"), &boostTypes.BuilderSubmitBlockRequest{})
+	require.NoError(t, err)
+
+	req := <-requests
+	require.Equal(t, "key", req.Header.Get("X-Api-Key"))
+	require.Equal(t, "test-submission", req.Header.Get(SubmissionIDHeader))
+	require.NotEmpty(t, req.Header.Get("X-Signature"))
+
+	// Required headers are checked at construction
+	opts.Headers = nil
+	_, err = NewRemoteRelayWithOptions(srv.URL, nil, opts)
+	require.Error(t, err)
+
+	_, err = NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{Headers: map[string]string{"X Api Key": "key"}})
+	require.Error(t, err)
+}
+
+func TestParseRelayHeaders(t *testing.T) {
+	headers, err := ParseRelayHeaders([]string{"X-Api-Key=key", "X-Builder-Id = builder"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"X-Api-Key": "key", "X-Builder-Id": "builder"}, headers)
+
+	_, err = ParseRelayHeaders([]string{"X-Api-Key"})
+	require.Error(t, err)
+}
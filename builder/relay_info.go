@@ -0,0 +1,104 @@
+package builder
+
+import (
+	"sync"
+	"time"
+)
+
+// RelayInfo describes a relay the builder submits to.
+type RelayInfo struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Enabled is false if the relay was disabled with DisableRelay or violates the latency SLA
+	Enabled bool `json:"enabled"`
+	// CircuitState is the state of the relay's circuit breaker: closed, open or half-open
+	CircuitState string `json:"circuitState"`
+	// LastSubmission is the time of the last submission to the relay, zero if there was none
+	LastSubmission time.Time `json:"lastSubmission"`
+	// LastSuccess is the time of the last submission the relay accepted, zero if there was none
+	LastSuccess time.Time `json:"lastSuccess"`
+	// LastError is the error of the last submission to the relay, empty if the relay accepted it
+	LastError string `json:"lastError,omitempty"`
+	// Latency are the submission latency percentiles, the relay is disabled while they violate the latency SLA
+	Latency LatencyStats `json:"latency"`
+}
+
+// relayStatus is the outcome of the last submissions to a relay.
+type relayStatus struct {
+	lastSubmission time.Time
+	lastSuccess    time.Time
+	lastErr        error
+}
+
+// relayStatuses are the statuses of the relays by member relay, recorded for the submissions sent to them.
+type relayStatuses struct {
+	mu       sync.Mutex
+	statuses map[IRelay]relayStatus
+}
+
+// record records the outcomes of a submission to the relays, in the order of the result. The relays the submission
+// skipped keep their status.
+func (s *relayStatuses) record(relays []IRelay, result SubmissionResult, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.statuses == nil {
+		s.statuses = make(map[IRelay]relayStatus)
+	}
+	for i, outcome := range result {
+		if i >= len(relays) || outcome.Skipped {
+			continue
+		}
+		status := s.statuses[relays[i]]
+		status.lastSubmission, status.lastErr = now, outcome.Err
+		if outcome.Err == nil {
+			status.lastSuccess = now
+		}
+		s.statuses[relays[i]] = status
+	}
+}
+
+func (s *relayStatuses) get(relay IRelay) relayStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.statuses[relay]
+}
+
+// Relays returns the configured relays and their status. The circuit breaker and the latency SLA are the builder's,
+// shared by the relays of a MultiRelay, the submissions' outcomes are each relay's.
+func (b *Builder) Relays() []RelayInfo {
+	relays := b.relays()
+	infos := make([]RelayInfo, 0, len(relays))
+	for _, relay := range relays {
+		name, url := relayIdentity(relay)
+		status := b.statuses.get(relay)
+		info := RelayInfo{
+			Name:           name,
+			URL:            url,
+			Enabled:        b.toggles.enabled(relay) && b.latencySLA.Enabled(),
+			CircuitState:   b.breaker.State().String(),
+			LastSubmission: status.lastSubmission,
+			LastSuccess:    status.lastSuccess,
+			Latency:        b.latencySLA.Stats(),
+		}
+		if status.lastErr != nil {
+			info.LastError = status.lastErr.Error()
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func relayIdentity(relay IRelay) (name string, url string) {
+	switch r := relay.(type) {
+	case *RemoteRelay:
+		return r.host, r.url
+	case *WebSocketRelay:
+		return r.host, r.url
+	case *LocalRelay:
+		return "local", ""
+	default:
+		return "unknown", ""
+	}
+}
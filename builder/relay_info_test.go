@@ -0,0 +1,52 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderRelays(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{CircuitBreakerThreshold: 1, CircuitBreakerCooldown: time.Minute})
+
+	relays := builder.Relays()
+	require.Len(t, relays, 1)
+	require.Equal(t, "closed", relays[0].CircuitState)
+	require.True(t, relays[0].LastSuccess.IsZero())
+
+	executableData := newTestExecutableData()
+	require.NoError(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25))
+	require.False(t, builder.Relays()[0].LastSuccess.IsZero())
+	require.True(t, builder.Relays()[0].Enabled)
+	require.Equal(t, 1, builder.Relays()[0].Latency.Samples)
+
+	testRelay.submitErr = errors.New("relay unavailable")
+	require.Error(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 26))
+	require.Equal(t, "open", builder.Relays()[0].CircuitState)
+}
+
+func TestBuilderRelaysMultiRelay(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	builder.relay = NewMultiRelay([]IRelay{&testRelay{}, &LocalRelay{}}, ValidatorConflictRecent)
+
+	relays := builder.Relays()
+	require.Len(t, relays, 2)
+	require.Equal(t, "unknown", relays[0].Name)
+	require.Equal(t, "local", relays[1].Name)
+
+	// The submissions' outcomes are each relay's
+	accepting, rejecting := &testRelay{}, &testRelay{submitErr: errors.New("rejected")}
+	builder.relay = NewMultiRelay([]IRelay{accepting, rejecting}, ValidatorConflictRecent)
+	require.NoError(t, builder.onSealedBlock(builder.eth, newTestExecutableData(), newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25))
+
+	relays = builder.Relays()
+	require.False(t, relays[0].LastSubmission.IsZero())
+	require.Equal(t, relays[0].LastSubmission, relays[0].LastSuccess)
+	require.Empty(t, relays[0].LastError)
+	require.False(t, relays[1].LastSubmission.IsZero())
+	require.True(t, relays[1].LastSuccess.IsZero())
+	require.Equal(t, "rejected", relays[1].LastError)
+}
@@ -0,0 +1,136 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// builderKey is a builder key pair the submissions are signed with.
+type builderKey struct {
+	sk *bls.SecretKey
+	pk boostTypes.PublicKey
+}
+
+func newBuilderKey(sk *bls.SecretKey) builderKey {
+	var pk boostTypes.PublicKey
+	pk.FromSlice(bls.PublicKeyFromSecretKey(sk).Compress())
+	return builderKey{sk: sk, pk: pk}
+}
+
+// ParseRelaySecretKeys parses "relay=key" pairs into the builder secret keys of the relays, where relay is the relay's
+// name in Relays and key the hex encoded secret key.
+func ParseRelaySecretKeys(pairs []string) (map[string]*bls.SecretKey, error) {
+	keys := make(map[string]*bls.SecretKey, len(pairs))
+	for _, pair := range pairs {
+		relay, key, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(relay) == "" {
+			return nil, fmt.Errorf("invalid relay secret key for %q, expected relay=key", relay)
+		}
+		skBytes, err := hexutil.Decode(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid relay secret key for %q: %w", relay, err)
+		}
+		sk, err := bls.SecretKeyFromBytes(skBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid relay secret key for %q: %w", relay, err)
+		}
+		keys[strings.TrimSpace(relay)] = sk
+	}
+	return keys, nil
+}
+
+// keyFor returns the key the submissions to the relay are signed with, the builder key if the relay has none.
+func (b *Builder) keyFor(relay IRelay) builderKey {
+	name, _ := relayIdentity(relay)
+	if key, found := b.relayKeys[name]; found {
+		return key
+	}
+	return builderKey{sk: b.builderSecretKey, pk: b.builderPublicKey}
+}
+
+// ownsPubkey reports whether the pubkey is the builder's or one of the relays' keys.
+func (b *Builder) ownsPubkey(pubkey boostTypes.PublicKey) bool {
+	if pubkey == b.builderPublicKey {
+		return true
+	}
+	for _, key := range b.relayKeys {
+		if pubkey == key.pk {
+			return true
+		}
+	}
+	return false
+}
+
+// signedFor returns the submission signed with the relay's key, the submission signed with the builder key as is
+// if the relay has no key of its own.
+func (b *Builder) signedFor(relay IRelay, req *boostTypes.BuilderSubmitBlockRequest) (*boostTypes.BuilderSubmitBlockRequest, error) {
+	name, _ := relayIdentity(relay)
+	key, found := b.relayKeys[name]
+	if !found || req.Message.BuilderPubkey == key.pk {
+		return req, nil
+	}
+
+	msg := *req.Message
+	msg.BuilderPubkey = key.pk
+	signature, err := boostTypes.SignMessage(&msg, b.builderSigningDomain, key.sk)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(&msg, b.builderSigningDomain, signature); err != nil {
+		invalidSignatureCounter.Inc(1)
+		return nil, err
+	}
+	return &boostTypes.BuilderSubmitBlockRequest{Signature: signature, Message: &msg, ExecutionPayload: req.ExecutionPayload}, nil
+}
+
+// verifySignature checks the bid trace's signature against its builder pubkey, catching a corrupted key or a signing
+// bug before the relays reject the submission.
+func verifySignature(msg *boostTypes.BidTrace, domain boostTypes.Domain, signature boostTypes.Signature) error {
+	ok, err := boostTypes.VerifySignature(msg, domain, msg.BuilderPubkey[:], signature[:])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// CheckRelayKeysRegistered checks that the relays with their own key know the relays and, for the relays keeping
+// track of the builders, that the key is registered with them. The unknown relays are returned as an error, the
+// relays the key could not be confirmed registered with as warnings: the builder registrations are not part of the
+// relay API, and the registration monitors register the keys.
+func CheckRelayKeysRegistered(ctx context.Context, relays []IRelay, keys map[string]*bls.SecretKey) ([]error, error) {
+	var warnings []error
+	for name, sk := range keys {
+		var relay IRelay
+		for _, r := range relays {
+			if relayName, _ := relayIdentity(r); relayName == name {
+				relay = r
+			}
+		}
+		if relay == nil {
+			return nil, fmt.Errorf("%w %q has a secret key", ErrUnknownRelay, name)
+		}
+
+		registrar, ok := relay.(BuilderRegistrar)
+		if !ok {
+			continue
+		}
+		pk := newBuilderKey(sk).pk
+		registered, err := registrar.IsBuilderRegistered(ctx, pk)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("could not check the builder key registration with relay %s: %w", name, err))
+			continue
+		}
+		if !registered {
+			warnings = append(warnings, fmt.Errorf("builder key %s is not registered with relay %s", pk.String(), name))
+		}
+	}
+	return warnings, nil
+}
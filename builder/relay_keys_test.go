@@ -0,0 +1,98 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+type registeredRelay struct {
+	*testRelay
+	*testRegistrar
+}
+
+func TestParseRelaySecretKeys(t *testing.T) {
+	sk := NewTestSecretKey([]byte("relay key"))
+	keys, err := ParseRelaySecretKeys([]string{"relay.example.com = " + hexutil.Encode(sk.Serialize())})
+	require.NoError(t, err)
+	require.Equal(t, map[string]*bls.SecretKey{"relay.example.com": sk}, keys)
+
+	for _, pair := range []string{"relay.example.com", "=" + hexutil.Encode(sk.Serialize()), "relay.example.com=0x01"} {
+		_, err = ParseRelaySecretKeys([]string{pair})
+		require.Error(t, err, pair)
+	}
+}
+
+func TestOnSealedBlockSignsWithRelayKey(t *testing.T) {
+	relaySk := NewTestSecretKey([]byte("relay key"))
+	relayKey := newBuilderKey(relaySk)
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{RelaySecretKeys: map[string]*bls.SecretKey{"unknown": relaySk}})
+	executableData := newTestExecutableData()
+
+	require.NoError(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25))
+	msg := testRelay.submittedMsg.Message
+	require.Equal(t, relayKey.pk, msg.BuilderPubkey)
+	ok, err := boostTypes.VerifySignature(msg, builder.builderSigningDomain, relayKey.pk[:], testRelay.submittedMsg.Signature[:])
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.True(t, builder.ownsPubkey(relayKey.pk))
+	require.True(t, builder.ownsPubkey(builder.builderPublicKey))
+	require.False(t, builder.ownsPubkey(boostTypes.PublicKey{0x01}))
+
+	// Relays without a key of their own are signed for with the builder key
+	builder.relayKeys = nil
+	require.NoError(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 26))
+	require.Equal(t, builder.builderPublicKey, testRelay.submittedMsg.Message.BuilderPubkey)
+}
+
+func TestOnSealedBlockInvalidSignature(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	executableData := newTestExecutableData()
+
+	// The signature does not verify against a pubkey which is not the secret key's
+	builder.builderPublicKey = newBuilderKey(NewTestSecretKey([]byte("other key"))).pk
+	require.ErrorIs(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25), ErrInvalidSignature)
+	require.Nil(t, testRelay.submittedMsg)
+
+	msg := &boostTypes.BidTrace{Slot: 25, BuilderPubkey: newBuilderKey(builder.builderSecretKey).pk}
+	signature, err := boostTypes.SignMessage(msg, builder.builderSigningDomain, builder.builderSecretKey)
+	require.NoError(t, err)
+	require.NoError(t, verifySignature(msg, builder.builderSigningDomain, signature))
+	require.ErrorIs(t, verifySignature(msg, builder.builderSigningDomain, boostTypes.Signature{}), ErrInvalidSignature)
+}
+
+func TestCheckRelayKeysRegistered(t *testing.T) {
+	keys := map[string]*bls.SecretKey{"unknown": NewTestSecretKey([]byte("relay key"))}
+	registrar := &testRegistrar{registered: true}
+	relays := []IRelay{registeredRelay{&testRelay{}, registrar}}
+	warnings, err := CheckRelayKeysRegistered(context.Background(), relays, keys)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	// Unregistered keys and failed checks are only warnings
+	registrar.registered = false
+	warnings, err = CheckRelayKeysRegistered(context.Background(), relays, keys)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.ErrorContains(t, warnings[0], "is not registered with relay unknown")
+
+	registrar.checkErr = errors.New("relay unavailable")
+	warnings, err = CheckRelayKeysRegistered(context.Background(), relays, keys)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.ErrorIs(t, warnings[0], registrar.checkErr)
+
+	// Relays not keeping track of the builders can't be checked
+	warnings, err = CheckRelayKeysRegistered(context.Background(), []IRelay{&testRelay{}}, keys)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	_, err = CheckRelayKeysRegistered(context.Background(), []IRelay{&LocalRelay{}}, keys)
+	require.ErrorIs(t, err, ErrUnknownRelay)
+}
@@ -0,0 +1,54 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrBidBelowMinValue is returned when the bid is below the minimum value of all relays it would be submitted to
+var ErrBidBelowMinValue = errors.New("bid below the relay minimum value")
+
+// ParseRelayMinValues parses "host=wei" pairs into the minimum bid values of the relays by host.
+func ParseRelayMinValues(pairs []string) (map[string]*big.Int, error) {
+	minValues := make(map[string]*big.Int, len(pairs))
+	for _, pair := range pairs {
+		host, value, found := strings.Cut(pair, "=")
+		minValue, ok := new(big.Int).SetString(strings.TrimSpace(value), 10)
+		if !found || host == "" || !ok || minValue.Sign() < 0 {
+			return nil, fmt.Errorf("invalid relay minimum value %q, expected host=wei", pair)
+		}
+		minValues[strings.TrimSpace(host)] = minValue
+	}
+	return minValues, nil
+}
+
+// relayMinValue returns the lowest bid value the relay accepts, zero if it has no minimum or its constraints
+// are not available.
+func relayMinValue(ctx context.Context, relay IRelay) *big.Int {
+	ctx, cancel := context.WithTimeout(ctx, relayConstraintsTimeout)
+	defer cancel()
+
+	constraints, err := relay.GetConstraints(ctx)
+	if err != nil {
+		return new(big.Int)
+	}
+	return constraints.MinValue.BigInt()
+}
+
+// checkMinValue returns ErrBidBelowMinValue if the value, net of the relay's fee, is below the relay's minimum.
+func checkMinValue(ctx context.Context, relay IRelay, value *big.Int) error {
+	minValue := relayMinValue(ctx, relay)
+	if value.Cmp(minValue) >= 0 {
+		return nil
+	}
+
+	name, _ := relayIdentity(relay)
+	submissionID, _ := SubmissionIDFromContext(ctx)
+	log.Debug("skipping relay, bid below its minimum value", "submission_id", submissionID, "relay", name, "value", value, "min_value", minValue)
+	return fmt.Errorf("%w: %s is below %s", ErrBidBelowMinValue, value, minValue)
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"math/big"
+	"testing"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRelayWithMinValue(t *testing.T, minValue int64) *testRelay {
+	relay := &testRelay{}
+	require.NoError(t, relay.constraints.MinValue.FromBig(big.NewInt(minValue)))
+	return relay
+}
+
+func TestParseRelayMinValues(t *testing.T) {
+	minValues, err := ParseRelayMinValues([]string{"relay.example.com=100", " other.example.com = 2000000000 "})
+	require.NoError(t, err)
+	require.Equal(t, map[string]*big.Int{"relay.example.com": big.NewInt(100), "other.example.com": big.NewInt(2_000_000_000)}, minValues)
+
+	for _, pair := range []string{"relay.example.com", "=100", "relay.example.com=1e9", "relay.example.com=-1"} {
+		_, err = ParseRelayMinValues([]string{pair})
+		require.Error(t, err, pair)
+	}
+}
+
+func TestSubmitSkipsRelaysBelowMinValue(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	lowRelay := newTestRelayWithMinValue(t, 10)
+	highRelay := newTestRelayWithMinValue(t, 100)
+	builder.relay = NewMultiRelay([]IRelay{lowRelay, highRelay}, ValidatorConflictRecent)
+
+	req := newTestSubmitBlockRequest(t, nil)
+	require.NoError(t, req.Message.Value.FromBig(big.NewInt(50)))
+	require.NoError(t, builder.submitBlock("submission", "", nil, req, 25))
+	require.Equal(t, req, lowRelay.submittedMsg)
+	require.Nil(t, highRelay.submittedMsg)
+
+	// Bids below all minimums don't trip the circuit breaker
+	require.NoError(t, req.Message.Value.FromBig(big.NewInt(5)))
+	require.ErrorIs(t, builder.submitBlock("submission", "", nil, req, 25), ErrBidBelowMinValue)
+	require.Equal(t, "closed", builder.Relays()[0].CircuitState)
+}
+
+func TestOnSealedBlockBelowMinValue(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	relay := newTestRelayWithMinValue(t, 100)
+	builder.relay = relay
+	executableData := newTestExecutableData()
+
+	err := builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25)
+	require.ErrorIs(t, err, ErrBidBelowMinValue)
+	require.Nil(t, relay.submittedMsg)
+
+	require.NoError(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(100), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25))
+	require.NotNil(t, relay.submittedMsg)
+}
@@ -0,0 +1,43 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+var defaultProxyPorts = map[string]string{"http": "80", "https": "443", "socks5": "1080"}
+
+// validateProxyURL accepts HTTP(S) proxies, tunnelling https relays with CONNECT, and SOCKS5 proxies.
+func validateProxyURL(proxy string) (*url.URL, error) {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid relay proxy URL %q: %w", proxy, err)
+	}
+	if _, ok := defaultProxyPorts[proxyURL.Scheme]; !ok {
+		return nil, fmt.Errorf("invalid relay proxy URL %s: scheme must be http, https or socks5", proxyURL.Redacted())
+	}
+	if proxyURL.Hostname() == "" {
+		return nil, fmt.Errorf("invalid relay proxy URL %s: missing host", proxyURL.Redacted())
+	}
+	return proxyURL, nil
+}
+
+// CheckProxy returns an error if the relay's proxy does not accept connections, nil without a proxy.
+func (r *RemoteRelay) CheckProxy(ctx context.Context) error {
+	if r.proxyURL == nil {
+		return nil
+	}
+
+	port := r.proxyURL.Port()
+	if port == "" {
+		port = defaultProxyPorts[r.proxyURL.Scheme]
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(r.proxyURL.Hostname(), port))
+	if err != nil {
+		return r.relayError(err)
+	}
+	return conn.Close()
+}
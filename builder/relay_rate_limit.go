@@ -0,0 +1,72 @@
+package builder
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRelayRateLimited is returned for the submissions suppressed while backing off from a relay's 429 response
+var ErrRelayRateLimited = errors.New("relay rate limited")
+
+const defaultRateLimitBackoff = time.Second
+
+// rateLimiter suppresses the submissions to a relay until the backoff of its last 429 response expires.
+type rateLimiter struct {
+	// defaultBackoff applies to the 429 responses without a valid Retry-After header
+	defaultBackoff time.Duration
+
+	mu    sync.Mutex
+	until time.Time
+}
+
+// backOff suppresses the submissions for the Retry-After header's duration, or the default backoff if the header is
+// missing or invalid, and returns the backoff.
+func (l *rateLimiter) backOff(retryAfter string, now time.Time) time.Duration {
+	backoff, ok := parseRetryAfter(retryAfter, now)
+	if !ok {
+		backoff = l.defaultBackoff
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A shorter backoff of a concurrent submission does not lift a longer one
+	if until := now.Add(backoff); until.After(l.until) {
+		l.until = until
+	}
+	return backoff
+}
+
+// limited returns the remaining backoff, and whether the submissions are suppressed.
+func (l *rateLimiter) limited(now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !now.Before(l.until) {
+		return 0, false
+	}
+	return l.until.Sub(now), true
+}
+
+// parseRetryAfter parses a Retry-After header value, either delay seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
@@ -0,0 +1,88 @@
+package builder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	backoff, ok := parseRetryAfter("3", now)
+	require.True(t, ok)
+	require.Equal(t, 3*time.Second, backoff)
+
+	backoff, ok = parseRetryAfter("Thu, 01 Sep 2022 12:00:10 GMT", now)
+	require.True(t, ok)
+	require.Equal(t, 10*time.Second, backoff)
+
+	// Dates in the past don't back off
+	backoff, ok = parseRetryAfter("Thu, 01 Sep 2022 11:00:00 GMT", now)
+	require.True(t, ok)
+	require.Equal(t, time.Duration(0), backoff)
+
+	for _, value := range []string{"", "-1", "soon"} {
+		_, ok = parseRetryAfter(value, now)
+		require.False(t, ok, value)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := rateLimiter{defaultBackoff: time.Second}
+	_, limited := limiter.limited(now)
+	require.False(t, limited)
+
+	require.Equal(t, time.Second, limiter.backOff("", now))
+	remaining, limited := limiter.limited(now.Add(400 * time.Millisecond))
+	require.True(t, limited)
+	require.Equal(t, 600*time.Millisecond, remaining)
+
+	// A shorter backoff does not lift the current one
+	limiter.backOff("0", now)
+	_, limited = limiter.limited(now.Add(400 * time.Millisecond))
+	require.True(t, limited)
+
+	_, limited = limiter.limited(now.Add(time.Second))
+	require.False(t, limited)
+}
+
+func TestRemoteRelayRateLimited(t *testing.T) {
+	var submissions int32
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&submissions, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	relay, err := NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{RateLimitBackoff: 200 * time.Millisecond})
+	require.NoError(t, err)
+	req := newTestSubmitBlockRequest(t, []hexutil.Bytes{{0x01, 0x02}})
+
+	require.Error(t, relay.SubmitBlock(context.Background(), req))
+
+	// The submissions are suppressed without reaching the relay until the backoff expires
+	require.ErrorIs(t, relay.SubmitBlock(context.Background(), req), ErrRelayRateLimited)
+	require.Equal(t, int32(1), atomic.LoadInt32(&submissions))
+
+	time.Sleep(250 * time.Millisecond)
+	require.NoError(t, relay.SubmitBlock(context.Background(), req))
+	require.Equal(t, int32(2), atomic.LoadInt32(&submissions))
+}
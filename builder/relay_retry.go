@@ -0,0 +1,92 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy is how the failed submissions to a relay are retried. Only the submissions failing to reach the relay
+// or failing with a 5xx response are retried, the rejected ones are not.
+type RetryPolicy struct {
+	// Attempts is the number of times a submission is sent, once if zero
+	Attempts int
+	// Backoff is the wait before the first retry, doubled before every later retry
+	Backoff time.Duration
+}
+
+// ParseRetryPolicy parses an "attempts:backoff" retry policy, e.g. "3:50ms". The backoff may be omitted.
+func ParseRetryPolicy(policy string) (RetryPolicy, error) {
+	attemptsStr, backoffStr, hasBackoff := strings.Cut(strings.TrimSpace(policy), ":")
+	attempts, err := strconv.Atoi(attemptsStr)
+	if err != nil || attempts < 1 {
+		return RetryPolicy{}, fmt.Errorf("invalid retry policy %q, expected at least one attempt", policy)
+	}
+	retry := RetryPolicy{Attempts: attempts}
+	if hasBackoff {
+		backoff, err := time.ParseDuration(backoffStr)
+		if err != nil || backoff < 0 {
+			return RetryPolicy{}, fmt.Errorf("invalid retry policy %q, expected a backoff duration", policy)
+		}
+		retry.Backoff = backoff
+	}
+	return retry, nil
+}
+
+// ParseRelayRetryPolicies parses "host=attempts:backoff" pairs into the retry policies of the relays by host.
+func ParseRelayRetryPolicies(pairs []string) (map[string]RetryPolicy, error) {
+	policies := make(map[string]RetryPolicy, len(pairs))
+	for _, pair := range pairs {
+		host, policy, found := strings.Cut(pair, "=")
+		host = strings.TrimSpace(host)
+		if !found || host == "" {
+			return nil, fmt.Errorf("invalid relay retry policy %q, expected host=attempts:backoff", pair)
+		}
+		retry, err := ParseRetryPolicy(policy)
+		if err != nil {
+			return nil, fmt.Errorf("relay %s: %w", host, err)
+		}
+		policies[host] = retry
+	}
+	return policies, nil
+}
+
+// retryable reports whether the submission failed with the code and error is worth retrying: the relay was not
+// reached or failed with a 5xx response. The rejections and the rate limited submissions are not retried.
+func retryable(ctx context.Context, code int, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var respErr *RelayResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode >= http.StatusInternalServerError
+	}
+	var urlErr *url.Error
+	return code == 0 && errors.As(err, &urlErr)
+}
+
+// wait waits the backoff before the retry, the first retry being 1, returning false without waiting if the
+// backoff would end past the context's deadline.
+func (p RetryPolicy) wait(ctx context.Context, retry int) bool {
+	backoff := p.Backoff << (retry - 1)
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(backoff).After(deadline) {
+		return false
+	}
+	if backoff <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,98 @@
+package builder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRelayRetryPolicies(t *testing.T) {
+	retry, err := ParseRetryPolicy("3:50ms")
+	require.NoError(t, err)
+	require.Equal(t, RetryPolicy{Attempts: 3, Backoff: 50 * time.Millisecond}, retry)
+
+	retry, err = ParseRetryPolicy("2")
+	require.NoError(t, err)
+	require.Equal(t, RetryPolicy{Attempts: 2}, retry)
+
+	for _, policy := range []string{"", "0:1s", "three", "3:soon", "3:-1s"} {
+		_, err = ParseRetryPolicy(policy)
+		require.Error(t, err, policy)
+	}
+
+	policies, err := ParseRelayRetryPolicies([]string{"relay.example.com=5:10ms", " flaky.example.com = 1"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]RetryPolicy{
+		"relay.example.com": {Attempts: 5, Backoff: 10 * time.Millisecond},
+		"flaky.example.com": {Attempts: 1},
+	}, policies)
+
+	_, err = ParseRelayRetryPolicies([]string{"relay.example.com"})
+	require.Error(t, err)
+	_, err = ParseRelayRetryPolicies([]string{"relay.example.com=0"})
+	require.Error(t, err)
+}
+
+// newFailingRelayServer returns a relay failing the first submissions with the status, and the number of
+// submissions it received.
+func newFailingRelayServer(t *testing.T, failures int32, status int) (*httptest.Server, *int32) {
+	var submissions int32
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&submissions, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv, &submissions
+}
+
+func TestRemoteRelayRetryPolicies(t *testing.T) {
+	req := newTestSubmitBlockRequest(t, []hexutil.Bytes{{0x01, 0x02}})
+
+	// The reliable relay is retried through its failures, doubling the backoff
+	srv, submissions := newFailingRelayServer(t, 2, http.StatusServiceUnavailable)
+	reliable, err := NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{Retry: RetryPolicy{Attempts: 3, Backoff: 20 * time.Millisecond}})
+	require.NoError(t, err)
+	start := time.Now()
+	require.NoError(t, reliable.SubmitBlock(context.Background(), req))
+	require.Equal(t, int32(3), atomic.LoadInt32(submissions))
+	require.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+
+	// The flaky relay fails fast with the default policy
+	srv, submissions = newFailingRelayServer(t, 2, http.StatusServiceUnavailable)
+	flaky, err := NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{})
+	require.NoError(t, err)
+	require.Error(t, flaky.SubmitBlock(context.Background(), req))
+	require.Equal(t, int32(1), atomic.LoadInt32(submissions))
+
+	// The rejections are not retried
+	srv, submissions = newFailingRelayServer(t, 1, http.StatusBadRequest)
+	rejecting, err := NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{Retry: RetryPolicy{Attempts: 3}})
+	require.NoError(t, err)
+	require.Error(t, rejecting.SubmitBlock(context.Background(), req))
+	require.Equal(t, int32(1), atomic.LoadInt32(submissions))
+
+	// The retries stop at the submit timeout rather than wait past it
+	srv, submissions = newFailingRelayServer(t, 2, http.StatusServiceUnavailable)
+	slow, err := NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{SubmitTimeout: 100 * time.Millisecond, Retry: RetryPolicy{Attempts: 3, Backoff: time.Second}})
+	require.NoError(t, err)
+	start = time.Now()
+	require.Error(t, slow.SubmitBlock(context.Background(), req))
+	require.Equal(t, int32(1), atomic.LoadInt32(submissions))
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+}
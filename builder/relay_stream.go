@@ -0,0 +1,326 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/gorilla/websocket"
+)
+
+var (
+	// ErrStreamDisconnected is returned for the submissions in flight when the stream connection is lost
+	ErrStreamDisconnected = errors.New("relay stream disconnected")
+	// ErrStreamClosed is returned for the submissions made after the relay is closed
+	ErrStreamClosed = errors.New("relay stream closed")
+)
+
+const (
+	defaultStreamMaxInFlight = 16
+	streamWriteTimeout       = 5 * time.Second
+	streamMinReconnectDelay  = time.Second
+	streamMaxReconnectDelay  = 30 * time.Second
+)
+
+// WebSocketRelayOptions are the optional settings of the submission stream
+type WebSocketRelayOptions struct {
+	// Headers are set on the stream's handshake
+	Headers map[string]string
+	// ClientVersion is set as the EL version header on the stream's handshake, for the relays with the
+	// ClientVersionHeader option. Not set if empty
+	ClientVersion string
+	// MaxInFlight is the number of submissions sent without an ack yet, further submissions wait for an ack or
+	// their context. 16 if zero
+	MaxInFlight int
+}
+
+// streamAck is the relay's response to a submission, in the relays' error format of the builder API. The submission
+// was accepted if Code is below 300.
+type streamAck struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// WebSocketRelay submits the blocks over a persistent WebSocket connection to the relay, saving the handshake of
+// every submission. Each submission is a message with the body of the HTTP submission, encoded with the relay's codec:
+// a text message for JSON, a binary message for SSZ. The relay acks the submissions in the order they were sent, each
+// with a streamAck. Everything else, and the submissions while the stream is down, goes through the HTTP API of the
+// remote relay.
+type WebSocketRelay struct {
+	*RemoteRelay
+
+	streamURL string
+	// streamRedacted is the stream URL with any credentials redacted, used in logs and errors
+	streamRedacted string
+	dialer         websocket.Dialer
+	header         http.Header
+	inFlight       chan struct{}
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+	// pending are the submissions waiting for their ack in the order they were sent, receiving nil if accepted.
+	// The submissions no longer waiting keep their place, as the relay acks them all
+	pending []chan error
+
+	// writeLock serializes writes and their pending acks, the connection supports a single writer
+	writeLock sync.Mutex
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewWebSocketRelay streams the submissions to the remote relay over the ws(s) stream URL. The stream is connected in
+// the background and reconnected when lost.
+func NewWebSocketRelay(remoteRelay *RemoteRelay, streamURL string, opts WebSocketRelayOptions) (*WebSocketRelay, error) {
+	parsedURL, err := url.Parse(streamURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid relay stream URL %q: %w", streamURL, err)
+	}
+	if parsedURL.Scheme != "ws" && parsedURL.Scheme != "wss" {
+		return nil, fmt.Errorf("invalid relay stream URL %s: scheme must be ws or wss", parsedURL.Redacted())
+	}
+
+	header := make(http.Header, len(opts.Headers))
+	for name, value := range opts.Headers {
+		header.Set(name, value)
+	}
+	if opts.ClientVersion != "" {
+		header.Set(ClientVersionHeader, opts.ClientVersion)
+	}
+
+	maxInFlight := opts.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = defaultStreamMaxInFlight
+	}
+
+	dialer := websocket.Dialer{HandshakeTimeout: streamWriteTimeout}
+	if remoteRelay.proxyURL != nil {
+		dialer.Proxy = http.ProxyURL(remoteRelay.proxyURL)
+	}
+	if remoteRelay.tlsConfig != nil {
+		dialer.TLSClientConfig = remoteRelay.tlsConfig.Clone()
+	}
+
+	r := &WebSocketRelay{
+		RemoteRelay:    remoteRelay,
+		streamURL:      streamURL,
+		streamRedacted: parsedURL.Redacted(),
+		dialer:         dialer,
+		header:         header,
+		inFlight:       make(chan struct{}, maxInFlight),
+		closed:         make(chan struct{}),
+	}
+	go r.run()
+	return r, nil
+}
+
+// ParseRelayStreamURLs parses "host=url" pairs into the submission stream URLs of the relays by host.
+func ParseRelayStreamURLs(pairs []string) (map[string]string, error) {
+	streamURLs := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		host, streamURL, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(host) == "" || strings.TrimSpace(streamURL) == "" {
+			return nil, fmt.Errorf("invalid relay stream %q, expected host=url", pair)
+		}
+		streamURLs[strings.TrimSpace(host)] = strings.TrimSpace(streamURL)
+	}
+	return streamURLs, nil
+}
+
+// Close stops the stream, later submissions fail with ErrStreamClosed.
+func (r *WebSocketRelay) Close() {
+	r.closeOnce.Do(func() {
+		close(r.closed)
+		r.mu.Lock()
+		conn := r.conn
+		r.mu.Unlock()
+		if conn != nil {
+			conn.Close()
+		}
+	})
+}
+
+// run keeps the stream connected until the relay is closed, backing off between failed connection attempts.
+func (r *WebSocketRelay) run() {
+	delay := streamMinReconnectDelay
+	for {
+		conn, _, err := r.dialer.Dial(r.streamURL, r.header)
+		if err != nil {
+			log.Warn("could not connect to relay stream, submitting over http", "err", err, "relay", r.streamRedacted, "retry_in", delay)
+			select {
+			case <-r.closed:
+				return
+			case <-time.After(delay):
+			}
+			delay *= 2
+			if delay > streamMaxReconnectDelay {
+				delay = streamMaxReconnectDelay
+			}
+			continue
+		}
+
+		r.mu.Lock()
+		select {
+		case <-r.closed:
+			r.mu.Unlock()
+			conn.Close()
+			return
+		default:
+		}
+		r.conn = conn
+		r.mu.Unlock()
+
+		log.Info("connected to relay stream", "relay", r.streamRedacted)
+		delay = streamMinReconnectDelay
+		err = r.readAcks(conn)
+
+		r.disconnect(conn)
+		select {
+		case <-r.closed:
+			return
+		default:
+			log.Warn("relay stream disconnected, reconnecting", "err", err, "relay", r.streamRedacted)
+		}
+	}
+}
+
+// readAcks hands the acks to the waiting submissions until the connection fails.
+func (r *WebSocketRelay) readAcks(conn *websocket.Conn) error {
+	for {
+		var ack streamAck
+		if err := conn.ReadJSON(&ack); err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		if len(r.pending) == 0 {
+			r.mu.Unlock()
+			return fmt.Errorf("relay stream %s acked more submissions than sent", r.streamRedacted)
+		}
+		acked := r.pending[0]
+		r.pending = r.pending[1:]
+		r.mu.Unlock()
+
+		if ack.Code > 299 {
+			if ack.Code == http.StatusUnsupportedMediaType {
+				log.Warn("relay stream does not support the submission encoding, falling back to json", "relay", r.streamRedacted)
+				r.setCodec(JSONCodec{})
+			}
+			acked <- &RelayResponseError{StatusCode: ack.Code, Body: ack.Message}
+			continue
+		}
+		acked <- nil
+	}
+}
+
+// disconnect drops the connection, failing the submissions still waiting for their ack.
+func (r *WebSocketRelay) disconnect(conn *websocket.Conn) {
+	conn.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == conn {
+		r.conn = nil
+	}
+	for _, acked := range r.pending {
+		acked <- fmt.Errorf("relay stream %s: %w", r.streamRedacted, ErrStreamDisconnected)
+	}
+	r.pending = nil
+}
+
+// SubmitBlock sends the submission over the stream and waits for the relay's ack, within the relay's submit
+// timeout. Submissions are sent over http while the stream is not connected.
+func (r *WebSocketRelay) SubmitBlock(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) error {
+	select {
+	case <-r.closed:
+		return ErrStreamClosed
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.submitTimeout)
+	defer cancel()
+
+	select {
+	case r.inFlight <- struct{}{}:
+		defer func() { <-r.inFlight }()
+	case <-ctx.Done():
+		return fmt.Errorf("relay stream %s: %w", r.streamRedacted, ctx.Err())
+	}
+
+	submissionID, _ := SubmissionIDFromContext(ctx)
+	clientVersion, _ := ClientVersionFromContext(ctx)
+
+	body, contentType, err := r.submissionCodec().Encode(msg)
+	if err != nil {
+		return fmt.Errorf("could not encode submission: %w", err)
+	}
+	messageType := websocket.BinaryMessage
+	if contentType == "application/json" {
+		messageType = websocket.TextMessage
+	}
+
+	// The connection's writes are serialized with the pending acks, which follow the order of the submissions
+	r.writeLock.Lock()
+	r.mu.Lock()
+	conn := r.conn
+	if conn == nil {
+		r.mu.Unlock()
+		r.writeLock.Unlock()
+		log.Debug("relay stream not connected, submitting over http", "submission_id", submissionID, "relay", r.streamRedacted)
+		return r.RemoteRelay.SubmitBlock(ctx, msg)
+	}
+	acked := make(chan error, 1)
+	r.pending = append(r.pending, acked)
+	r.mu.Unlock()
+	err = r.write(ctx, conn, messageType, body)
+	r.writeLock.Unlock()
+	if err != nil {
+		// The read loop reconnects once the connection is closed, failing the pending submissions
+		conn.Close()
+		return fmt.Errorf("relay stream %s: %w", r.streamRedacted, err)
+	}
+
+	select {
+	case err := <-acked:
+		if err != nil {
+			return fmt.Errorf("relay stream %s rejected the submission: %w", r.streamRedacted, err)
+		}
+	case <-ctx.Done():
+		return fmt.Errorf("relay stream %s: %w", r.streamRedacted, ctx.Err())
+	}
+
+	log.Info("submitted block", "submission_id", submissionID, "el_version", clientVersion, "relay", r.streamRedacted, "submission", msg)
+
+	if r.localRelay != nil && !isCanary(ctx) {
+		r.localRelay.SubmitBlock(ctx, msg)
+	}
+	return nil
+}
+
+// write sends the message, the caller holds the write lock.
+func (r *WebSocketRelay) write(ctx context.Context, conn *websocket.Conn, messageType int, data []byte) error {
+	deadline := time.Now().Add(streamWriteTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	return conn.WriteMessage(messageType, data)
+}
+
+// connected reports whether the stream is connected.
+func (r *WebSocketRelay) connected() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.conn != nil
+}
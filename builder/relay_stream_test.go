@@ -0,0 +1,211 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// streamMessage is a submission received over the stream, decoded by its message type.
+type streamMessage struct {
+	messageType int
+	submission  *boostTypes.BuilderSubmitBlockRequest
+}
+
+// testStreamRelay acks the streamed submissions in order with the acks sent to it.
+type testStreamRelay struct {
+	upgrader    websocket.Upgrader
+	submissions chan streamMessage
+	acks        chan streamAck
+	headers     chan http.Header
+	// conns receives the server side of the connections
+	conns chan *websocket.Conn
+}
+
+func (s *testStreamRelay) handle(w http.ResponseWriter, r *http.Request) {
+	s.headers <- r.Header
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	s.conns <- conn
+	defer conn.Close()
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var codec Codec = JSONCodec{}
+		if messageType == websocket.BinaryMessage {
+			codec = SSZCodec{}
+		}
+		submission, err := codec.Decode(data)
+		if err != nil {
+			return
+		}
+		s.submissions <- streamMessage{messageType: messageType, submission: submission}
+		if err := conn.WriteJSON(<-s.acks); err != nil {
+			return
+		}
+	}
+}
+
+func newTestWebSocketRelay(t *testing.T, relayOpts RemoteRelayOptions, opts WebSocketRelayOptions) (*WebSocketRelay, *testStreamRelay, chan struct{}) {
+	streamRelay := &testStreamRelay{
+		submissions: make(chan streamMessage, 10),
+		acks:        make(chan streamAck, 10),
+		headers:     make(chan http.Header, 10),
+		conns:       make(chan *websocket.Conn, 10),
+	}
+
+	httpSubmissions := make(chan struct{}, 10)
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		httpSubmissions <- struct{}{}
+		w.WriteHeader(http.StatusOK)
+	})
+	r.HandleFunc("/relay/v1/builder/blocks/stream", streamRelay.handle)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	remoteRelay, err := NewRemoteRelayWithOptions(srv.URL, nil, relayOpts)
+	require.NoError(t, err)
+	relay, err := NewWebSocketRelay(remoteRelay, "ws"+strings.TrimPrefix(srv.URL, "http")+"/relay/v1/builder/blocks/stream", opts)
+	require.NoError(t, err)
+	t.Cleanup(relay.Close)
+	return relay, streamRelay, httpSubmissions
+}
+
+func newTestStreamSubmission(t *testing.T, slot uint64) *boostTypes.BuilderSubmitBlockRequest {
+	msg := newTestSubmitBlockRequest(t, nil)
+	msg.Message.Slot = slot
+	return msg
+}
+
+func TestWebSocketRelaySubmitBlock(t *testing.T) {
+	relay, streamRelay, httpSubmissions := newTestWebSocketRelay(t, RemoteRelayOptions{}, WebSocketRelayOptions{Headers: map[string]string{"X-Api-Key": "secret"}})
+	headers := <-streamRelay.headers
+	require.Equal(t, "secret", headers.Get("X-Api-Key"))
+	require.Empty(t, headers.Get(ClientVersionHeader))
+	require.Eventually(t, relay.connected, time.Second, 10*time.Millisecond)
+
+	// The submissions are the relay codec's encoding, acked in order
+	streamRelay.acks <- streamAck{Code: http.StatusBadRequest, Message: "invalid slot"}
+	streamRelay.acks <- streamAck{Code: http.StatusOK}
+	err := relay.SubmitBlock(context.Background(), newTestStreamSubmission(t, 0))
+	var respErr *RelayResponseError
+	require.True(t, errors.As(err, &respErr))
+	require.Equal(t, http.StatusBadRequest, respErr.StatusCode)
+	require.Equal(t, "invalid slot", respErr.Body)
+
+	ctx := withClientVersion(withSubmissionID(context.Background(), "test-submission"), "Geth/v1.10.23-stable")
+	require.NoError(t, relay.SubmitBlock(ctx, newTestStreamSubmission(t, 25)))
+
+	for _, slot := range []uint64{0, 25} {
+		message := <-streamRelay.submissions
+		require.Equal(t, websocket.TextMessage, message.messageType)
+		require.Equal(t, slot, message.submission.Message.Slot)
+	}
+	require.Len(t, httpSubmissions, 0)
+}
+
+func TestWebSocketRelayCodec(t *testing.T) {
+	relay, streamRelay, _ := newTestWebSocketRelay(t, RemoteRelayOptions{Codec: SSZCodec{}}, WebSocketRelayOptions{ClientVersion: "Geth/v1.10.23-stable"})
+	require.Equal(t, "Geth/v1.10.23-stable", (<-streamRelay.headers).Get(ClientVersionHeader))
+	require.Eventually(t, relay.connected, time.Second, 10*time.Millisecond)
+
+	streamRelay.acks <- streamAck{Code: http.StatusOK}
+	require.NoError(t, relay.SubmitBlock(context.Background(), newTestStreamSubmission(t, 25)))
+	message := <-streamRelay.submissions
+	require.Equal(t, websocket.BinaryMessage, message.messageType)
+	require.Equal(t, uint64(25), message.submission.Message.Slot)
+	require.Equal(t, newTestStreamSubmission(t, 25).ExecutionPayload.BlockHash, message.submission.ExecutionPayload.BlockHash)
+
+	// Relays not supporting the codec are switched to JSON
+	streamRelay.acks <- streamAck{Code: http.StatusUnsupportedMediaType}
+	require.Error(t, relay.SubmitBlock(context.Background(), newTestStreamSubmission(t, 26)))
+	<-streamRelay.submissions
+	streamRelay.acks <- streamAck{Code: http.StatusOK}
+	require.NoError(t, relay.SubmitBlock(context.Background(), newTestStreamSubmission(t, 27)))
+	require.Equal(t, websocket.TextMessage, (<-streamRelay.submissions).messageType)
+}
+
+func TestWebSocketRelayReconnect(t *testing.T) {
+	relay, streamRelay, httpSubmissions := newTestWebSocketRelay(t, RemoteRelayOptions{}, WebSocketRelayOptions{})
+	conn := <-streamRelay.conns
+	require.Eventually(t, relay.connected, time.Second, 10*time.Millisecond)
+
+	// The submissions waiting for their ack fail with the connection
+	errs := make(chan error, 1)
+	go func() {
+		errs <- relay.SubmitBlock(context.Background(), newTestStreamSubmission(t, 25))
+	}()
+	<-streamRelay.submissions
+	conn.Close()
+	require.ErrorIs(t, <-errs, ErrStreamDisconnected)
+
+	// The stream is reconnected
+	<-streamRelay.conns
+	require.Eventually(t, relay.connected, 2*time.Second, 10*time.Millisecond)
+
+	// Submissions fail once the builder is stopped
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	builder.relay = relay
+	builder.Stop()
+	require.Eventually(t, func() bool { return !relay.connected() }, time.Second, 10*time.Millisecond)
+	require.ErrorIs(t, relay.SubmitBlock(context.Background(), newTestStreamSubmission(t, 25)), ErrStreamClosed)
+	require.Len(t, httpSubmissions, 0)
+}
+
+func TestWebSocketRelayHTTPFallback(t *testing.T) {
+	relay, _, httpSubmissions := newTestWebSocketRelay(t, RemoteRelayOptions{}, WebSocketRelayOptions{})
+
+	// The relay does not serve the stream
+	relay, err := NewWebSocketRelay(relay.RemoteRelay, strings.Replace(relay.streamURL, "/stream", "/unknown", 1), WebSocketRelayOptions{})
+	require.NoError(t, err)
+	defer relay.Close()
+
+	require.NoError(t, relay.SubmitBlock(context.Background(), newTestStreamSubmission(t, 25)))
+	require.Len(t, httpSubmissions, 1)
+	require.False(t, relay.connected())
+}
+
+func TestWebSocketRelayBackpressure(t *testing.T) {
+	relay, streamRelay, _ := newTestWebSocketRelay(t, RemoteRelayOptions{}, WebSocketRelayOptions{MaxInFlight: 1})
+	require.Eventually(t, relay.connected, time.Second, 10*time.Millisecond)
+
+	// The first submission is not acked, the second one cannot be sent while the first is in flight
+	go relay.SubmitBlock(context.Background(), newTestStreamSubmission(t, 25))
+	<-streamRelay.submissions
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, relay.SubmitBlock(ctx, newTestStreamSubmission(t, 26)), context.DeadlineExceeded)
+	require.Len(t, streamRelay.submissions, 0)
+}
+
+func TestNewWebSocketRelay(t *testing.T) {
+	_, err := NewWebSocketRelay(&RemoteRelay{}, "https://relay.example.com/stream", WebSocketRelayOptions{})
+	require.ErrorContains(t, err, "scheme must be ws or wss")
+
+	streamURLs, err := ParseRelayStreamURLs([]string{"relay.example.com=wss://relay.example.com/stream"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"relay.example.com": "wss://relay.example.com/stream"}, streamURLs)
+
+	_, err = ParseRelayStreamURLs([]string{"wss://relay.example.com/stream"})
+	require.Error(t, err)
+}
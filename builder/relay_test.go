@@ -1,11 +1,17 @@
 package builder
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	boostTypes "github.com/flashbots/go-boost-utils/types"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
@@ -45,7 +51,8 @@ func TestRemoteRelay(t *testing.T) {
 	}
 
 	srv := httptest.NewServer(r)
-	relay := NewRemoteRelay(srv.URL, nil)
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
 	vd, found := relay.validatorSlotMap[123]
 	require.True(t, found)
 	expectedValidator_123 := ValidatorData{
@@ -56,7 +63,7 @@ func TestRemoteRelay(t *testing.T) {
 	}
 	require.Equal(t, expectedValidator_123, vd)
 
-	vd, err := relay.GetValidatorForSlot(123)
+	vd, err = relay.GetValidatorForSlot(123)
 	require.NoError(t, err)
 	require.Equal(t, expectedValidator_123, vd)
 
@@ -125,3 +132,383 @@ func TestRemoteRelay(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedValidator_156, vd)
 }
+
+func TestRemoteRelayConstraints(t *testing.T) {
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+
+	constraintsRequests := 0
+	r.HandleFunc("/relay/v1/builder/constraints", func(w http.ResponseWriter, r *http.Request) {
+		constraintsRequests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"min_gas_limit": "29000000", "max_gas_limit": "31000000"}`))
+	})
+
+	srv := httptest.NewServer(r)
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+
+	constraints, err := relay.GetConstraints(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, RelayConstraints{MinGasLimit: 29_000_000, MaxGasLimit: 31_000_000}, constraints)
+
+	// Cached until the refresh interval
+	_, err = relay.GetConstraints(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, constraintsRequests)
+
+	// Relays not publishing constraints are unconstrained
+	relay, err = NewRemoteRelay(srv.URL+"/none", nil)
+	require.NoError(t, err)
+	constraints, err = relay.GetConstraints(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, RelayConstraints{}, constraints)
+
+	// The configured minimum value raises the published one
+	r.HandleFunc("/min_value/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	r.HandleFunc("/min_value/relay/v1/builder/constraints", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"min_value": "1000"}`))
+	})
+	for configured, expected := range map[int64]int64{100: 1000, 5000: 5000} {
+		relay, err = NewRemoteRelayWithOptions(srv.URL+"/min_value", nil, RemoteRelayOptions{MinValue: big.NewInt(configured)})
+		require.NoError(t, err)
+		constraints, err = relay.GetConstraints(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(expected), constraints.MinValue.BigInt())
+	}
+}
+
+func TestRemoteRelayBuilderRegistration(t *testing.T) {
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+
+	registered := map[string]bool{}
+	r.HandleFunc("/relay/v1/builder/builders/{pubkey}", func(w http.ResponseWriter, r *http.Request) {
+		if !registered[mux.Vars(r)["pubkey"]] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet)
+	r.HandleFunc("/relay/v1/builder/builders", func(w http.ResponseWriter, r *http.Request) {
+		var registration boostTypes.SignedValidatorRegistration
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&registration))
+		registered[registration.Message.Pubkey.String()] = true
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodPost)
+
+	srv := httptest.NewServer(r)
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+
+	pubkey := boostTypes.PublicKey{0x01}
+	ok, err := relay.IsBuilderRegistered(context.Background(), pubkey)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	err = relay.RegisterBuilder(context.Background(), &boostTypes.SignedValidatorRegistration{
+		Message: &boostTypes.RegisterValidatorRequestMessage{Pubkey: pubkey},
+	})
+	require.NoError(t, err)
+
+	ok, err = relay.IsBuilderRegistered(context.Background(), pubkey)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The relays implementing only the relay API answer 404
+	srv = httptest.NewServer(mux.NewRouter())
+	t.Cleanup(srv.Close)
+	relay, err = NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+	ok, err = relay.IsBuilderRegistered(context.Background(), pubkey)
+	require.NoError(t, err)
+	require.False(t, ok)
+	err = relay.RegisterBuilder(context.Background(), &boostTypes.SignedValidatorRegistration{
+		Message: &boostTypes.RegisterValidatorRequestMessage{Pubkey: pubkey},
+	})
+	require.ErrorIs(t, err, ErrBuilderRegistrationUnsupported)
+}
+
+func TestRemoteRelayCancellations(t *testing.T) {
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+
+	var cancellations []string
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		cancellations = append(cancellations, r.URL.Query().Get("cancellations"))
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodPost)
+
+	srv := httptest.NewServer(r)
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+	require.False(t, relay.CancelsBids())
+	require.NoError(t, relay.SubmitBlock(context.Background(), newTestSubmitBlockRequest(t, nil)))
+
+	// The cancellable submissions replace the builder's previous ones for the slot
+	relay, err = NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{CancelBids: true})
+	require.NoError(t, err)
+	require.True(t, relay.CancelsBids())
+	require.NoError(t, relay.SubmitBlock(context.Background(), newTestSubmitBlockRequest(t, nil)))
+	require.Equal(t, []string{"", "1"}, cancellations)
+
+	require.True(t, relayListed([]string{"relay.example.com", " " + relay.host}, srv.URL))
+	require.False(t, relayListed([]string{"relay.example.com"}, srv.URL))
+}
+
+func TestRemoteRelayGetRegisteredGasLimit(t *testing.T) {
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+
+	registered := boostTypes.PublicKey{0x01}
+	r.HandleFunc("/relay/v1/data/validator_registration", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pubkey") != registered.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(boostTypes.SignedValidatorRegistration{
+			Message: &boostTypes.RegisterValidatorRequestMessage{Pubkey: registered, GasLimit: 30_000_000},
+		})
+	}).Methods(http.MethodGet)
+
+	srv := httptest.NewServer(r)
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+
+	gasLimit, err := relay.GetRegisteredGasLimit(context.Background(), registered)
+	require.NoError(t, err)
+	require.Equal(t, uint64(30_000_000), gasLimit)
+
+	_, err = relay.GetRegisteredGasLimit(context.Background(), boostTypes.PublicKey{0x02})
+	require.ErrorIs(t, err, ErrRegistrationNotFound)
+}
+
+func TestRemoteRelaySubmissionID(t *testing.T) {
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+
+	submissionIDs := make(chan string, 1)
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		submissionIDs <- r.Header.Get(SubmissionIDHeader)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	srv := httptest.NewServer(r)
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+
+	err = relay.SubmitBlock(withSubmissionID(context.Background(), "test-submission"), &boostTypes.BuilderSubmitBlockRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "test-submission", <-submissionIDs)
+}
+
+func TestRemoteRelayClientVersionHeader(t *testing.T) {
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+
+	clientVersions := make(chan string, 1)
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		clientVersions <- r.Header.Get(ClientVersionHeader)
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(r)
+
+	ctx := withClientVersion(withSubmissionID(context.Background(), "test-submission"), "Geth/v1.10.23-stable")
+
+	// The header is opt-in
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+	require.NoError(t, relay.SubmitBlock(ctx, &boostTypes.BuilderSubmitBlockRequest{}))
+	require.Equal(t, "", <-clientVersions)
+
+	relay, err = NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{ClientVersionHeader: true})
+	require.NoError(t, err)
+	require.NoError(t, relay.SubmitBlock(ctx, &boostTypes.BuilderSubmitBlockRequest{}))
+	require.Equal(t, "Geth/v1.10.23-stable", <-clientVersions)
+}
+
+func TestRemoteRelayProposerIndexHeader(t *testing.T) {
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+
+	proposerIndexes := make(chan string, 1)
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		proposerIndexes <- r.Header.Get(ProposerIndexHeader)
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(r)
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+
+	ctx := withSubmissionID(context.Background(), "test-submission")
+	require.NoError(t, relay.SubmitBlock(ctx, &boostTypes.BuilderSubmitBlockRequest{}))
+	require.Equal(t, "", <-proposerIndexes)
+
+	require.NoError(t, relay.SubmitBlock(withProposerIndex(ctx, 0), &boostTypes.BuilderSubmitBlockRequest{}))
+	require.Equal(t, "0", <-proposerIndexes)
+}
+
+func TestRemoteRelayURLValidation(t *testing.T) {
+	_, err := NewRemoteRelay("localhost:28545", nil)
+	require.Error(t, err)
+
+	_, err = NewRemoteRelay("http://", nil)
+	require.Error(t, err)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	r.HandleFunc("/eth/v1/builder/status", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(r)
+
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+	require.NoError(t, relay.CheckReachable(context.Background()))
+
+	srv.Close()
+	err = relay.CheckReachable(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), srv.URL)
+}
+
+func TestRemoteRelayCodec(t *testing.T) {
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+
+	acceptSSZ, sszStatus := true, http.StatusUnsupportedMediaType
+	submissions := make(chan *boostTypes.BuilderSubmitBlockRequest, 2)
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var codec Codec = JSONCodec{}
+		if r.Header.Get("Content-Type") == "application/octet-stream" {
+			if !acceptSSZ {
+				w.WriteHeader(sszStatus)
+				return
+			}
+			codec = SSZCodec{}
+		}
+		req, err := codec.Decode(body)
+		require.NoError(t, err)
+		submissions <- req
+		w.WriteHeader(http.StatusOK)
+	})
+
+	srv := httptest.NewServer(r)
+	relay, err := NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{Codec: SSZCodec{}})
+	require.NoError(t, err)
+
+	req := newTestSubmitBlockRequest(t, []hexutil.Bytes{{0x01, 0x02}})
+	require.NoError(t, relay.SubmitBlock(context.Background(), req))
+	require.Equal(t, req, <-submissions)
+
+	// Falls back to json
+	acceptSSZ = false
+	require.NoError(t, relay.SubmitBlock(context.Background(), req))
+	require.Equal(t, req, <-submissions)
+	require.Equal(t, JSONCodec{}, relay.submissionCodec())
+
+	// Relays failing to decode the submission answer 400, the json submission is accepted
+	relay.setCodec(SSZCodec{})
+	sszStatus = http.StatusBadRequest
+	require.NoError(t, relay.SubmitBlock(context.Background(), req))
+	require.Equal(t, req, <-submissions)
+	require.Equal(t, JSONCodec{}, relay.submissionCodec())
+}
+
+func TestRemoteRelayTimeouts(t *testing.T) {
+	var validatorsDelay, submitDelay int64
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Duration(atomic.LoadInt64(&validatorsDelay)))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Duration(atomic.LoadInt64(&submitDelay)))
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	relay, err := NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{SubmitTimeout: 300 * time.Millisecond, ValidatorsTimeout: 100 * time.Millisecond})
+	require.NoError(t, err)
+
+	// The validators fail fast while the submissions get their own budget
+	atomic.StoreInt64(&validatorsDelay, int64(200*time.Millisecond))
+	atomic.StoreInt64(&submitDelay, int64(200*time.Millisecond))
+	_, err = relay.getSlotValidatorMapFromRelay()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NoError(t, relay.SubmitBlock(context.Background(), &boostTypes.BuilderSubmitBlockRequest{}))
+
+	atomic.StoreInt64(&submitDelay, int64(400*time.Millisecond))
+	require.ErrorIs(t, relay.SubmitBlock(context.Background(), &boostTypes.BuilderSubmitBlockRequest{}), context.DeadlineExceeded)
+}
+
+func TestRemoteRelaySubmitErrorBody(t *testing.T) {
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":400,"message":"incorrect gas limit"}`))
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	req := newTestSubmitBlockRequest(t, []hexutil.Bytes{{0x01, 0x02}})
+
+	relay, err := NewRemoteRelay(srv.URL, nil)
+	require.NoError(t, err)
+	err = relay.SubmitBlock(context.Background(), req)
+	var respErr *RelayResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusBadRequest, respErr.StatusCode)
+	require.Equal(t, `{"code":400,"message":"incorrect gas limit"}`, respErr.Body)
+	require.False(t, respErr.Truncated)
+	require.ErrorContains(t, err, "incorrect gas limit")
+
+	relay, err = NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{ErrorBodyLimit: 10})
+	require.NoError(t, err)
+	err = relay.SubmitBlock(context.Background(), req)
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, `{"code":40`, respErr.Body)
+	require.True(t, respErr.Truncated)
+	require.ErrorContains(t, err, "(truncated)")
+}
@@ -0,0 +1,158 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+var (
+	// ErrUnknownRelay is returned when toggling or testing a relay the builder does not submit to
+	ErrUnknownRelay = errors.New("unknown relay")
+	// ErrRelayDisabled is returned when the relays of a submission are all disabled
+	ErrRelayDisabled = errors.New("relay disabled")
+)
+
+// relayToggles are the relays disabled at runtime by relayKey, the relays are enabled by default.
+type relayToggles struct {
+	mu       sync.RWMutex
+	disabled map[string]bool
+}
+
+func (t *relayToggles) set(relay IRelay, enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.disabled == nil {
+		t.disabled = make(map[string]bool)
+	}
+	if enabled {
+		delete(t.disabled, relayKey(relay))
+	} else {
+		t.disabled[relayKey(relay)] = true
+	}
+}
+
+func (t *relayToggles) enabled(relay IRelay) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return !t.disabled[relayKey(relay)]
+}
+
+// relayKey identifies the relay by its URL, the relays of a host may differ by port or path, or by its name for the
+// relays without a URL.
+func relayKey(relay IRelay) string {
+	name, url := relayIdentity(relay)
+	if url != "" {
+		return url
+	}
+	return name
+}
+
+// EnableRelay resumes the submissions to the relay, identified by its URL or its name in Relays.
+func (b *Builder) EnableRelay(id string) error {
+	return b.toggleRelay(id, true)
+}
+
+// DisableRelay stops the submissions to the relay, identified by its URL or its name in Relays, until it is enabled
+// again. The relay is still used for the validator registrations.
+func (b *Builder) DisableRelay(id string) error {
+	return b.toggleRelay(id, false)
+}
+
+// toggleRelay toggles the relays whose URL or name in Relays is the id, all of the host's relays for a name.
+func (b *Builder) toggleRelay(id string, enabled bool) error {
+	found := false
+	for _, relay := range b.relays() {
+		if name, url := relayIdentity(relay); id == url || id == name {
+			b.toggles.set(relay, enabled)
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w %q", ErrUnknownRelay, id)
+	}
+	return nil
+}
+
+// relays returns the relays of a MultiRelay, or the single relay.
+func (b *Builder) relays() []IRelay {
+	return relayMembers(b.relay)
+}
+
+func relayMembers(relay IRelay) []IRelay {
+	if multiRelay, ok := relay.(*MultiRelay); ok {
+		return multiRelay.relays
+	}
+	return []IRelay{relay}
+}
+
+func (b *Builder) anyRelayEnabled() bool {
+	for _, relay := range b.relays() {
+		if b.toggles.enabled(relay) {
+			return true
+		}
+	}
+	return false
+}
+
+// submitToEnabledRelays submits the block to the relays which are not disabled, skipping the relays whose minimum
+// value is above the bid. Each relay is sent the submission signed with its key.
+func (b *Builder) submitToEnabledRelays(ctx context.Context, req *boostTypes.BuilderSubmitBlockRequest) SubmissionResult {
+	value := req.Message.Value.BigInt()
+	blockHash := req.ExecutionPayload.BlockHash
+	// The member relays the block is in flight to are released once all of the submissions completed
+	var started []IRelay
+	defer func() {
+		for _, relay := range started {
+			b.inFlight.done(relay, blockHash)
+		}
+	}()
+	prepare := func(relay IRelay) (*boostTypes.BuilderSubmitBlockRequest, error) {
+		if !b.toggles.enabled(relay) {
+			return nil, ErrRelayDisabled
+		}
+		if err := checkMinValue(ctx, relay, b.netValue(relay, value)); err != nil {
+			return nil, err
+		}
+		relayReq, err := b.signedFor(relay, req)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.byteBudget.reserve(relay, relayReq); err != nil {
+			return nil, err
+		}
+		if !b.inFlight.start(relay, blockHash) {
+			duplicateSubmissionsCounter.Inc(1)
+			return nil, ErrSubmissionInFlight
+		}
+		started = append(started, relay)
+		return relayReq, nil
+	}
+
+	var result SubmissionResult
+	if multiRelay, ok := b.relay.(*MultiRelay); ok {
+		result = multiRelay.submitBlockTo(ctx, prepare)
+	} else {
+		result = b.submitToRelay(ctx, b.relay, prepare)
+	}
+	b.statuses.record(b.relays(), result, time.Now())
+	return result
+}
+
+func (b *Builder) submitToRelay(ctx context.Context, relay IRelay, prepare func(IRelay) (*boostTypes.BuilderSubmitBlockRequest, error)) SubmissionResult {
+	name, _ := relayIdentity(relay)
+	relayReq, err := prepare(relay)
+	if err != nil {
+		return SubmissionResult{{Relay: name, Err: err, Skipped: true}}
+	}
+	start := time.Now()
+	err = relay.SubmitBlock(ctx, relayReq)
+	relaySubmitTimer.UpdateSince(start)
+	return SubmissionResult{{Relay: name, Err: err}}
+}
@@ -0,0 +1,69 @@
+package builder
+
+import (
+	"testing"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToggleRelay(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	executableData := newTestExecutableData()
+
+	require.ErrorIs(t, builder.DisableRelay("relay.example.com"), ErrUnknownRelay)
+
+	require.NoError(t, builder.DisableRelay("unknown"))
+	require.False(t, builder.Relays()[0].Enabled)
+	err := builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25)
+	require.ErrorIs(t, err, ErrRelayDisabled)
+	require.Nil(t, testRelay.submittedMsg)
+	// Disabled relays don't trip the circuit breaker
+	require.Equal(t, "closed", builder.Relays()[0].CircuitState)
+
+	require.NoError(t, builder.EnableRelay("unknown"))
+	require.True(t, builder.Relays()[0].Enabled)
+	require.NoError(t, builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25))
+	require.NotNil(t, testRelay.submittedMsg)
+}
+
+func TestToggleMultiRelay(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	remoteRelay := &testRelay{}
+	localRelay := &LocalRelay{}
+	builder.relay = NewMultiRelay([]IRelay{remoteRelay, localRelay}, ValidatorConflictRecent)
+
+	// Only the enabled relays are submitted to, the zero LocalRelay could not accept the submission
+	require.NoError(t, builder.DisableRelay("local"))
+	relays := builder.Relays()
+	require.True(t, relays[0].Enabled)
+	require.False(t, relays[1].Enabled)
+
+	req := newTestSubmitBlockRequest(t, nil)
+	require.NoError(t, builder.submitBlock("submission", "", nil, req, 25))
+	require.Equal(t, req, remoteRelay.submittedMsg)
+
+	require.NoError(t, builder.DisableRelay("unknown"))
+	require.ErrorIs(t, builder.submitBlock("submission", "", nil, req, 25), ErrRelayDisabled)
+}
+
+func TestToggleRelayByURL(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	relayA := &RemoteRelay{host: "relay.example.com", url: "https://relay.example.com/a"}
+	relayB := &RemoteRelay{host: "relay.example.com", url: "https://relay.example.com/b"}
+	builder.relay = NewMultiRelay([]IRelay{relayA, relayB}, ValidatorConflictRecent)
+
+	// The relays of a host are toggled apart by URL
+	require.NoError(t, builder.DisableRelay("https://relay.example.com/a"))
+	require.False(t, builder.toggles.enabled(relayA))
+	require.True(t, builder.toggles.enabled(relayB))
+
+	// The host's name toggles all of its relays
+	require.NoError(t, builder.DisableRelay("relay.example.com"))
+	require.False(t, builder.toggles.enabled(relayB))
+	require.NoError(t, builder.EnableRelay("relay.example.com"))
+	require.True(t, builder.toggles.enabled(relayA))
+	require.True(t, builder.toggles.enabled(relayB))
+
+	require.ErrorIs(t, builder.DisableRelay("https://relay.example.com/c"), ErrUnknownRelay)
+}
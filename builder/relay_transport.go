@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// relayTransport returns the default transport, or a copy of it sending every request through the proxy if set,
+// with the TLS configuration if set and without HTTP/2 if disabled. The default transport uses the proxy set in the
+// environment, if any, and negotiates HTTP/2 with the https relays supporting it, multiplexing the requests over a
+// single connection.
+func relayTransport(proxyURL *url.URL, tlsConfig *tls.Config, disableHTTP2 bool) http.RoundTripper {
+	if proxyURL == nil && tlsConfig == nil && !disableHTTP2 {
+		return http.DefaultTransport
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig.Clone()
+	}
+	if disableHTTP2 {
+		// A non-nil empty map disables HTTP/2 in the transport
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	return transport
+}
+
+// observeProtocol logs the protocol of the relay's responses when it changes, noting the https relays which did not
+// negotiate HTTP/2 while it is enabled.
+func (r *RemoteRelay) observeProtocol(resp *http.Response) {
+	r.protoLock.Lock()
+	changed := r.proto != resp.Proto
+	r.proto = resp.Proto
+	r.protoLock.Unlock()
+	if !changed {
+		return
+	}
+
+	if !r.disableHTTP2 && resp.ProtoMajor < 2 && strings.HasPrefix(r.endpoint, "https://") {
+		log.Info("relay did not negotiate HTTP/2, submitting over a connection per request", "relay", r.url, "protocol", resp.Proto)
+		return
+	}
+	log.Info("relay protocol negotiated", "relay", r.url, "protocol", resp.Proto)
+}
+
+// protocol returns the protocol of the relay's last response, empty before the first response.
+func (r *RemoteRelay) protocol() string {
+	r.protoLock.Lock()
+	defer r.protoLock.Unlock()
+
+	return r.proto
+}
@@ -0,0 +1,49 @@
+package builder
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTLSRelay returns a mock relay serving HTTP/2 over TLS, and the TLS configuration trusting it.
+func newTestTLSRelay(t testing.TB) (*httptest.Server, *tls.Config) {
+	r := mux.NewRouter()
+	r.HandleFunc("/relay/v1/builder/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	r.HandleFunc("/relay/v1/builder/blocks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewUnstartedServer(r)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	return srv, &tls.Config{RootCAs: roots}
+}
+
+func TestRemoteRelayHTTP2(t *testing.T) {
+	srv, tlsConfig := newTestTLSRelay(t)
+	req := newTestSubmitBlockRequest(t, []hexutil.Bytes{{0x01, 0x02}})
+
+	relay, err := NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{TLSConfig: tlsConfig})
+	require.NoError(t, err)
+	require.NoError(t, relay.SubmitBlock(context.Background(), req))
+	require.Equal(t, "HTTP/2.0", relay.protocol())
+
+	relay, err = NewRemoteRelayWithOptions(srv.URL, nil, RemoteRelayOptions{TLSConfig: tlsConfig, DisableHTTP2: true})
+	require.NoError(t, err)
+	require.NoError(t, relay.SubmitBlock(context.Background(), req))
+	require.Equal(t, "HTTP/1.1", relay.protocol())
+}
@@ -2,41 +2,234 @@ package builder
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+var errTaskSuperseded = errors.New("task superseded")
+
+const (
+	defaultResubmitWorkers = 4
+	resubmitQueueSize      = 64
+	// resubmitNudgeQueueSize bounds the queued nudges, kept apart from the regular iterations so that the nudges
+	// never crowd them out
+	resubmitNudgeQueueSize = 16
+)
+
+// Resubmitter runs the tasks' first iteration in the caller and queues the repeated iterations, which are run
+// by a fixed pool of workers.
 type Resubmitter struct {
 	mu     sync.Mutex
 	cancel context.CancelFunc
+
+	// allowOverlap lets a new task run while an iteration of the previous task is still in flight.
+	// Otherwise iterations are serialized across tasks and at most one fn runs at a time.
+	allowOverlap bool
+	runMu        sync.Mutex
+
+	// workers is the size of the worker pool, defaultResubmitWorkers if zero
+	workers     int
+	startOnce   sync.Once
+	queue       chan *resubmission
+	nudges      chan *resubmission
+	busyWorkers int32
+
+	// current is the repeated task, nil if the latest task runs once
+	current *resubmission
+}
+
+// resubmission is a queued iteration of a task, dropped once the task's context is done.
+type resubmission struct {
+	ctx      context.Context
+	fn       func() error
+	interval time.Duration
+	// slot is the slot the task builds for
+	slot uint64
+	// nudged iterations run once, out of the task's cadence, for the task
+	nudged bool
+	task   *resubmission
+	// nudgePending is set while a nudge of the task is queued, the later nudges are coalesced with it. Accessed
+	// atomically
+	nudgePending int32
 }
 
 func (r *Resubmitter) newTask(repeatFor time.Duration, interval time.Duration, fn func() error) error {
-	repeatUntilCh := time.After(repeatFor)
+	return r.newTaskUnless(nil, 0, 0, repeatFor, interval, fn)
+}
 
+// newTaskUnless is newTask for the slot, unless superseded returns true when the task would replace the previous one.
+// If delay is positive the first iteration is queued for the workers after the delay instead of run in the caller,
+// and it is dropped like the repeated iterations if the task is replaced in the meantime.
+func (r *Resubmitter) newTaskUnless(superseded func() bool, slot uint64, delay time.Duration, repeatFor time.Duration, interval time.Duration, fn func() error) error {
 	r.mu.Lock()
+	if superseded != nil && superseded() {
+		r.mu.Unlock()
+		return errTaskSuperseded
+	}
 	if r.cancel != nil {
 		r.cancel()
 	}
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), repeatFor)
 	r.cancel = cancel
+	task := &resubmission{ctx: ctx, fn: fn, interval: interval, slot: slot}
+	r.current = task
 	r.mu.Unlock()
 
-	firstRunErr := fn()
-
-	go func() {
-		for ctx.Err() == nil {
-			select {
-			case <-ctx.Done():
-				return
-			case <-repeatUntilCh:
-				cancel()
-				return
-			case <-time.After(interval):
-				fn()
-			}
-		}
-	}()
+	if delay > 0 {
+		r.startWorkers()
+		r.scheduleAfter(task, delay)
+		return nil
+	}
+
+	firstRunErr := r.run(ctx, fn)
+
+	r.startWorkers()
+	r.schedule(task)
 
 	return firstRunErr
 }
+
+// runOnce cancels the previous task and runs fn a single time, skipping it if the deadline passes
+// while waiting for an in-flight iteration of the previous task.
+func (r *Resubmitter) runOnce(deadline time.Time, fn func() error) error {
+	return r.runOnceUnless(nil, 0, deadline, fn)
+}
+
+// runOnceUnless is runOnce, unless superseded returns true when the task would replace the previous one. If delay is
+// positive fn is run after the delay instead of in the caller, unless the task is replaced in the meantime.
+func (r *Resubmitter) runOnceUnless(superseded func() bool, delay time.Duration, deadline time.Time, fn func() error) error {
+	r.mu.Lock()
+	if superseded != nil && superseded() {
+		r.mu.Unlock()
+		return errTaskSuperseded
+	}
+	if r.cancel != nil {
+		r.cancel()
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	r.cancel = cancel
+	r.current = nil
+	r.mu.Unlock()
+
+	if delay > 0 {
+		time.AfterFunc(delay, func() {
+			defer cancel()
+			r.runRecovered(ctx, fn)
+		})
+		return nil
+	}
+	defer cancel()
+
+	return r.run(ctx, fn)
+}
+
+// run waits for the in-flight iteration of a previous task to complete, unless overlap is allowed,
+// and skips fn if the task was superseded in the meantime.
+func (r *Resubmitter) run(ctx context.Context, fn func() error) error {
+	if !r.allowOverlap {
+		r.runMu.Lock()
+		defer r.runMu.Unlock()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return fn()
+}
+
+// runRecovered is run for the resubmissions, which have no caller to return a panic to.
+func (r *Resubmitter) runRecovered(ctx context.Context, fn func() error) (err error) {
+	defer recoverPanic(&err, "resubmission")
+	return r.run(ctx, fn)
+}
+
+func (r *Resubmitter) startWorkers() {
+	r.startOnce.Do(func() {
+		workers := r.workers
+		if workers <= 0 {
+			workers = defaultResubmitWorkers
+		}
+		r.queue = make(chan *resubmission, resubmitQueueSize)
+		r.nudges = make(chan *resubmission, resubmitNudgeQueueSize)
+		resubmitWorkersGauge.Update(int64(workers))
+		for i := 0; i < workers; i++ {
+			go r.work()
+		}
+	})
+}
+
+// schedule queues the task's next iteration after its interval, unless the task is done by then.
+// If the queue is full the iteration is skipped, and the task is scheduled again after another interval.
+func (r *Resubmitter) schedule(task *resubmission) {
+	r.scheduleAfter(task, task.interval)
+}
+
+// scheduleAfter is schedule after the delay rather than the task's interval.
+func (r *Resubmitter) scheduleAfter(task *resubmission, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		if task.ctx.Err() != nil {
+			return
+		}
+		select {
+		case r.queue <- task:
+			resubmitQueueDepthGauge.Update(int64(len(r.queue)))
+		default:
+			droppedResubmissionsCounter.Inc(1)
+			r.schedule(task)
+		}
+	})
+}
+
+// nudge queues an immediate iteration of the repeated task for the slot, on top of its regular iterations, on the
+// nudges' queue. A nudge while one is queued for the task is coalesced with it. Returns false if no repeated task is
+// active for the slot or the nudges' queue is full.
+func (r *Resubmitter) nudge(slot uint64) bool {
+	r.mu.Lock()
+	task := r.current
+	r.mu.Unlock()
+
+	if task == nil || task.slot != slot || task.ctx.Err() != nil {
+		return false
+	}
+
+	if !atomic.CompareAndSwapInt32(&task.nudgePending, 0, 1) {
+		return true
+	}
+	r.startWorkers()
+	select {
+	case r.nudges <- &resubmission{ctx: task.ctx, fn: task.fn, slot: task.slot, nudged: true, task: task}:
+		return true
+	default:
+		atomic.StoreInt32(&task.nudgePending, 0)
+		droppedResubmissionsCounter.Inc(1)
+		return false
+	}
+}
+
+func (r *Resubmitter) work() {
+	for {
+		var task *resubmission
+		select {
+		case task = <-r.nudges:
+			// Nudges queued from now on run after this one
+			atomic.StoreInt32(&task.task.nudgePending, 0)
+		case task = <-r.queue:
+			resubmitQueueDepthGauge.Update(int64(len(r.queue)))
+		}
+		if task.ctx.Err() != nil {
+			// Superseded or past the deadline
+			continue
+		}
+
+		resubmitBusyWorkersGauge.Update(int64(atomic.AddInt32(&r.busyWorkers, 1)))
+		r.runRecovered(task.ctx, task.fn)
+		resubmitBusyWorkersGauge.Update(int64(atomic.AddInt32(&r.busyWorkers, -1)))
+
+		if !task.nudged {
+			r.schedule(task)
+		}
+	}
+}
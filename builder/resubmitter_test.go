@@ -1,7 +1,10 @@
 package builder
 
 import (
+	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -54,3 +57,191 @@ func TestResubmitter(t *testing.T) {
 	case <-time.After(200 * time.Millisecond):
 	}
 }
+
+func TestResubmitterNoOverlap(t *testing.T) {
+	resubmitter := Resubmitter{}
+
+	var mu sync.Mutex
+	running, maxRunning, runs := 0, 0, 0
+	slowFn := func() error {
+		mu.Lock()
+		running++
+		runs++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(150 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	}
+
+	// Build takes longer than the resubmit interval, and new tasks keep superseding the running one
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resubmitter.newTask(500*time.Millisecond, 50*time.Millisecond, slowFn)
+		}()
+		time.Sleep(100 * time.Millisecond)
+	}
+	wg.Wait()
+	time.Sleep(700 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, 1, maxRunning)
+	require.Greater(t, runs, 3)
+}
+
+func TestResubmitterAllowOverlap(t *testing.T) {
+	resubmitter := Resubmitter{allowOverlap: true}
+
+	var running, maxRunning int32
+	slowFn := func() error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(200 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resubmitter.newTask(100*time.Millisecond, time.Second, slowFn)
+		}()
+		time.Sleep(50 * time.Millisecond)
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+}
+
+func TestResubmitterWorkerPool(t *testing.T) {
+	resubmitter := Resubmitter{allowOverlap: true, workers: 1}
+
+	release := make(chan struct{})
+	var runsA, runsB int32
+	resubmitter.newTask(time.Second, 10*time.Millisecond, func() error {
+		if atomic.AddInt32(&runsA, 1) == 2 {
+			// Holds the single worker
+			<-release
+		}
+		return nil
+	})
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&runsA) == 2 }, time.Second, time.Millisecond)
+
+	// The new task's iterations wait for the worker, and are dropped once past the task's deadline
+	resubmitter.newTask(50*time.Millisecond, 10*time.Millisecond, func() error {
+		atomic.AddInt32(&runsB, 1)
+		return nil
+	})
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	time.Sleep(100 * time.Millisecond)
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&runsB))
+	// The superseded task is not resubmitted either
+	require.Equal(t, int32(2), atomic.LoadInt32(&runsA))
+}
+
+func TestResubmitterNudge(t *testing.T) {
+	resubmitter := Resubmitter{}
+	require.False(t, resubmitter.nudge(25))
+
+	var runs int32
+	require.NoError(t, resubmitter.newTaskUnless(nil, 25, 0, time.Second, 300*time.Millisecond, func() error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}))
+	require.False(t, resubmitter.nudge(26))
+
+	require.True(t, resubmitter.nudge(25))
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 2 }, 100*time.Millisecond, time.Millisecond)
+
+	// The nudge does not add to the regular iterations
+	time.Sleep(450 * time.Millisecond)
+	require.Equal(t, int32(3), atomic.LoadInt32(&runs))
+
+	time.Sleep(600 * time.Millisecond)
+	require.False(t, resubmitter.nudge(25))
+}
+
+func TestResubmitterQueueFull(t *testing.T) {
+	resubmitter := Resubmitter{queue: make(chan *resubmission, 1)}
+	resubmitter.queue <- &resubmission{ctx: context.Background()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	task := &resubmission{ctx: ctx, fn: func() error { return nil }, interval: 10 * time.Millisecond}
+	resubmitter.schedule(task)
+
+	// The iterations skipped while the queue is full don't end the task's resubmissions
+	time.Sleep(50 * time.Millisecond)
+	<-resubmitter.queue
+	select {
+	case queued := <-resubmitter.queue:
+		require.Equal(t, task, queued)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("task not rescheduled")
+	}
+}
+
+func TestResubmitterNudgeCoalesced(t *testing.T) {
+	resubmitter := Resubmitter{workers: 1}
+
+	release := make(chan struct{})
+	var runs int32
+	require.NoError(t, resubmitter.newTaskUnless(nil, 25, 0, time.Second, time.Hour, func() error {
+		if atomic.AddInt32(&runs, 1) == 2 {
+			<-release
+		}
+		return nil
+	}))
+
+	// The nudges queued while one is pending run once
+	require.True(t, resubmitter.nudge(25))
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 2 }, 100*time.Millisecond, time.Millisecond)
+	require.True(t, resubmitter.nudge(25))
+	require.True(t, resubmitter.nudge(25))
+	require.True(t, resubmitter.nudge(25))
+	close(release)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 3 }, 100*time.Millisecond, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(3), atomic.LoadInt32(&runs))
+}
+
+func TestResubmitterDelayedTask(t *testing.T) {
+	resubmitter := Resubmitter{}
+
+	var runsA, runsB int32
+	start := time.Now()
+	require.NoError(t, resubmitter.newTaskUnless(nil, 25, 100*time.Millisecond, time.Second, time.Hour, func() error {
+		atomic.AddInt32(&runsA, 1)
+		return nil
+	}))
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&runsA) == 1 }, 300*time.Millisecond, time.Millisecond)
+
+	// The delayed first iteration is dropped once the task is replaced
+	require.NoError(t, resubmitter.newTaskUnless(nil, 26, 100*time.Millisecond, time.Second, time.Hour, func() error {
+		atomic.AddInt32(&runsB, 1)
+		return nil
+	}))
+	require.NoError(t, resubmitter.newTaskUnless(nil, 27, 0, time.Second, time.Hour, func() error { return nil }))
+	time.Sleep(200 * time.Millisecond)
+	require.Zero(t, atomic.LoadInt32(&runsB))
+}
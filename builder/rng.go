@@ -0,0 +1,38 @@
+package builder
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedRand is the source of all the builder's randomness, safe for concurrent use.
+type lockedRand struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// newLockedRand returns the randomness drawn from the source, or from a source seeded with crypto/rand if nil.
+func newLockedRand(source rand.Source) *lockedRand {
+	if source == nil {
+		source = rand.NewSource(randomSeed())
+	}
+	return &lockedRand{rand: rand.New(source)}
+}
+
+// randomSeed returns a seed from crypto/rand, or from the time if it fails.
+func randomSeed() int64 {
+	var seed [8]byte
+	if _, err := crand.Read(seed[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(seed[:]))
+}
+
+func (r *lockedRand) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rand.Read(p)
+}
@@ -0,0 +1,29 @@
+package builder
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandSource(t *testing.T) {
+	submissionIDs := func(source rand.Source) []string {
+		builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SingleShot: true, RandSource: source})
+		var ids []string
+		for slot := uint64(25); slot < 28; slot++ {
+			require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(slot)))
+			ids = append(ids, testRelay.submissionID)
+		}
+		return ids
+	}
+
+	// The same seed replays the same submission IDs
+	ids := submissionIDs(rand.NewSource(1))
+	require.Equal(t, ids, submissionIDs(rand.NewSource(1)))
+	require.NotEqual(t, ids, submissionIDs(rand.NewSource(2)))
+	require.NotEqual(t, ids[0], ids[1])
+
+	// Seeded from crypto/rand otherwise
+	require.NotEqual(t, submissionIDs(nil), submissionIDs(nil))
+}
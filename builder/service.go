@@ -1,12 +1,18 @@
 package builder
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
@@ -26,6 +32,8 @@ const (
 	_PathGetPayload        = "/eth/v1/builder/blinded_blocks"
 )
 
+const relayReachableTimeout = 5 * time.Second
+
 type BuilderPayloadAttributes struct {
 	Timestamp             hexutil.Uint64 `json:"timestamp"`
 	Random                common.Hash    `json:"prevRandao"`
@@ -33,22 +41,135 @@ type BuilderPayloadAttributes struct {
 	Slot                  uint64         `json:"slot"`
 	HeadHash              common.Hash    `json:"blockHash"`
 	GasLimit              uint64
+	BuildParams           *BuildParams `json:"buildParams,omitempty"`
+	// CandidateHeads are the competing heads built on besides HeadHash during reorg uncertainty, in order of
+	// preference. Only built on with BuilderOptions.MaxCandidateHeads. A block is submitted for each of them, as the
+	// relays only deliver the bid on the parent the proposer asks for
+	CandidateHeads []common.Hash `json:"candidateHeads,omitempty"`
+}
+
+var ErrInvalidAttributes = errors.New("invalid payload attributes")
+
+// Validate checks that the fields required for building a block are set.
+func (attrs *BuilderPayloadAttributes) Validate() error {
+	switch {
+	case attrs.Slot == 0:
+		return fmt.Errorf("%w: missing slot", ErrInvalidAttributes)
+	case attrs.HeadHash == (common.Hash{}):
+		return fmt.Errorf("%w: missing head hash", ErrInvalidAttributes)
+	case attrs.Timestamp == 0:
+		return fmt.Errorf("%w: missing timestamp", ErrInvalidAttributes)
+	}
+	return nil
 }
 
 type Service struct {
 	srv     *http.Server
 	builder IBuilder
+	// pprofSrv serves the pprof handlers if enabled
+	pprofSrv *http.Server
 }
 
-func (s *Service) Start() {
+func (s *Service) Start() error {
+	if builder, ok := s.builder.(*Builder); ok {
+		builder.Start()
+	}
 	if s.srv != nil {
 		log.Info("Service started")
 		go s.srv.ListenAndServe()
 	}
+	if s.pprofSrv != nil {
+		log.Info("builder pprof server started", "addr", s.pprofSrv.Addr)
+		go func() {
+			if err := s.pprofSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Error("builder pprof server failed", "err", err, "addr", s.pprofSrv.Addr)
+			}
+		}()
+	}
+	return nil
+}
+
+// Stop stops the builder, closing its relay connections, and the pprof server.
+func (s *Service) Stop() error {
+	if builder, ok := s.builder.(*Builder); ok {
+		builder.Stop()
+	}
+	if s.pprofSrv != nil {
+		if err := s.pprofSrv.Close(); err != nil {
+			log.Warn("could not close the builder pprof server", "err", err, "addr", s.pprofSrv.Addr)
+		}
+	}
+	return nil
+}
+
+// enablePprof serves Go's pprof handlers on the address once the service is started, set from EnablePprof only.
+func (s *Service) enablePprof(addr string) {
+	s.pprofSrv = newPprofServer(addr)
+}
+
+// BuilderAPI is the builder's RPC API, served in the builder namespace. The Service's lifecycle methods are not
+// part of it.
+type BuilderAPI struct {
+	builder IBuilder
+}
+
+func NewBuilderAPI(builder IBuilder) *BuilderAPI {
+	return &BuilderAPI{builder: builder}
+}
+
+func (api *BuilderAPI) PayloadAttributes(payloadAttributes *BuilderPayloadAttributes) error {
+	return api.builder.OnPayloadAttribute(payloadAttributes)
+}
+
+func (api *BuilderAPI) GetPayload(blindedBlock *boostTypes.SignedBlindedBeaconBlock) (*boostTypes.ExecutionPayload, error) {
+	return api.builder.GetPayload(blindedBlock)
+}
+
+// ObservedBids returns the relays' top bids observed for the slot by a builder in observer mode.
+func (api *BuilderAPI) ObservedBids(slot uint64) ([]ObservedBid, error) {
+	builder, ok := api.builder.(*Builder)
+	if !ok || !builder.observer {
+		return nil, errors.New("the builder is not in observer mode")
+	}
+	return builder.ObservedBids(slot), nil
+}
+
+// SubmitCanary submits a canary block to the relay named in Relays, see Builder.SubmitCanary.
+func (api *BuilderAPI) SubmitCanary(ctx context.Context, relay string) (CanaryResult, error) {
+	builder, ok := api.builder.(*Builder)
+	if !ok {
+		return CanaryResult{}, errors.New("the builder can't submit canaries")
+	}
+	return builder.SubmitCanary(ctx, relay)
 }
 
-func (s *Service) PayloadAttributes(payloadAttributes *BuilderPayloadAttributes) error {
-	return s.builder.OnPayloadAttribute(payloadAttributes)
+// Pause stops building and submitting blocks until Resume.
+func (api *BuilderAPI) Pause() error {
+	builder, ok := api.builder.(*Builder)
+	if !ok {
+		return errors.New("the builder can't be paused")
+	}
+	builder.Pause()
+	return nil
+}
+
+// Resume resumes building and submitting blocks after Pause.
+func (api *BuilderAPI) Resume() error {
+	builder, ok := api.builder.(*Builder)
+	if !ok {
+		return errors.New("the builder can't be resumed")
+	}
+	builder.Resume()
+	return nil
+}
+
+// Status returns the operational status of the builder.
+func (api *BuilderAPI) Status() (BuilderStatus, error) {
+	builder, ok := api.builder.(*Builder)
+	if !ok {
+		return BuilderStatus{}, errors.New("the builder has no status")
+	}
+	return builder.Status(), nil
 }
 
 func getRouter(localRelay *LocalRelay) http.Handler {
@@ -88,17 +209,149 @@ func NewService(listenAddr string, localRelay *LocalRelay, builder *Builder) *Se
 }
 
 type BuilderConfig struct {
-	Enabled               bool
-	EnableValidatorChecks bool
-	EnableLocalRelay      bool
-	BuilderSecretKey      string
-	RelaySecretKey        string
-	ListenAddr            string
-	GenesisForkVersion    string
-	BellatrixForkVersion  string
-	GenesisValidatorsRoot string
-	BeaconEndpoint        string
-	RemoteRelayEndpoint   string
+	Enabled                   bool
+	EnableValidatorChecks     bool
+	EnableLocalRelay          bool
+	BuilderSecretKey          string
+	RelaySecretKey            string
+	ListenAddr                string
+	GenesisForkVersion        string
+	BellatrixForkVersion      string
+	GenesisValidatorsRoot     string
+	BeaconEndpoint            string
+	RemoteRelayEndpoint       string
+	BuildStrategy             string
+	AllowOverlappingBuilds    bool
+	ProfitBreakdown           bool
+	BidValueReserve           string
+	RecordAttributesPath      string
+	NotSyncedPolicy           string
+	NotSyncedMaxWait          time.Duration
+	RegistrationCheckInterval time.Duration
+	PinMempool                bool
+	SingleShot                bool
+	CheckRelayReachable       bool
+	Reconcile                 bool
+	RemoteRelayHeaders        []string
+	SelfDrivenBuilds          bool
+	SelfDrivenBuildDelay      time.Duration
+	MaxConcurrentBuilds       int
+	RelayLatencySLA           time.Duration
+	RelayLatencySLAWindow     time.Duration
+	RemoteRelayCodec          string
+	RemoteRelayProxy          string
+	MaxRegistrationAge        time.Duration
+	ValidatorConflictPolicy   string
+	SimulateBlocks            bool
+	RemoteRelayCancelBids     []string
+	GasLimitSmoothing         float64
+	EnablePprof               bool
+	PprofAddr                 string
+	ResubmitWorkers           int
+	// RemoteRelayElVersionHeader sends the EL version with the submissions to the remote relays
+	RemoteRelayElVersionHeader bool
+	// RemoteRelayMinValues are the lowest bid values submitted to the remote relays, as host=wei pairs
+	RemoteRelayMinValues []string
+	// DumpDir is the directory the submissions are dumped to, DumpSlots the number of recent slots whose
+	// submissions are all kept. Only the 1024 most recent failed submissions are kept if DumpSlots is zero
+	DumpDir   string
+	DumpSlots uint64
+	// ProposerPaymentKey is the hex private key of the etherbase, with which the miner pays the proposer
+	ProposerPaymentKey string
+	// MissedSlotsThreshold is the number of consecutive slots without payload attributes after which the missed
+	// slots are logged and counted, disabled if zero
+	MissedSlotsThreshold uint64
+	// RelaySecretKeys are the builder keys of the relays not signed for with the builder key, as relay=key pairs
+	RelaySecretKeys []string
+	// SlotMismatchPolicy is the behaviour when the payload attributes' slot differs from the slot of their timestamp
+	// by more than SlotMismatchTolerance slots, "warn" (the default if empty) or "reject"
+	SlotMismatchPolicy    string
+	SlotMismatchTolerance uint64
+	// TimestampFlexibility is the number of seconds past the slot's start the blocks may be timestamped at, on the
+	// development networks only
+	TimestampFlexibility         uint64
+	MaxSlotsAhead                uint64
+	InitialSubmissionDelay       time.Duration
+	Observer                     bool
+	SpeculativeBuilds            bool
+	ExtraData                    string
+	BeaconPolicy                 string
+	MaintenanceWindows           string
+	SubmissionWebhookURL         string
+	SubmissionBatchSize          int
+	SubmissionStagger            time.Duration
+	ArchiveDir                   string
+	AllRelaysRejectedPause       time.Duration
+	AllRelaysRejectedWebhookURL  string
+	MaxSubmitBytesPerSlot        uint64
+	RemoteRelayStreams           []string
+	RemoteRelayStreamMaxInFlight int
+	// RelayFees are the fees the relays deduct from the bids, as relay=bps:wei pairs
+	RelayFees                    []string
+	RemoteRelaySubmitTimeout     time.Duration
+	RemoteRelayValidatorsTimeout time.Duration
+	RemoteRelayErrorBodyLimit    int
+	RemoteRelayRateLimitBackoff  time.Duration
+	RemoteRelayDisableHTTP2      bool
+	// RemoteRelayRetry is the attempts:backoff retry policy of the relays without one in RemoteRelayRetries
+	RemoteRelayRetry string
+	// RemoteRelayRetries are the retry policies of the relays, as host=attempts:backoff pairs
+	RemoteRelayRetries []string
+	// RemoteRelayDebugLog is the logging mode of the remote relay requests, for debugging only
+	RemoteRelayDebugLog string
+	// CircuitBreakerThreshold is the number of consecutive failed submissions opening the relay circuit breaker, 0
+	// disables the breaker
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is the time the relay circuit breaker stays open before the relay is retried
+	CircuitBreakerCooldown time.Duration
+	// SigningDomainCheckInterval is the interval of the builder signing domain check with the beacon node
+	SigningDomainCheckInterval time.Duration
+	// MaxCandidateHeads is the number of heads built on, the attributes' head and their candidate heads
+	MaxCandidateHeads int
+	// LazyBuild builds the blocks after the slot's first only when the relays' top bid looks beatable, by at most
+	// LazyBuildMaxDeficit wei plus LazyBuildMaxDeficitBps of the last block's profit
+	LazyBuild              bool
+	LazyBuildMaxDeficit    string
+	LazyBuildMaxDeficitBps uint64
+	// DevPrevRandao overrides the prevRandao of the built blocks, on the development networks only, see DevChainIDs
+	DevPrevRandao string
+	// DevChainIDs are the chain IDs of the development networks without a real beacon chain besides the --dev chain,
+	// on which the test features breaking the consensus rules are allowed
+	DevChainIDs []string
+	// CanarySubmissions allows the builder_submitCanary calls, whose canaries are real bids for the next slot
+	CanarySubmissions bool
+	// FailoverRelayEndpoints are the comma separated endpoints of the relays submitted to when the submission to the
+	// remote relays failed
+	FailoverRelayEndpoints string
+	// Extensions are started with the builder service, for the opt-in integrations such as the gRPC control API
+	Extensions []Extension
+}
+
+// Extension integrates with the builder once it is created, e.g. registering a lifecycle serving an API backed by
+// the builder with the node.
+type Extension func(stack *node.Node, builder *Builder) error
+
+// relayListed reports whether the relay endpoint's host is one of the hosts.
+func relayListed(hosts []string, endpoint string) bool {
+	relayURL, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	for _, host := range hosts {
+		if strings.TrimSpace(host) == relayURL.Host {
+			return true
+		}
+	}
+	return false
+}
+
+// relayHost returns the host of the relay endpoint, empty if it is not a valid URL.
+func relayHost(endpoint string) string {
+	relayURL, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	return relayURL.Host
 }
 
 func Register(stack *node.Node, backend *eth.Ethereum, cfg *BuilderConfig) error {
@@ -149,25 +402,345 @@ func Register(stack *node.Node, backend *eth.Ethereum, cfg *BuilderConfig) error
 	}
 
 	var relay IRelay
+	var failoverRelays []IRelay
 	if cfg.RemoteRelayEndpoint != "" {
-		relay = NewRemoteRelay(cfg.RemoteRelayEndpoint, localRelay)
+		headers, err := ParseRelayHeaders(cfg.RemoteRelayHeaders)
+		if err != nil {
+			return err
+		}
+		codec, err := ParseCodec(cfg.RemoteRelayCodec)
+		if err != nil {
+			return err
+		}
+		conflictPolicy, err := ParseValidatorConflictPolicy(cfg.ValidatorConflictPolicy)
+		if err != nil {
+			return err
+		}
+		minValues, err := ParseRelayMinValues(cfg.RemoteRelayMinValues)
+		if err != nil {
+			return err
+		}
+		streamURLs, err := ParseRelayStreamURLs(cfg.RemoteRelayStreams)
+		if err != nil {
+			return err
+		}
+		var sharedRetry RetryPolicy
+		if cfg.RemoteRelayRetry != "" {
+			sharedRetry, err = ParseRetryPolicy(cfg.RemoteRelayRetry)
+			if err != nil {
+				return err
+			}
+		}
+		retries, err := ParseRelayRetryPolicies(cfg.RemoteRelayRetries)
+		if err != nil {
+			return err
+		}
+		debugLog, err := ParseRelayDebugLog(cfg.RemoteRelayDebugLog)
+		if err != nil {
+			return err
+		}
+
+		relayOptions := func(endpoint string) RemoteRelayOptions {
+			retry, found := retries[relayHost(endpoint)]
+			if !found {
+				retry = sharedRetry
+			}
+			return RemoteRelayOptions{
+				Headers:             headers,
+				Codec:               codec,
+				ProxyURL:            cfg.RemoteRelayProxy,
+				CancelBids:          relayListed(cfg.RemoteRelayCancelBids, endpoint),
+				ClientVersionHeader: cfg.RemoteRelayElVersionHeader,
+				MinValue:            minValues[relayHost(endpoint)],
+				SubmitTimeout:       cfg.RemoteRelaySubmitTimeout,
+				ValidatorsTimeout:   cfg.RemoteRelayValidatorsTimeout,
+				ErrorBodyLimit:      cfg.RemoteRelayErrorBodyLimit,
+				RateLimitBackoff:    cfg.RemoteRelayRateLimitBackoff,
+				DisableHTTP2:        cfg.RemoteRelayDisableHTTP2,
+				Retry:               retry,
+				DebugLog:            debugLog,
+			}
+		}
+
+		var remoteRelays []IRelay
+		for _, endpoint := range strings.Split(cfg.RemoteRelayEndpoint, ",") {
+			endpoint = strings.TrimSpace(endpoint)
+			remoteRelay, err := NewRemoteRelayWithOptions(endpoint, localRelay, relayOptions(endpoint))
+			if err != nil {
+				return err
+			}
+			if cfg.RemoteRelayProxy != "" {
+				ctx, cancel := context.WithTimeout(context.Background(), relayReachableTimeout)
+				err = remoteRelay.CheckProxy(ctx)
+				cancel()
+				if err != nil {
+					return fmt.Errorf("remote relay proxy unreachable: %w", err)
+				}
+			}
+			if cfg.CheckRelayReachable {
+				ctx, cancel := context.WithTimeout(context.Background(), relayReachableTimeout)
+				err = remoteRelay.CheckReachable(ctx)
+				cancel()
+				if err != nil {
+					return fmt.Errorf("remote relay unreachable: %w", err)
+				}
+			}
+			if streamURL, found := streamURLs[relayHost(endpoint)]; found {
+				streamOpts := WebSocketRelayOptions{Headers: headers, MaxInFlight: cfg.RemoteRelayStreamMaxInFlight}
+				if cfg.RemoteRelayElVersionHeader {
+					streamOpts.ClientVersion = stack.Server().Config.Name
+				}
+				streamRelay, err := NewWebSocketRelay(remoteRelay, streamURL, streamOpts)
+				if err != nil {
+					return err
+				}
+				remoteRelays = append(remoteRelays, streamRelay)
+				continue
+			}
+			remoteRelays = append(remoteRelays, remoteRelay)
+		}
+
+		if len(remoteRelays) == 1 {
+			relay = remoteRelays[0]
+		} else {
+			relay = NewMultiRelayWithOptions(remoteRelays, MultiRelayOptions{
+				ConflictPolicy:      conflictPolicy,
+				SubmissionBatchSize: cfg.SubmissionBatchSize,
+				SubmissionStagger:   cfg.SubmissionStagger,
+			})
+		}
+
+		if cfg.FailoverRelayEndpoints != "" {
+			for _, endpoint := range strings.Split(cfg.FailoverRelayEndpoints, ",") {
+				endpoint = strings.TrimSpace(endpoint)
+				failoverRelay, err := NewRemoteRelayWithOptions(endpoint, localRelay, relayOptions(endpoint))
+				if err != nil {
+					return fmt.Errorf("failover relay: %w", err)
+				}
+				failoverRelays = append(failoverRelays, failoverRelay)
+			}
+		}
+	} else if cfg.FailoverRelayEndpoints != "" {
+		return errors.New("failover relays require a remote relay")
 	} else if localRelay != nil {
 		relay = localRelay
 	} else {
 		return errors.New("neither local nor remote relay specified")
 	}
 
-	ethereumService := NewEthereumService(backend)
+	relaySecretKeys, err := ParseRelaySecretKeys(cfg.RelaySecretKeys)
+	if err != nil {
+		return err
+	}
+	relayFees, err := ParseRelayFees(cfg.RelayFees)
+	if err != nil {
+		return err
+	}
+	if len(relaySecretKeys) > 0 {
+		relays := []IRelay{relay}
+		if multiRelay, ok := relay.(*MultiRelay); ok {
+			relays = multiRelay.relays
+		}
+		relays = append(relays, failoverRelays...)
+		ctx, cancel := context.WithTimeout(context.Background(), relayReachableTimeout)
+		warnings, err := CheckRelayKeysRegistered(ctx, relays, relaySecretKeys)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, warning := range warnings {
+			log.Warn("could not confirm the relay's builder key registration", "err", warning)
+		}
+	}
+
+	buildStrategy, err := ParseBuildStrategy(cfg.BuildStrategy)
+	if err != nil {
+		return err
+	}
+
+	ethOpts := EthereumServiceOptions{
+		Strategy:        buildStrategy,
+		ProfitBreakdown: cfg.ProfitBreakdown,
+		PinMempool:      cfg.PinMempool,
+		ClientVersion:   stack.Server().Config.Name,
+	}
+	if cfg.ProposerPaymentKey != "" {
+		paymentKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.ProposerPaymentKey, "0x"))
+		if err != nil {
+			return fmt.Errorf("invalid proposer payment key: %w", err)
+		}
+		etherbase, err := backend.Etherbase()
+		if err != nil {
+			return fmt.Errorf("proposer payment requires an etherbase: %w", err)
+		}
+		if address := crypto.PubkeyToAddress(paymentKey.PublicKey); address != etherbase {
+			return fmt.Errorf("proposer payment key address %s is not the etherbase %s", address, etherbase)
+		}
+		ethOpts.ProposerPaymentKey = paymentKey
+	}
+	if cfg.ExtraData != "" {
+		extraDataProvider, err := NewExtraDataRotation(strings.Split(cfg.ExtraData, ","))
+		if err != nil {
+			return fmt.Errorf("invalid extra data: %w", err)
+		}
+		ethOpts.ExtraDataProvider = extraDataProvider
+	}
+	ethereumService := NewEthereumServiceWithOptions(backend, ethOpts)
+
+	notSyncedPolicy, err := ParseNotSyncedPolicy(cfg.NotSyncedPolicy)
+	if err != nil {
+		return err
+	}
+	slotMismatchPolicy, err := ParseSlotMismatchPolicy(cfg.SlotMismatchPolicy)
+	if err != nil {
+		return err
+	}
+	beaconPolicy, err := ParseBeaconPolicy(cfg.BeaconPolicy)
+	if err != nil {
+		return err
+	}
+	var maintenanceWindows []MaintenanceWindow
+	if cfg.MaintenanceWindows != "" {
+		maintenanceWindows, err = ParseMaintenanceWindows(cfg.MaintenanceWindows)
+		if err != nil {
+			return err
+		}
+	}
+	if notSyncedPolicy == NotSyncedFallback {
+		return errors.New("not synced fallback policy requires a fallback EL, which is not available in the node")
+	}
+	devNetwork, err := IsDevNetwork(backend.BlockChain().Config().ChainID, cfg.DevChainIDs)
+	if err != nil {
+		return err
+	}
+	prevRandaoOverride, err := ParsePrevRandao(cfg.DevPrevRandao)
+	if err != nil {
+		return err
+	}
+	if prevRandaoOverride != nil {
+		log.Warn("overriding the prevRandao of the built blocks, for development networks only", "prev_randao", *prevRandaoOverride)
+	}
+
+	builderOpts := BuilderOptions{
+		AllowOverlappingBuilds:     cfg.AllowOverlappingBuilds,
+		NotSyncedPolicy:            notSyncedPolicy,
+		NotSyncedMaxWait:           cfg.NotSyncedMaxWait,
+		RegistrationCheckInterval:  cfg.RegistrationCheckInterval,
+		SingleShot:                 cfg.SingleShot,
+		Reconcile:                  cfg.Reconcile,
+		SelfDrivenBuilds:           cfg.SelfDrivenBuilds,
+		SelfDrivenBuildDelay:       cfg.SelfDrivenBuildDelay,
+		MaxConcurrentBuilds:        cfg.MaxConcurrentBuilds,
+		RelayLatencySLA:            cfg.RelayLatencySLA,
+		RelayLatencySLAWindow:      cfg.RelayLatencySLAWindow,
+		MaxRegistrationAge:         cfg.MaxRegistrationAge,
+		SimulateBlocks:             cfg.SimulateBlocks,
+		GasLimitSmoothing:          cfg.GasLimitSmoothing,
+		ResubmitWorkers:            cfg.ResubmitWorkers,
+		MissedSlotsThreshold:       cfg.MissedSlotsThreshold,
+		RelaySecretKeys:            relaySecretKeys,
+		RelayFees:                  relayFees,
+		FailoverRelays:             failoverRelays,
+		SlotMismatchPolicy:         slotMismatchPolicy,
+		SlotMismatchTolerance:      cfg.SlotMismatchTolerance,
+		TimestampFlexibility:       cfg.TimestampFlexibility,
+		MaxSlotsAhead:              cfg.MaxSlotsAhead,
+		InitialSubmissionDelay:     cfg.InitialSubmissionDelay,
+		Observer:                   cfg.Observer,
+		SpeculativeBuilds:          cfg.SpeculativeBuilds,
+		BeaconPolicy:               beaconPolicy,
+		MaintenanceWindows:         maintenanceWindows,
+		AllRelaysRejectedPause:     cfg.AllRelaysRejectedPause,
+		MaxSubmitBytesPerSlot:      cfg.MaxSubmitBytesPerSlot,
+		SigningDomainCheckInterval: cfg.SigningDomainCheckInterval,
+		CircuitBreakerThreshold:    cfg.CircuitBreakerThreshold,
+		CircuitBreakerCooldown:     cfg.CircuitBreakerCooldown,
+		MaxCandidateHeads:          cfg.MaxCandidateHeads,
+		DevNetwork:                 devNetwork,
+		CanarySubmissions:          cfg.CanarySubmissions,
+		PrevRandaoOverride:         prevRandaoOverride,
+	}
+	if cfg.LazyBuild {
+		lazyBuild := &LazyBuildPolicy{MaxDeficitBps: cfg.LazyBuildMaxDeficitBps}
+		if cfg.LazyBuildMaxDeficit != "" {
+			maxDeficit, ok := new(big.Int).SetString(cfg.LazyBuildMaxDeficit, 10)
+			if !ok || maxDeficit.Sign() < 0 {
+				return fmt.Errorf("invalid lazy build max deficit %q", cfg.LazyBuildMaxDeficit)
+			}
+			lazyBuild.MaxDeficit = maxDeficit
+		}
+		builderOpts.LazyBuild = lazyBuild
+	}
+
+	if cfg.BidValueReserve != "" {
+		reserve, ok := new(big.Int).SetString(cfg.BidValueReserve, 10)
+		if !ok || reserve.Sign() < 0 {
+			return fmt.Errorf("invalid bid value reserve %q", cfg.BidValueReserve)
+		}
+		builderOpts.BidValueStrategy = ReserveBidValue{Reserve: reserve}
+	}
+
+	if cfg.RecordAttributesPath != "" {
+		recorder, err := NewAttributesRecorder(cfg.RecordAttributesPath)
+		if err != nil {
+			return fmt.Errorf("could not open attributes record file: %w", err)
+		}
+		builderOpts.AttributesRecorder = recorder
+	}
+
+	if cfg.DumpDir != "" {
+		dumper, err := NewBlockDumper(cfg.DumpDir, cfg.DumpSlots)
+		if err != nil {
+			return fmt.Errorf("could not create block dump directory: %w", err)
+		}
+		builderOpts.BlockDumper = dumper
+	}
+	if cfg.ArchiveDir != "" {
+		archive, err := NewFileArchive(cfg.ArchiveDir)
+		if err != nil {
+			return fmt.Errorf("could not create archive directory: %w", err)
+		}
+		builderOpts.ArchiveSink = archive
+	}
+	if cfg.SubmissionWebhookURL != "" {
+		builderOpts.Webhook = NewWebhookNotifier(cfg.SubmissionWebhookURL, 0)
+	}
+	if cfg.AllRelaysRejectedWebhookURL != "" {
+		builderOpts.AllRelaysRejectedWebhook = NewWebhookNotifier(cfg.AllRelaysRejectedWebhookURL, 0)
+	}
+
+	if err := WarmUpSigning(builderSk, builderSigningDomain); err != nil {
+		return fmt.Errorf("could not warm up BLS signing: %w", err)
+	}
 
-	builderBackend := NewBuilder(builderSk, beaconClient, relay, builderSigningDomain, ethereumService)
+	builderBackend, err := NewBuilderFromConfig(Config{
+		SecretKey:      builderSk,
+		BeaconClient:   beaconClient,
+		Relay:          relay,
+		SigningDomain:  builderSigningDomain,
+		Eth:            ethereumService,
+		BuilderOptions: builderOpts,
+	})
+	if err != nil {
+		return err
+	}
 	builderService := NewService(cfg.ListenAddr, localRelay, builderBackend)
-	builderService.Start()
+	if cfg.EnablePprof {
+		builderService.enablePprof(cfg.PprofAddr)
+	}
+	stack.RegisterLifecycle(builderService)
+
+	for _, extension := range cfg.Extensions {
+		if err := extension(stack, builderBackend); err != nil {
+			return err
+		}
+	}
 
 	stack.RegisterAPIs([]rpc.API{
 		{
 			Namespace:     "builder",
 			Version:       "1.0",
-			Service:       builderService,
+			Service:       NewBuilderAPI(builderBackend),
 			Public:        true,
 			Authenticated: true,
 		},
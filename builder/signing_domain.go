@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+const signingDomainRequestTimeout = 5 * time.Second
+
+// GenesisForkVersionReporter is implemented by the beacon clients serving the network's genesis fork version, which
+// the relays derive the builder signing domain from.
+type GenesisForkVersionReporter interface {
+	GetGenesisForkVersion(ctx context.Context) (boostTypes.ForkVersion, error)
+}
+
+func (b *Builder) runSigningDomainCheck(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		b.checkSigningDomain()
+		<-ticker.C
+	}
+}
+
+// checkSigningDomain compares the builder signing domain with the domain of the genesis fork version the beacon node
+// serves. The relays verify the submissions against the network's domain, the relays publishing no endpoint for it:
+// a mismatch, typically a builder configured for another network, gets every submission rejected. It is logged at
+// error level and counted on every check until fixed.
+func (b *Builder) checkSigningDomain() {
+	reporter, ok := b.beaconClient.(GenesisForkVersionReporter)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), signingDomainRequestTimeout)
+	defer cancel()
+	forkVersion, err := reporter.GetGenesisForkVersion(ctx)
+	if err != nil {
+		log.Warn("could not get the genesis fork version from the beacon node", "err", err)
+		return
+	}
+
+	domain := boostTypes.ComputeDomain(boostTypes.DomainTypeAppBuilder, forkVersion, boostTypes.Root{})
+	if domain == b.builderSigningDomain {
+		return
+	}
+	log.Error("builder signing domain differs from the network's, all submissions will be rejected",
+		"builder_domain", hexutil.Encode(b.builderSigningDomain[:]), "network_domain", hexutil.Encode(domain[:]),
+		"genesis_fork_version", hexutil.Encode(forkVersion[:]))
+	signingDomainMismatchCounter.Inc(1)
+}
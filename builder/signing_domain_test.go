@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSigningDomain(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	beaconClient := builder.beaconClient.(*testBeaconClient)
+
+	var (
+		mu         sync.Mutex
+		mismatches int
+	)
+	handler := log.Root().GetHandler()
+	log.Root().SetHandler(log.FuncHandler(func(r *log.Record) error {
+		if r.Lvl == log.LvlError && r.Msg == "builder signing domain differs from the network's, all submissions will be rejected" {
+			mu.Lock()
+			mismatches++
+			mu.Unlock()
+		}
+		return nil
+	}))
+	t.Cleanup(func() { log.Root().SetHandler(handler) })
+	countMismatches := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return mismatches
+	}
+
+	// Unreachable genesis fork version, nothing to compare with
+	builder.checkSigningDomain()
+	require.Zero(t, countMismatches())
+
+	builder.builderSigningDomain = boostTypes.ComputeDomain(boostTypes.DomainTypeAppBuilder, boostTypes.ForkVersion{0x02}, boostTypes.Root{})
+	beaconClient.genesisForkVersion = &boostTypes.ForkVersion{0x02}
+	builder.checkSigningDomain()
+	require.Zero(t, countMismatches())
+
+	// Builder configured for another network, reported on every check
+	beaconClient.genesisForkVersion = &boostTypes.ForkVersion{0x01, 0x01, 0x70, 0x00}
+	builder.checkSigningDomain()
+	builder.checkSigningDomain()
+	require.Equal(t, 2, countMismatches())
+}
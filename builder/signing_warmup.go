@@ -0,0 +1,35 @@
+package builder
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// WarmUpSigning signs and verifies a throwaway bid trace with the builder key, so that the lazy initialization of
+// the BLS library happens at startup rather than on the first slot's submission.
+func WarmUpSigning(sk *bls.SecretKey, builderSigningDomain boostTypes.Domain) error {
+	start := time.Now()
+
+	var pk boostTypes.PublicKey
+	pk.FromSlice(bls.PublicKeyFromSecretKey(sk).Compress())
+	msg := &boostTypes.BidTrace{BuilderPubkey: pk}
+
+	signature, err := boostTypes.SignMessage(msg, builderSigningDomain, sk)
+	if err != nil {
+		return err
+	}
+	ok, err := boostTypes.VerifySignature(msg, builderSigningDomain, pk[:], signature[:])
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("could not verify the warm-up signature")
+	}
+
+	log.Info("warmed up BLS signing", "duration", time.Since(start))
+	return nil
+}
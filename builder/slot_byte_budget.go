@@ -0,0 +1,134 @@
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// ErrSlotByteBudgetExceeded is returned for the submissions not sent as they would exceed the slot's byte budget
+var ErrSlotByteBudgetExceeded = errors.New("slot submission byte budget exceeded")
+
+// codecRelay is implemented by the relays encoding the submissions with a codec of their own, the other relays are
+// sent the submissions as JSON.
+type codecRelay interface {
+	submissionCodec() Codec
+}
+
+// slotByteBudget bounds the bytes submitted per slot across the relays. The relays are submitted to in their
+// configured order, so the earlier relays are the last to go over the budget.
+type slotByteBudget struct {
+	mu    sync.Mutex
+	max   uint64
+	spent map[uint64]*slotSpending
+}
+
+// slotSpending are the bytes spent of a slot's budget, and the blocks they were spent on by relay.
+type slotSpending struct {
+	bytes   uint64
+	charged map[chargedSubmission]struct{}
+}
+
+type chargedSubmission struct {
+	relay     IRelay
+	blockHash boostTypes.Hash
+}
+
+func newSlotByteBudget(max uint64) *slotByteBudget {
+	return &slotByteBudget{max: max, spent: make(map[uint64]*slotSpending)}
+}
+
+// reserve spends the submission's size in the relay's encoding of its slot's budget, returning
+// ErrSlotByteBudgetExceeded without spending anything if the submission would exceed it. A block already charged to
+// the relay, such as a retried submission, is not charged again. Unbounded if the budget is zero.
+func (s *slotByteBudget) reserve(relay IRelay, req *boostTypes.BuilderSubmitBlockRequest) error {
+	if s.max == 0 {
+		return nil
+	}
+	size, err := submissionSize(relay, req)
+	if err != nil {
+		return err
+	}
+	charge := chargedSubmission{relay: relay, blockHash: req.Message.BlockHash}
+	slot := req.Message.Slot
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	spending, found := s.spent[slot]
+	if !found {
+		spending = &slotSpending{charged: make(map[chargedSubmission]struct{})}
+		s.spent[slot] = spending
+	}
+	if _, charged := spending.charged[charge]; charged {
+		return nil
+	}
+	if spending.bytes+size > s.max {
+		slotByteBudgetExceededCounter.Inc(1)
+		name, _ := relayIdentity(relay)
+		log.Debug("skipping relay, slot byte budget exceeded", "relay", name, "slot", slot, "spent", spending.bytes, "size", size, "budget", s.max)
+		return fmt.Errorf("%w: %d bytes spent of %d, submission is %d bytes", ErrSlotByteBudgetExceeded, spending.bytes, s.max, size)
+	}
+	spending.bytes += size
+	spending.charged[charge] = struct{}{}
+	return nil
+}
+
+// submissionSize returns the size of the submission encoded with the relay's codec. The SSZ and JSON sizes are
+// computed from the transactions' lengths, without encoding them.
+func submissionSize(relay IRelay, req *boostTypes.BuilderSubmitBlockRequest) (uint64, error) {
+	var codec Codec = JSONCodec{}
+	if r, ok := relay.(codecRelay); ok {
+		codec = r.submissionCodec()
+	}
+	switch codec.(type) {
+	case SSZCodec:
+		if req.ExecutionPayload != nil {
+			return uint64(submitBlockRequestSSZFixedSize + executionPayloadSSZSize(req.ExecutionPayload)), nil
+		}
+	case JSONCodec:
+		if req.ExecutionPayload != nil && len(req.ExecutionPayload.Transactions) > 0 {
+			return jsonSubmissionSize(req)
+		}
+	}
+	data, _, err := codec.Encode(req)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(len(data)), nil
+}
+
+// jsonSubmissionSize returns the size of the submission's JSON encoding: the submission is encoded without its
+// transactions, which are each a quoted 0x-prefixed hex string in the list.
+func jsonSubmissionSize(req *boostTypes.BuilderSubmitBlockRequest) (uint64, error) {
+	payload := *req.ExecutionPayload
+	payload.Transactions = []hexutil.Bytes{}
+	withoutTxs := *req
+	withoutTxs.ExecutionPayload = &payload
+	data, err := json.Marshal(&withoutTxs)
+	if err != nil {
+		return 0, err
+	}
+
+	size := uint64(len(data)) + uint64(len(req.ExecutionPayload.Transactions)-1)
+	for _, tx := range req.ExecutionPayload.Transactions {
+		size += uint64(2*len(tx) + len(`"0x"`))
+	}
+	return size, nil
+}
+
+func (s *slotByteBudget) pruneBefore(slot uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for spentSlot := range s.spent {
+		if spentSlot < slot {
+			delete(s.spent, spentSlot)
+		}
+	}
+}
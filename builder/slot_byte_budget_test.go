@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+type sszTestRelay struct {
+	*testRelay
+}
+
+func (sszTestRelay) submissionCodec() Codec {
+	return SSZCodec{}
+}
+
+func TestSlotByteBudget(t *testing.T) {
+	eth := newTestEthereumService()
+	// A large block, a submission serializes to over 100kB
+	eth.testExecutableData.Transactions = [][]byte{make([]byte, 50_000)}
+	builder, first := newTestBuilderWithOptions(t, eth, BuilderOptions{SingleShot: true, MaxSubmitBytesPerSlot: 250_000})
+	second, third := &testRelay{validator: first.validator}, &testRelay{validator: first.validator}
+	builder.relay = NewMultiRelay([]IRelay{first, second, third}, ValidatorConflictRecent)
+
+	// The budget fits two submissions, the first relays get them
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.NotNil(t, first.submittedMsg)
+	require.NotNil(t, second.submittedMsg)
+	require.Nil(t, third.submittedMsg)
+
+	// A submission over the budget is not sent to any relay
+	submitted := *first.submittedMsg
+	submitted.Message = &boostTypes.BidTrace{Slot: 25, BlockHash: boostTypes.Hash{0x01}}
+	first.submittedMsg, second.submittedMsg = nil, nil
+	require.ErrorIs(t, builder.submitBlock("over-budget", "", nil, &submitted, 25), ErrSlotByteBudgetExceeded)
+	require.Nil(t, first.submittedMsg)
+
+	// The next slot has its own budget
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(26)))
+	require.NotNil(t, first.submittedMsg)
+	require.NotNil(t, second.submittedMsg)
+	require.Nil(t, third.submittedMsg)
+}
+
+func TestSlotByteBudgetSubmissionSize(t *testing.T) {
+	req := newTestSubmitBlockRequest(t, []hexutil.Bytes{make([]byte, 50_000)})
+	jsonRelay, sszRelay := &testRelay{}, sszTestRelay{&testRelay{}}
+
+	// The relays are charged the size of their encoding, the JSON hex doubling the transactions' size
+	jsonSize, err := submissionSize(jsonRelay, req)
+	require.NoError(t, err)
+	sszSize, err := submissionSize(sszRelay, req)
+	require.NoError(t, err)
+	encoded, _, err := SSZCodec{}.Encode(req)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(encoded)), sszSize)
+	require.Greater(t, jsonSize, 2*sszSize-1_000)
+
+	// The JSON size is computed without encoding the transactions, to the byte
+	for _, txs := range [][]hexutil.Bytes{nil, {}, {{0x01}}, {make([]byte, 50_000), {0x02, 0xab}, {0x03}}} {
+		txsReq := newTestSubmitBlockRequest(t, txs)
+		encoded, err := json.Marshal(txsReq)
+		require.NoError(t, err)
+		size, err := submissionSize(jsonRelay, txsReq)
+		require.NoError(t, err)
+		require.Equal(t, uint64(len(encoded)), size)
+	}
+
+	budget := newSlotByteBudget(jsonSize + sszSize)
+	require.NoError(t, budget.reserve(jsonRelay, req))
+	require.NoError(t, budget.reserve(sszRelay, req))
+	// The retries of a block are not charged again, unlike another block
+	require.NoError(t, budget.reserve(jsonRelay, req))
+	require.NoError(t, budget.reserve(sszRelay, req))
+	other := *req
+	other.Message = &boostTypes.BidTrace{Slot: req.Message.Slot, BlockHash: boostTypes.Hash{0x01}}
+	require.ErrorIs(t, budget.reserve(sszRelay, &other), ErrSlotByteBudgetExceeded)
+}
@@ -0,0 +1,85 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	ErrSlotMismatch = errors.New("attributes slot does not match their timestamp")
+	// ErrSlotTooFarAhead is returned for attributes of a slot more than the maximum number of slots ahead of the
+	// current slot
+	ErrSlotTooFarAhead = errors.New("attributes slot too far ahead of the current slot")
+)
+
+// SlotMismatchPolicy selects what the builder does with payload attributes whose slot disagrees with the slot
+// derived from their timestamp.
+type SlotMismatchPolicy string
+
+const (
+	// SlotMismatchWarn logs the mismatch and builds the attributes
+	SlotMismatchWarn SlotMismatchPolicy = "warn"
+	// SlotMismatchReject drops the attributes
+	SlotMismatchReject SlotMismatchPolicy = "reject"
+)
+
+func ParseSlotMismatchPolicy(policy string) (SlotMismatchPolicy, error) {
+	switch SlotMismatchPolicy(policy) {
+	case "":
+		return SlotMismatchWarn, nil
+	case SlotMismatchWarn, SlotMismatchReject:
+		return SlotMismatchPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unknown slot mismatch policy %q", policy)
+	}
+}
+
+// timestampSlot returns the slot of the timestamp, false if it is before genesis or the genesis time is unknown.
+func (b *Builder) timestampSlot(timestamp uint64) (uint64, bool) {
+	if b.genesisTime == 0 || timestamp < b.genesisTime {
+		return 0, false
+	}
+	return (timestamp - b.genesisTime) / b.secondsPerSlot, true
+}
+
+// checkAttributesSlot cross-checks the attributes' slot with the slot derived from their timestamp, which the relay
+// checks the block against. Mismatches beyond the tolerance are handled by the slot mismatch policy.
+func (b *Builder) checkAttributesSlot(attrs *BuilderPayloadAttributes) error {
+	expected, ok := b.timestampSlot(uint64(attrs.Timestamp))
+	if !ok {
+		return nil
+	}
+
+	diff := expected - attrs.Slot
+	if attrs.Slot > expected {
+		diff = attrs.Slot - expected
+	}
+	if diff <= b.slotMismatchTolerance {
+		return nil
+	}
+
+	if b.slotMismatchPolicy == SlotMismatchReject {
+		log.Info("dropping payload attributes, the slot does not match the timestamp", "slot", attrs.Slot, "timestamp", attrs.Timestamp, "timestamp_slot", expected)
+		return fmt.Errorf("%w: slot %d, timestamp of slot %d", ErrSlotMismatch, attrs.Slot, expected)
+	}
+	log.Warn("payload attributes slot does not match the timestamp", "slot", attrs.Slot, "timestamp", attrs.Timestamp, "timestamp_slot", expected)
+	return nil
+}
+
+// checkSlotAhead drops the attributes of a slot more than maxSlotsAhead slots after the current slot at now, whose
+// block would be stale by the time the slot starts.
+func (b *Builder) checkSlotAhead(attrs *BuilderPayloadAttributes, now time.Time) error {
+	if b.maxSlotsAhead == 0 {
+		return nil
+	}
+	current, ok := b.timestampSlot(uint64(now.Unix()))
+	if !ok || attrs.Slot <= current+b.maxSlotsAhead {
+		return nil
+	}
+
+	log.Info("dropping payload attributes, the slot is too far ahead", "slot", attrs.Slot, "current_slot", current, "max_slots_ahead", b.maxSlotsAhead)
+	return fmt.Errorf("%w: slot %d, current slot %d", ErrSlotTooFarAhead, attrs.Slot, current)
+}
@@ -0,0 +1,68 @@
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAttributesSlot(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SlotMismatchTolerance: 1})
+	builder.genesisTime = 1000
+	builder.secondsPerSlot = 12
+
+	require.NoError(t, builder.checkAttributesSlot(newTestAttributes(25)))
+
+	attrs := newTestAttributes(25)
+	attrs.Slot = 26
+	require.NoError(t, builder.checkAttributesSlot(attrs))
+
+	// Mismatches are only logged by default
+	attrs.Slot = 23
+	require.NoError(t, builder.checkAttributesSlot(attrs))
+
+	builder.slotMismatchPolicy = SlotMismatchReject
+	require.ErrorIs(t, builder.checkAttributesSlot(attrs), ErrSlotMismatch)
+	require.ErrorIs(t, builder.OnPayloadAttribute(attrs), ErrSlotMismatch)
+	require.Nil(t, testRelay.submittedMsg)
+
+	// Attributes are not checked before genesis
+	attrs.Timestamp = 900
+	require.NoError(t, builder.checkAttributesSlot(attrs))
+}
+
+func TestCheckSlotAhead(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{MaxSlotsAhead: 2})
+	builder.genesisTime = 1000
+	builder.secondsPerSlot = 12
+
+	// Slot 10 is current until its last second
+	now := time.Unix(1000+10*12+11, 0)
+	require.NoError(t, builder.checkSlotAhead(newTestAttributes(11), now))
+	require.NoError(t, builder.checkSlotAhead(newTestAttributes(12), now))
+	require.ErrorIs(t, builder.checkSlotAhead(newTestAttributes(13), now), ErrSlotTooFarAhead)
+	require.NoError(t, builder.checkSlotAhead(newTestAttributes(13), now.Add(time.Second)))
+
+	// The attributes are dropped before building
+	require.ErrorIs(t, builder.OnPayloadAttribute(newTestAttributes(1<<40)), ErrSlotTooFarAhead)
+	require.Nil(t, testRelay.submittedMsg)
+
+	// Not checked before genesis or when disabled
+	require.NoError(t, builder.checkSlotAhead(newTestAttributes(13), time.Unix(900, 0)))
+	builder.maxSlotsAhead = 0
+	require.NoError(t, builder.checkSlotAhead(newTestAttributes(13), now))
+}
+
+func TestParseSlotMismatchPolicy(t *testing.T) {
+	policy, err := ParseSlotMismatchPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, SlotMismatchWarn, policy)
+
+	policy, err = ParseSlotMismatchPolicy("reject")
+	require.NoError(t, err)
+	require.Equal(t, SlotMismatchReject, policy)
+
+	_, err = ParseSlotMismatchPolicy("drop")
+	require.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package builder
+
+import (
+	"sync"
+)
+
+// historySlots is the number of most recent slots whose submissions are kept, covering the cancellation of the
+// previous slot's bids and the reconciliation attempts of the won blocks
+const historySlots = 2 * (reconcileDelaySlots + maxReconcileAttempts)
+
+// slotCleanup prunes a per-slot state of the slots before the given slot.
+type slotCleanup struct {
+	name string
+	// retain is the number of slots before the latest slot whose state is kept
+	retain uint64
+	prune  func(before uint64)
+}
+
+// slotLifecycle cleans up all the per-slot state in one place once the builder moves on to a later slot, bounding
+// the memory of the state kept by slot.
+type slotLifecycle struct {
+	mu       sync.Mutex
+	latest   uint64
+	cleanups []slotCleanup
+}
+
+// register adds the cleanup of a per-slot state keeping the retain slots before the latest slot.
+func (l *slotLifecycle) register(name string, retain uint64, prune func(before uint64)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cleanups = append(l.cleanups, slotCleanup{name: name, retain: retain, prune: prune})
+}
+
+// advance moves to the slot, pruning the state of the slots no longer retained. Slots not after the latest slot
+// don't prune anything.
+func (l *slotLifecycle) advance(slot uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if slot <= l.latest {
+		return
+	}
+	l.latest = slot
+	for _, cleanup := range l.cleanups {
+		if slot > cleanup.retain {
+			cleanup.prune(slot - cleanup.retain)
+		}
+	}
+}
+
+// registerSlotCleanups registers the cleanups of the builder's per-slot state. The resubmission tasks and the
+// in-flight submissions end with their slot on their own.
+func (b *Builder) registerSlotCleanups() {
+	// Payloads of previous slots can no longer be proposed
+	b.slots.register("payloads", 0, b.payloads.Prune)
+	b.slots.register("submission history", historySlots, b.history.pruneBefore)
+	b.slots.register("speculative block", 0, b.dropSpeculationBefore)
+	b.slots.register("observed bids", observedSlots-1, b.observations.pruneBefore)
+	b.slots.register("submitted bytes", 0, b.byteBudget.pruneBefore)
+}
@@ -0,0 +1,60 @@
+package builder
+
+import (
+	"math/big"
+	"testing"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlotLifecycle(t *testing.T) {
+	var lifecycle slotLifecycle
+	var pruned []uint64
+	lifecycle.register("test", 2, func(before uint64) { pruned = append(pruned, before) })
+
+	lifecycle.advance(1)
+	lifecycle.advance(5)
+	// Slots not after the latest slot don't prune
+	lifecycle.advance(4)
+	lifecycle.advance(5)
+	lifecycle.advance(6)
+	require.Equal(t, []uint64{3, 4}, pruned)
+}
+
+func TestSlotLifecycleBoundsState(t *testing.T) {
+	ethService := newTestEthereumService()
+	builder, _ := newTestBuilderWithOptions(t, ethService, BuilderOptions{SingleShot: true})
+
+	const slots = 200
+	for slot := uint64(1); slot <= slots; slot++ {
+		// A block per slot, as the payloads and the submission history are keyed by block hash
+		executableData := *ethService.testExecutableData
+		executableData.BlockHash[0] = byte(slot)
+		executableData.BlockHash[1] = byte(slot >> 8)
+		ethService.testExecutableData = &executableData
+
+		builder.observations.record(ObservedBid{TopBid: TopBid{Value: big.NewInt(1)}, Relay: "relay", Slot: slot})
+		builder.speculationMu.Lock()
+		builder.speculation = &speculativeBlock{attrs: BuilderPayloadAttributes{Slot: slot}}
+		builder.speculationMu.Unlock()
+		require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(slot)))
+	}
+
+	// Only the latest slot's payloads can be proposed
+	require.Equal(t, 1, builder.payloads.Len())
+	_, found := builder.payloads.Get(boostTypes.Hash{byte(slots), byte(slots >> 8)})
+	require.True(t, found)
+	require.LessOrEqual(t, builder.history.Len(), historySlots+1)
+	require.Greater(t, builder.history.Len(), 1)
+
+	// The last speculation is for the latest slot, not yet pruned
+	builder.slots.advance(slots + 1)
+	builder.speculationMu.Lock()
+	require.Nil(t, builder.speculation)
+	builder.speculationMu.Unlock()
+
+	builder.observations.mu.Lock()
+	require.LessOrEqual(t, len(builder.observations.slots), observedSlots)
+	builder.observations.mu.Unlock()
+}
@@ -0,0 +1,137 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// markReceivedAttributes records that attributes were received for the slot.
+func (b *Builder) markReceivedAttributes(slot uint64) {
+	b.attrsMu.Lock()
+	defer b.attrsMu.Unlock()
+
+	if slot > b.lastAttrsSlot {
+		b.lastAttrsSlot = slot
+	}
+}
+
+func (b *Builder) receivedAttributes(slot uint64) bool {
+	b.attrsMu.Lock()
+	defer b.attrsMu.Unlock()
+
+	return b.lastAttrsSlot >= slot
+}
+
+func (b *Builder) runSlotTicker() {
+	if b.genesisTime == 0 {
+		log.Error("self-driven builds require the genesis time from the beacon node, disabled")
+		return
+	}
+
+	for {
+		slot, buildAt := b.nextSelfDrivenBuild(time.Now())
+		time.Sleep(time.Until(buildAt))
+
+		if err := b.selfDrivenBuild(slot); err != nil && err != errTaskSuperseded {
+			log.Info("self-driven build failed", "err", err, "slot", slot)
+		}
+	}
+}
+
+// nextSelfDrivenBuild returns the next slot to self-drive and when to build it, the selfDrivenDelay into the
+// preceding slot by which its payload attributes should have been received.
+func (b *Builder) nextSelfDrivenBuild(now time.Time) (slot uint64, buildAt time.Time) {
+	genesis := time.Unix(int64(b.genesisTime), 0)
+	if now.Before(genesis) {
+		return 1, genesis.Add(b.selfDrivenDelay)
+	}
+
+	currentSlot := uint64(now.Sub(genesis) / b.slotDuration())
+	buildAt = genesis.Add(time.Duration(currentSlot)*b.slotDuration() + b.selfDrivenDelay)
+	if !now.Before(buildAt) {
+		currentSlot++
+		buildAt = buildAt.Add(b.slotDuration())
+	}
+	return currentSlot + 1, buildAt
+}
+
+// selfDrivenBuild builds for the slot on the EL head, unless its payload attributes were received.
+// Attributes received for the slot later on replace the self-driven builds.
+func (b *Builder) selfDrivenBuild(slot uint64) error {
+	if b.receivedAttributes(slot) || b.Paused() {
+		return nil
+	}
+
+	head := b.eth.CurrentBlock()
+	if head == nil {
+		return errors.New("no head block")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), beaconStartupTimeout)
+	defer cancel()
+	randao, err := b.prevRandao(ctx, slot, head)
+	if err != nil {
+		return err
+	}
+
+	attrs := &BuilderPayloadAttributes{
+		Timestamp: hexutil.Uint64(b.genesisTime + slot*b.secondsPerSlot),
+		Random:    randao,
+		Slot:      slot,
+		HeadHash:  head.Hash(),
+	}
+
+	log.Info("no payload attributes received, building on the head", "slot", slot, "head", head.Hash(), "head_number", head.NumberU64())
+	// The builds without attributes do not record the attributes to submission latency
+	return b.buildForAttributes(attrs, time.Time{}, func() bool { return b.receivedAttributes(slot) })
+}
+
+// runMissedSlotsMonitor checks at the start of every slot whether its payload attributes were received, warning
+// about the feed outages once the attributes of missedSlotsThreshold consecutive slots are missing.
+func (b *Builder) runMissedSlotsMonitor() {
+	if b.genesisTime == 0 {
+		log.Error("missed slots detection requires the genesis time from the beacon node, disabled")
+		return
+	}
+
+	for {
+		slot, startsAt := b.nextSlotStart(time.Now())
+		time.Sleep(time.Until(startsAt))
+		b.checkMissedSlots(slot)
+	}
+}
+
+// nextSlotStart returns the next slot and its start time.
+func (b *Builder) nextSlotStart(now time.Time) (slot uint64, startsAt time.Time) {
+	genesis := time.Unix(int64(b.genesisTime), 0)
+	if now.Before(genesis) {
+		return 0, genesis
+	}
+
+	slot = uint64(now.Sub(genesis)/b.slotDuration()) + 1
+	return slot, genesis.Add(time.Duration(slot) * b.slotDuration())
+}
+
+// checkMissedSlots counts the slot as missed and warns if no payload attributes were received for it and the
+// preceding slots, past the threshold. Nothing is missed before the first attributes are received.
+func (b *Builder) checkMissedSlots(slot uint64) (missed uint64) {
+	b.attrsMu.Lock()
+	lastAttrsSlot := b.lastAttrsSlot
+	b.attrsMu.Unlock()
+
+	if lastAttrsSlot == 0 || lastAttrsSlot >= slot {
+		return 0
+	}
+	missed = slot - lastAttrsSlot
+	if missed < b.missedSlotsThreshold {
+		return 0
+	}
+
+	missedSlotsCounter.Inc(1)
+	log.Warn("no payload attributes received for consecutive slots, the attributes feed may be down", "slot", slot, "last_attributes_slot", lastAttrsSlot, "missed_slots", missed)
+	return missed
+}
@@ -0,0 +1,105 @@
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/beacon"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextSelfDrivenBuild(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	builder.genesisTime = 1000
+	builder.secondsPerSlot = 12
+	builder.selfDrivenDelay = 6 * time.Second
+
+	// Before genesis
+	slot, buildAt := builder.nextSelfDrivenBuild(time.Unix(900, 0))
+	require.Equal(t, uint64(1), slot)
+	require.Equal(t, time.Unix(1006, 0), buildAt)
+
+	// Before the delay into slot 10
+	slot, buildAt = builder.nextSelfDrivenBuild(time.Unix(1000+10*12+3, 0))
+	require.Equal(t, uint64(11), slot)
+	require.Equal(t, time.Unix(1000+10*12+6, 0), buildAt)
+
+	// Past the delay into slot 10
+	slot, buildAt = builder.nextSelfDrivenBuild(time.Unix(1000+10*12+6, 0))
+	require.Equal(t, uint64(12), slot)
+	require.Equal(t, time.Unix(1000+11*12+6, 0), buildAt)
+}
+
+type attrsRecordingEthereumService struct {
+	testEthereumService
+	attrs *BuilderPayloadAttributes
+}
+
+func (s *attrsRecordingEthereumService) BuildBlock(attrs *BuilderPayloadAttributes) (*beacon.ExecutableDataV1, *types.Block, *ProfitBreakdown) {
+	s.attrs = attrs
+	return s.testEthereumService.BuildBlock(attrs)
+}
+
+func TestSelfDrivenBuild(t *testing.T) {
+	testEthService := &attrsRecordingEthereumService{testEthereumService: *newTestEthereumService()}
+	builder, testRelay := newTestBuilderWithOptions(t, testEthService, BuilderOptions{})
+	builder.genesisTime = 1000
+	builder.secondsPerSlot = 12
+
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	testRelay.submittedMsg = nil
+
+	// Attributes were received for the slot
+	require.NoError(t, builder.selfDrivenBuild(25))
+	require.Nil(t, testRelay.submittedMsg)
+
+	// Self-driven builds are on the EL head
+	testEthService.testExecutableData.ParentHash = testEthService.testBlock.Hash()
+	require.NoError(t, builder.selfDrivenBuild(26))
+	require.NotNil(t, testRelay.submittedMsg)
+	require.Equal(t, uint64(26), testRelay.submittedMsg.Message.Slot)
+	require.Equal(t, hexutil.Uint64(1000+26*12), testEthService.attrs.Timestamp)
+	require.Equal(t, testEthService.testBlock.Hash(), testEthService.attrs.HeadHash)
+
+	// Attributes received while the self-driven build starts take over
+	err := builder.buildForAttributes(newTestAttributes(27), time.Time{}, func() bool {
+		builder.markReceivedAttributes(27)
+		return builder.receivedAttributes(27)
+	})
+	require.ErrorIs(t, err, errTaskSuperseded)
+}
+
+func TestNextSlotStart(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	builder.genesisTime = 1000
+	builder.secondsPerSlot = 12
+
+	slot, startsAt := builder.nextSlotStart(time.Unix(900, 0))
+	require.Equal(t, uint64(0), slot)
+	require.Equal(t, time.Unix(1000, 0), startsAt)
+
+	slot, startsAt = builder.nextSlotStart(time.Unix(1000+10*12, 0))
+	require.Equal(t, uint64(11), slot)
+	require.Equal(t, time.Unix(1000+11*12, 0), startsAt)
+}
+
+func TestCheckMissedSlots(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SingleShot: true})
+	builder.missedSlotsThreshold = 3
+
+	// Nothing is missed before the first attributes
+	require.Equal(t, uint64(0), builder.checkMissedSlots(25))
+
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.Equal(t, uint64(0), builder.checkMissedSlots(25))
+	require.Equal(t, uint64(0), builder.checkMissedSlots(27))
+
+	require.Equal(t, uint64(3), builder.checkMissedSlots(28))
+	require.Equal(t, uint64(4), builder.checkMissedSlots(29))
+
+	// The gap closes with the next attributes
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(30)))
+	require.Equal(t, uint64(0), builder.checkMissedSlots(30))
+}
@@ -0,0 +1,49 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var ErrTimestampOutOfRange = errors.New("timestamp out of the slot's valid range")
+
+// slotTimestampRange returns the first and last timestamps the relays accept for the slot's block, false if the
+// genesis time is unknown.
+func (b *Builder) slotTimestampRange(slot uint64) (uint64, uint64, bool) {
+	if b.genesisTime == 0 {
+		return 0, 0, false
+	}
+	first := b.genesisTime + slot*b.secondsPerSlot
+	return first, first + b.timestampFlexibility, true
+}
+
+// chooseTimestamp sets the attributes' timestamp to the latest of the slot's valid timestamps, which leaves the
+// most time for transactions to arrive, if the relays accept a range. Returns ErrTimestampOutOfRange if the
+// attributes' timestamp is outside of the range or no timestamp of the range is after the parent's.
+func (b *Builder) chooseTimestamp(attrs *BuilderPayloadAttributes, parentTime uint64) error {
+	if b.timestampFlexibility == 0 {
+		return nil
+	}
+	first, last, ok := b.slotTimestampRange(attrs.Slot)
+	if !ok {
+		log.Warn("genesis time unknown, building at the attributes' timestamp", "slot", attrs.Slot, "timestamp", attrs.Timestamp)
+		return nil
+	}
+
+	if timestamp := uint64(attrs.Timestamp); timestamp < first || timestamp > last {
+		log.Info("dropping payload attributes with a timestamp out of the slot's range", "slot", attrs.Slot, "timestamp", timestamp, "first", first, "last", last)
+		return fmt.Errorf("%w: %d not within [%d, %d]", ErrTimestampOutOfRange, timestamp, first, last)
+	}
+	if last <= parentTime {
+		return fmt.Errorf("%w: [%d, %d] not after the parent's %d", ErrTimestampOutOfRange, first, last, parentTime)
+	}
+
+	if uint64(attrs.Timestamp) != last {
+		log.Debug("building at the slot's latest valid timestamp", "slot", attrs.Slot, "timestamp", last, "attributes_timestamp", attrs.Timestamp)
+		attrs.Timestamp = hexutil.Uint64(last)
+	}
+	return nil
+}
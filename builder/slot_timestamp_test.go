@@ -0,0 +1,44 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChooseTimestamp(t *testing.T) {
+	builder, _ := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	builder.genesisTime = 1000
+	builder.secondsPerSlot = 12
+
+	// The attributes' timestamp is kept by default
+	attrs := newTestAttributes(25)
+	attrs.Timestamp++
+	require.NoError(t, builder.chooseTimestamp(attrs, 0))
+	require.Equal(t, hexutil.Uint64(1301), attrs.Timestamp)
+
+	builder.timestampFlexibility = 2
+	first, last, ok := builder.slotTimestampRange(25)
+	require.True(t, ok)
+	require.Equal(t, uint64(1300), first)
+	require.Equal(t, uint64(1302), last)
+
+	attrs = newTestAttributes(25)
+	require.NoError(t, builder.chooseTimestamp(attrs, 1288))
+	require.Equal(t, hexutil.Uint64(1302), attrs.Timestamp)
+
+	attrs = newTestAttributes(25)
+	attrs.Timestamp = 1303
+	require.ErrorIs(t, builder.chooseTimestamp(attrs, 1288), ErrTimestampOutOfRange)
+
+	// The block must be after its parent
+	attrs = newTestAttributes(25)
+	require.ErrorIs(t, builder.chooseTimestamp(attrs, 1302), ErrTimestampOutOfRange)
+
+	// The range is unknown without the genesis time
+	builder.genesisTime = 0
+	attrs = newTestAttributes(25)
+	require.NoError(t, builder.chooseTimestamp(attrs, 1288))
+	require.Equal(t, hexutil.Uint64(1300), attrs.Timestamp)
+}
@@ -0,0 +1,121 @@
+package builder
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/beacon"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// speculativeBlock is a block built ahead for the next slot on the current head, which is the next slot's block if
+// no block is proposed for the current slot. It is only valid for the attributes it was built for.
+type speculativeBlock struct {
+	attrs           BuilderPayloadAttributes
+	executableData  *beacon.ExecutableDataV1
+	block           *types.Block
+	profitBreakdown *ProfitBreakdown
+}
+
+// mismatch returns why the block is not valid for the attributes, empty if it is.
+func (s *speculativeBlock) mismatch(attrs *BuilderPayloadAttributes) string {
+	switch {
+	case attrs.HeadHash != s.attrs.HeadHash:
+		return "head changed"
+	case attrs.Timestamp != s.attrs.Timestamp:
+		return "timestamp changed"
+	case attrs.Random != s.attrs.Random:
+		return "prevRandao changed"
+	case attrs.SuggestedFeeRecipient != s.attrs.SuggestedFeeRecipient:
+		return "fee recipient changed"
+	case attrs.GasLimit != s.attrs.GasLimit:
+		return "gas limit changed"
+	case attrs.BuildParams != nil:
+		return "build params set"
+	}
+	return ""
+}
+
+// buildSpeculative builds a block for the slot after the attributes' on the same head, until the deadline. The
+// prevRandao only changes with a block, so the attributes of the next slot are the same besides the timestamp and
+// the next slot's validator registration.
+func (b *Builder) buildSpeculative(eth IEthereumService, attrs *BuilderPayloadAttributes, parentGasLimit uint64, deadline time.Time) {
+	slot := attrs.Slot + 1
+	vd, err := b.relay.GetValidatorForSlot(slot)
+	if err != nil {
+		log.Debug("could not get validator of the speculative slot", "err", err, "slot", slot)
+		return
+	}
+
+	next := *attrs
+	next.Slot = slot
+	next.Timestamp = attrs.Timestamp + hexutil.Uint64(b.secondsPerSlot)
+	next.SuggestedFeeRecipient = common.Address(vd.FeeRecipient)
+	next.GasLimit = b.gasLimitForSlot(vd.GasLimit, parentGasLimit, slot)
+	next.BuildParams = nil
+
+	executableData, block, profitBreakdown, _, err := b.buildBlockWithRetry(eth, &next, deadline)
+	if err != nil {
+		log.Debug("could not build speculative block", "err", err, "slot", slot)
+		return
+	}
+
+	b.speculationMu.Lock()
+	defer b.speculationMu.Unlock()
+	if b.speculation != nil && b.speculation.attrs.Slot > slot {
+		return
+	}
+	b.speculation = &speculativeBlock{attrs: next, executableData: executableData, block: block, profitBreakdown: profitBreakdown}
+	log.Info("built speculative block", "slot", slot, "block_hash", block.Hash(), "head_hash", next.HeadHash)
+}
+
+// takeSpeculation returns the block speculatively built for the attributes' slot if it is valid for the attributes,
+// discarding it either way.
+func (b *Builder) takeSpeculation(attrs *BuilderPayloadAttributes) *speculativeBlock {
+	b.speculationMu.Lock()
+	speculation := b.speculation
+	if speculation == nil || speculation.attrs.Slot > attrs.Slot {
+		b.speculationMu.Unlock()
+		return nil
+	}
+	b.speculation = nil
+	b.speculationMu.Unlock()
+
+	if speculation.attrs.Slot < attrs.Slot {
+		return nil
+	}
+	if reason := speculation.mismatch(attrs); reason != "" {
+		speculativeDiscardedCounter.Inc(1)
+		log.Info("discarding speculative block", "slot", attrs.Slot, "block_hash", speculation.block.Hash(), "reason", reason)
+		return nil
+	}
+	return speculation
+}
+
+// dropSpeculationBefore drops the speculative block if it was built for a slot before the slot.
+func (b *Builder) dropSpeculationBefore(slot uint64) {
+	b.speculationMu.Lock()
+	defer b.speculationMu.Unlock()
+
+	if b.speculation != nil && b.speculation.attrs.Slot < slot {
+		b.speculation = nil
+	}
+}
+
+// submitSpeculation submits the block speculatively built for the attributes' slot if it is valid for them, ahead
+// of the slot's first build.
+func (b *Builder) submitSpeculation(eth IEthereumService, attrs *BuilderPayloadAttributes, proposerPubkey boostTypes.PublicKey, proposerFeeRecipient boostTypes.Address, proposerIndex *uint64) {
+	speculation := b.takeSpeculation(attrs)
+	if speculation == nil {
+		return
+	}
+	if err := b.onSealedBlock(eth, speculation.executableData, speculation.block, speculation.profitBreakdown, proposerPubkey, proposerFeeRecipient, proposerIndex, attrs.Slot); err != nil {
+		log.Error("could not submit speculative block", "err", err, "slot", attrs.Slot, "block_hash", speculation.block.Hash())
+		return
+	}
+	speculativeSubmittedCounter.Inc(1)
+	log.Info("submitted speculative block", "slot", attrs.Slot, "block_hash", speculation.block.Hash())
+}
@@ -0,0 +1,62 @@
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpeculativeBuilds(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SpeculativeBuilds: true, SingleShot: true})
+	speculation := func() *speculativeBlock {
+		builder.speculationMu.Lock()
+		defer builder.speculationMu.Unlock()
+		return builder.speculation
+	}
+
+	// The next slot is built on the same head once the slot's block is submitted
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.Eventually(t, func() bool { return speculation() != nil }, time.Second, 10*time.Millisecond)
+	require.Equal(t, uint64(26), speculation().attrs.Slot)
+	require.Equal(t, newTestAttributes(26).Timestamp, speculation().attrs.Timestamp)
+
+	// Submitted for the next slot's attributes
+	attrs := newTestAttributes(26)
+	attrs.GasLimit = speculation().attrs.GasLimit
+	builder.submitSpeculation(builder.eth, attrs, boostTypes.PublicKey{}, boostTypes.Address{}, nil)
+	require.Equal(t, uint64(26), testRelay.submittedMsg.Message.Slot)
+	require.Nil(t, speculation())
+
+	// Discarded when the next slot's attributes differ
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(26)))
+	require.Eventually(t, func() bool { return speculation() != nil }, time.Second, 10*time.Millisecond)
+	attrs = newTestAttributes(27)
+	attrs.GasLimit = speculation().attrs.GasLimit
+	attrs.HeadHash = common.Hash{0x04}
+	require.Nil(t, builder.takeSpeculation(attrs))
+	require.Nil(t, speculation())
+}
+
+func TestSpeculativeBlockMismatch(t *testing.T) {
+	speculation := &speculativeBlock{attrs: *newTestAttributes(26)}
+	require.Empty(t, speculation.mismatch(newTestAttributes(26)))
+
+	attrs := newTestAttributes(26)
+	attrs.Random = common.Hash{0x01}
+	require.Equal(t, "prevRandao changed", speculation.mismatch(attrs))
+
+	attrs = newTestAttributes(26)
+	attrs.Timestamp++
+	require.Equal(t, "timestamp changed", speculation.mismatch(attrs))
+
+	attrs = newTestAttributes(26)
+	attrs.SuggestedFeeRecipient = common.Address{0x01}
+	require.Equal(t, "fee recipient changed", speculation.mismatch(attrs))
+
+	attrs = newTestAttributes(26)
+	attrs.BuildParams = &BuildParams{}
+	require.Equal(t, "build params set", speculation.mismatch(attrs))
+}
@@ -0,0 +1,42 @@
+package builder
+
+import (
+	"context"
+	"time"
+)
+
+type submissionDeadlineKey struct{}
+
+func withSubmissionDeadline(ctx context.Context, deadline time.Time) context.Context {
+	return context.WithValue(ctx, submissionDeadlineKey{}, deadline)
+}
+
+// SubmissionDeadlineFromContext returns the deadline of the submission the context belongs to, the start of its
+// slot, if any.
+func SubmissionDeadlineFromContext(ctx context.Context) (time.Time, bool) {
+	deadline, ok := ctx.Value(submissionDeadlineKey{}).(time.Time)
+	return deadline, ok && !deadline.IsZero()
+}
+
+// startsWave reports whether the submission following the sent ones starts a new wave, the skipped relays aside.
+func (m *MultiRelay) startsWave(sent int) bool {
+	return m.batchSize > 0 && sent > 0 && sent%m.batchSize == 0
+}
+
+// waitStagger waits the stagger between two waves of submissions. The stagger is skipped if it would end past the
+// submission's deadline, so that the later waves are still sent in time.
+func (m *MultiRelay) waitStagger(ctx context.Context) {
+	if m.stagger <= 0 {
+		return
+	}
+	if deadline, ok := SubmissionDeadlineFromContext(ctx); ok && time.Now().Add(m.stagger).After(deadline) {
+		return
+	}
+
+	timer := time.NewTimer(m.stagger)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,63 @@
+package builder
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+type timedRelay struct {
+	*testRelay
+	mu          sync.Mutex
+	submittedAt time.Time
+}
+
+func (r *timedRelay) SubmitBlock(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) error {
+	r.mu.Lock()
+	r.submittedAt = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *timedRelay) submitted() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.submittedAt
+}
+
+func TestMultiRelaySubmissionBatching(t *testing.T) {
+	const stagger = 50 * time.Millisecond
+	relays := make([]*timedRelay, 5)
+	iRelays := make([]IRelay, len(relays))
+	for i := range relays {
+		relays[i] = &timedRelay{testRelay: &testRelay{}}
+		iRelays[i] = relays[i]
+	}
+	relay := NewMultiRelayWithOptions(iRelays, MultiRelayOptions{SubmissionBatchSize: 2, SubmissionStagger: stagger})
+
+	// The skipped relay is not part of a wave
+	msg := &boostTypes.BuilderSubmitBlockRequest{}
+	start := time.Now()
+	result := relay.submitBlockTo(context.Background(), func(r IRelay) (*boostTypes.BuilderSubmitBlockRequest, error) {
+		if r == relays[1] {
+			return nil, ErrRelayDisabled
+		}
+		return msg, nil
+	})
+	require.True(t, result.Submitted())
+	require.Less(t, relays[2].submitted().Sub(start), stagger)
+	require.GreaterOrEqual(t, relays[3].submitted().Sub(start), stagger)
+	require.Less(t, relays[4].submitted().Sub(relays[3].submitted()), stagger)
+	require.True(t, relays[1].submitted().IsZero())
+
+	// The waves go out at once rather than past the deadline
+	ctx := withSubmissionDeadline(context.Background(), time.Now().Add(stagger/2))
+	start = time.Now()
+	result = relay.submitBlockTo(ctx, func(IRelay) (*boostTypes.BuilderSubmitBlockRequest, error) { return msg, nil })
+	require.True(t, result.Submitted())
+	require.Less(t, relays[4].submitted().Sub(start), stagger)
+}
@@ -0,0 +1,48 @@
+package builder
+
+import (
+	"errors"
+	"sync"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// ErrSubmissionInFlight is returned when the block is already being submitted to the relay
+var ErrSubmissionInFlight = errors.New("block submission already in flight")
+
+type inFlightKey struct {
+	relay     IRelay
+	blockHash boostTypes.Hash
+}
+
+// inFlightSubmissions is the set of blocks being submitted to each relay, which suppresses the concurrent
+// submissions of the same block by the tasks of overlapping builds. A block can be submitted again once
+// its in-flight submission completed.
+type inFlightSubmissions struct {
+	mu   sync.Mutex
+	keys map[inFlightKey]struct{}
+}
+
+func newInFlightSubmissions() *inFlightSubmissions {
+	return &inFlightSubmissions{keys: make(map[inFlightKey]struct{})}
+}
+
+// start adds the block to the set, returning false if it is already in flight to the relay.
+func (s *inFlightSubmissions) start(relay IRelay, blockHash boostTypes.Hash) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := inFlightKey{relay, blockHash}
+	if _, found := s.keys[key]; found {
+		return false
+	}
+	s.keys[key] = struct{}{}
+	return true
+}
+
+func (s *inFlightSubmissions) done(relay IRelay, blockHash boostTypes.Hash) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, inFlightKey{relay, blockHash})
+}
@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingRelay holds the submissions until released
+type blockingRelay struct {
+	*testRelay
+	release   chan struct{}
+	submitted int32
+}
+
+func (r *blockingRelay) SubmitBlock(ctx context.Context, msg *boostTypes.BuilderSubmitBlockRequest) error {
+	atomic.AddInt32(&r.submitted, 1)
+	<-r.release
+	return nil
+}
+
+func TestInFlightSubmissions(t *testing.T) {
+	inFlight := newInFlightSubmissions()
+	relayA, relayB := &testRelay{}, &testRelay{}
+
+	require.True(t, inFlight.start(relayA, boostTypes.Hash{0x01}))
+	require.False(t, inFlight.start(relayA, boostTypes.Hash{0x01}))
+	// Other blocks and relays are independent
+	require.True(t, inFlight.start(relayA, boostTypes.Hash{0x02}))
+	require.True(t, inFlight.start(relayB, boostTypes.Hash{0x01}))
+
+	inFlight.done(relayA, boostTypes.Hash{0x01})
+	require.True(t, inFlight.start(relayA, boostTypes.Hash{0x01}))
+}
+
+func TestConcurrentDuplicateSubmission(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	relay := &blockingRelay{testRelay: testRelay, release: make(chan struct{})}
+	builder.relay = relay
+
+	req := newTestSubmitBlockRequest(t, nil)
+	errCh := make(chan error)
+	go func() { errCh <- builder.submitBlock("first", "", nil, req, 25) }()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&relay.submitted) == 1 }, time.Second, time.Millisecond)
+
+	require.ErrorIs(t, builder.submitBlock("second", "", nil, req, 25), ErrSubmissionInFlight)
+
+	close(relay.release)
+	require.NoError(t, <-errCh)
+	require.Equal(t, int32(1), atomic.LoadInt32(&relay.submitted))
+
+	// Submitted again once the first submission completed
+	require.NoError(t, builder.submitBlock("third", "", nil, req, 25))
+	require.Equal(t, int32(2), atomic.LoadInt32(&relay.submitted))
+}
+
+func TestDuplicateSubmissionPerMemberRelay(t *testing.T) {
+	builder, member := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{})
+	relay := &blockingRelay{testRelay: member, release: make(chan struct{})}
+	builder.relay = NewMultiRelay([]IRelay{relay}, ValidatorConflictRecent)
+
+	req := newTestSubmitBlockRequest(t, nil)
+	errCh := make(chan error)
+	go func() { errCh <- builder.submitBlock("first", "", nil, req, 25) }()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&relay.submitted) == 1 }, time.Second, time.Millisecond)
+
+	// The block is in flight to the member relay, whichever path submits it again
+	require.ErrorIs(t, builder.submitBlock("second", "", nil, req, 25), ErrSubmissionInFlight)
+	require.ErrorIs(t, builder.submitUnlessInFlight(context.Background(), relay, req), ErrSubmissionInFlight)
+	other := &testRelay{}
+	require.NoError(t, builder.submitUnlessInFlight(context.Background(), other, req))
+	require.Equal(t, req, other.submittedMsg)
+
+	close(relay.release)
+	require.NoError(t, <-errCh)
+	require.Equal(t, int32(1), atomic.LoadInt32(&relay.submitted))
+}
@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// submissionFeed fans the submission events out to the subscribers, such as the streams of the gRPC control API.
+// The events are sent without blocking so that the submissions never wait for a subscriber, the events a subscriber
+// doesn't keep up with are dropped.
+type submissionFeed struct {
+	mu          sync.Mutex
+	subscribers map[chan<- SubmissionEvent]struct{}
+}
+
+func (f *submissionFeed) subscribe(ch chan<- SubmissionEvent) func() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.subscribers == nil {
+		f.subscribers = make(map[chan<- SubmissionEvent]struct{})
+	}
+	f.subscribers[ch] = struct{}{}
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.subscribers, ch)
+	}
+}
+
+func (f *submissionFeed) active() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers) > 0
+}
+
+func (f *submissionFeed) send(event SubmissionEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for ch := range f.subscribers {
+		select {
+		case ch <- event:
+		default:
+			submissionFeedDroppedCounter.Inc(1)
+			log.Debug("submission subscriber not keeping up, dropping event", "submission_id", event.SubmissionID, "slot", event.Slot)
+		}
+	}
+}
+
+// SubscribeSubmissions sends the outcome of every later submission to the channel until unsubscribed, as it is
+// posted to the webhook. The events are dropped while the channel is full.
+func (b *Builder) SubscribeSubmissions(ch chan<- SubmissionEvent) (unsubscribe func()) {
+	return b.submissions.subscribe(ch)
+}
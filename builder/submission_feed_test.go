@@ -0,0 +1,28 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeSubmissions(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SingleShot: true})
+
+	events := make(chan SubmissionEvent, 1)
+	unsubscribe := builder.SubscribeSubmissions(events)
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	event := <-events
+	require.Equal(t, uint64(25), event.Slot)
+	require.Equal(t, testRelay.submittedMsg.Message.BlockHash, event.BlockHash)
+	require.True(t, event.Submitted)
+
+	// The events are dropped rather than block the submissions while the channel is full
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(26)))
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(27)))
+	require.Equal(t, uint64(26), (<-events).Slot)
+
+	unsubscribe()
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(28)))
+	require.Empty(t, events)
+}
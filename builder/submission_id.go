@@ -0,0 +1,48 @@
+package builder
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// SubmissionIDHeader carries the submission ID to the relay, to correlate a submission across builder and relay logs
+const SubmissionIDHeader = "X-Builder-Submission-Id"
+
+type submissionIDKey struct{}
+
+// newSubmissionID returns a random UUID drawn from the builder's randomness.
+func (b *Builder) newSubmissionID() string {
+	id, err := uuid.NewRandomFromReader(b.rand)
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
+
+func withSubmissionID(ctx context.Context, submissionID string) context.Context {
+	return context.WithValue(ctx, submissionIDKey{}, submissionID)
+}
+
+// SubmissionIDFromContext returns the ID of the submission the context belongs to, if any.
+func SubmissionIDFromContext(ctx context.Context) (string, bool) {
+	submissionID, ok := ctx.Value(submissionIDKey{}).(string)
+	return submissionID, ok
+}
+
+// submissionIDTransport sets the submission ID header on requests made with a submission context.
+type submissionIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t submissionIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	submissionID, ok := SubmissionIDFromContext(req.Context())
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set(SubmissionIDHeader, submissionID)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,177 @@
+package builder
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+type pendingSubmission struct {
+	submissionID string
+	// clientVersion is the version of the EL which built the block
+	clientVersion string
+	// proposerIndex is the validator index of the slot's proposer, nil if the beacon node did not have it
+	proposerIndex *uint64
+	req           *boostTypes.BuilderSubmitBlockRequest
+	slot          uint64
+	deadline      time.Time
+}
+
+// submissionRetryBuffer keeps the most recent failed submission, to re-send it once the relay recovers.
+type submissionRetryBuffer struct {
+	mu     sync.Mutex
+	latest *pendingSubmission
+}
+
+func (r *submissionRetryBuffer) Put(submission *pendingSubmission) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.latest == nil || submission.slot >= r.latest.slot {
+		r.latest = submission
+	}
+}
+
+// Take removes the buffered submission, returning nil if there is none or its deadline has passed.
+func (r *submissionRetryBuffer) Take(now time.Time) *pendingSubmission {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	submission := r.latest
+	r.latest = nil
+	if submission == nil || !now.Before(submission.deadline) {
+		return nil
+	}
+	return submission
+}
+
+func (r *submissionRetryBuffer) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.latest = nil
+}
+
+// submitBlock submits the block through the circuit breaker, buffering it for a retry if the relay is unavailable.
+// The outcome is posted to the webhook, including the submissions not sent to any relay.
+func (b *Builder) submitBlock(submissionID string, clientVersion string, proposerIndex *uint64, req *boostTypes.BuilderSubmitBlockRequest, slot uint64) (err error) {
+	var result SubmissionResult
+	defer func() { b.notifySubmission(submissionID, req, result, err) }()
+
+	submission := &pendingSubmission{
+		submissionID:  submissionID,
+		clientVersion: clientVersion,
+		proposerIndex: proposerIndex,
+		req:           req,
+		slot:          slot,
+		deadline:      b.slotDeadline(slot),
+	}
+
+	if !b.anyRelayEnabled() {
+		return ErrRelayDisabled
+	}
+
+	if !b.breaker.Allow() {
+		b.retries.Put(submission)
+		return ErrCircuitOpen
+	}
+	if !b.latencySLA.Allow(time.Now()) {
+		return ErrRelaySLAViolated
+	}
+
+	start := time.Now()
+	result = b.submitToEnabledRelays(submissionContext(submission), req)
+	err = result.Err()
+	b.latencySLA.Observe(time.Now(), time.Since(start))
+	if errors.Is(err, ErrBidBelowMinValue) || errors.Is(err, ErrRelayDisabled) || errors.Is(err, ErrSlotByteBudgetExceeded) || errors.Is(err, ErrSubmissionInFlight) {
+		// No relay was submitted to, which says nothing about the relays' availability
+		return err
+	}
+	if err != nil && result.RateLimited() {
+		// The relays are up and asked to back off, a buffered retry would only add to their rate limits
+		return err
+	}
+	if err != nil && result.Rejected() {
+		// The relays are up and refused the block, which a retry would resend as is
+		b.onAllRelaysRejected(submissionID, req, result, err)
+		return err
+	}
+	if err != nil {
+		b.breaker.Failure()
+		if len(b.failoverRelays) > 0 {
+			relay, failoverErr := b.submitToFailoverRelays(submissionContext(submission), req)
+			if failoverErr == nil {
+				result = append(result, RelayOutcome{Relay: relay})
+				log.Warn("submitted block to failover relay", "submission_id", submissionID, "slot", slot, "failover_relay", relay, "err", err)
+				return nil
+			}
+			log.Error("could not submit block to the failover relays", "submission_id", submissionID, "slot", slot, "err", failoverErr)
+		}
+		b.retries.Put(submission)
+		return err
+	}
+
+	b.breaker.Success()
+	b.retries.Clear()
+	return nil
+}
+
+// resubmitBuffered re-sends the buffered submission when the circuit breaker half-opens.
+// Without a buffered submission, the next regular submission probes the relay.
+func (b *Builder) resubmitBuffered() {
+	submission := b.retries.Take(time.Now())
+	if submission == nil {
+		return
+	}
+
+	if !b.anyRelayEnabled() || b.Paused() {
+		b.retries.Put(submission)
+		return
+	}
+
+	logger := log.New("submission_id", submission.submissionID, "slot", submission.slot)
+	result := b.submitToEnabledRelays(submissionContext(submission), submission.req)
+	err := result.Err()
+	if errors.Is(err, ErrSubmissionInFlight) {
+		logger.Debug("buffered block already being submitted", "block_hash", submission.req.ExecutionPayload.BlockHash)
+		return
+	}
+	b.notifySubmission(submission.submissionID, submission.req, result, err)
+	if err != nil && result.RateLimited() {
+		logger.Warn("dropping the buffered block, the relays are rate limiting the submissions", "err", err)
+		return
+	}
+	if err != nil && result.Rejected() {
+		logger.Warn("dropping the buffered block, the relays rejected it", "err", err)
+		b.onAllRelaysRejected(submission.submissionID, submission.req, result, err)
+		return
+	}
+	if err != nil {
+		logger.Error("could not resubmit block after relay reconnect", "err", err)
+		b.breaker.Failure()
+		b.retries.Put(submission)
+		return
+	}
+
+	b.breaker.Success()
+	b.payloads.Add(submission.slot, submission.req.ExecutionPayload)
+	b.history.Add(submission.slot, submission.req.ExecutionPayload.BlockHash, submission.req.ExecutionPayload.BlockNumber, b.reconcileAt(submission.slot))
+	logger.Info("resubmitted block after relay reconnect", "block_hash", submission.req.ExecutionPayload.BlockHash)
+}
+
+// reconcileAt is the time the slot's block is expected to be imported by the EL.
+func (b *Builder) reconcileAt(slot uint64) time.Time {
+	return b.slotDeadline(slot).Add(reconcileDelaySlots * b.slotDuration())
+}
+
+// slotDeadline is the start of the slot, when its proposer requests bids. Without the genesis time
+// the deadline is one slot duration from now.
+func (b *Builder) slotDeadline(slot uint64) time.Time {
+	if b.genesisTime == 0 {
+		return time.Now().Add(b.slotDuration())
+	}
+	return time.Unix(int64(b.genesisTime+slot*b.secondsPerSlot), 0)
+}
@@ -0,0 +1,97 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmissionRetryBuffer(t *testing.T) {
+	var buffer submissionRetryBuffer
+	now := time.Now()
+
+	require.Nil(t, buffer.Take(now))
+
+	buffer.Put(&pendingSubmission{slot: 10, deadline: now.Add(time.Second)})
+	// Older slots don't replace more recent submissions
+	buffer.Put(&pendingSubmission{slot: 9, deadline: now.Add(time.Second)})
+	submission := buffer.Take(now)
+	require.NotNil(t, submission)
+	require.Equal(t, uint64(10), submission.slot)
+	require.Nil(t, buffer.Take(now))
+
+	// Submissions past their deadline are discarded
+	buffer.Put(&pendingSubmission{slot: 11, deadline: now})
+	require.Nil(t, buffer.Take(now))
+}
+
+func TestResubmitAfterRelayReconnect(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{
+		CircuitBreakerThreshold: 1,
+		CircuitBreakerCooldown:  100 * time.Millisecond,
+	})
+
+	executableData := newTestExecutableData()
+
+	testRelay.submitErr = errors.New("relay unavailable")
+	err := builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25)
+	require.Error(t, err)
+	require.Equal(t, circuitOpen, builder.breaker.State())
+
+	err = builder.onSealedBlock(builder.eth, executableData, newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	// The relay recovers, the buffered submission is re-sent when the breaker half-opens
+	testRelay.submitErr = nil
+	time.Sleep(300 * time.Millisecond)
+	require.NotNil(t, testRelay.submittedMsg)
+	require.Equal(t, uint64(25), testRelay.submittedMsg.Message.Slot)
+	require.Equal(t, circuitClosed, builder.breaker.State())
+
+	_, found := builder.payloads.Get(boostTypes.Hash{0x09, 0xff})
+	require.True(t, found)
+}
+
+func TestRateLimitedSubmissionNotRetried(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{CircuitBreakerThreshold: 1})
+
+	// Neither a circuit breaker failure nor buffered, whether suppressed or answered with a 429
+	for _, submitErr := range []error{
+		fmt.Errorf("relay: %w", ErrRelayRateLimited),
+		fmt.Errorf("relay: %w", &RelayResponseError{StatusCode: http.StatusTooManyRequests}),
+	} {
+		testRelay.submitErr = submitErr
+		err := builder.onSealedBlock(builder.eth, newTestExecutableData(), newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25)
+		require.ErrorIs(t, err, submitErr)
+		require.Equal(t, circuitClosed, builder.breaker.State())
+		require.Nil(t, builder.retries.Take(time.Now()))
+	}
+
+	testRelay.submitErr = errors.New("relay unavailable")
+	require.Error(t, builder.onSealedBlock(builder.eth, newTestExecutableData(), newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25))
+	require.Equal(t, circuitOpen, builder.breaker.State())
+}
+
+func TestRejectedSubmissionNotRetried(t *testing.T) {
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{CircuitBreakerThreshold: 1})
+
+	// A relay refusing the block is up, the rejection is neither a circuit breaker failure nor buffered
+	testRelay.submitErr = fmt.Errorf("relay: %w", &RelayResponseError{StatusCode: http.StatusBadRequest})
+	err := builder.onSealedBlock(builder.eth, newTestExecutableData(), newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25)
+	require.ErrorIs(t, err, testRelay.submitErr)
+	require.Equal(t, circuitClosed, builder.breaker.State())
+	require.Nil(t, builder.retries.Take(time.Now()))
+
+	// Nor is a buffered block the relays reject on its resubmission
+	testRelay.submitErr = errors.New("relay unavailable")
+	require.Error(t, builder.onSealedBlock(builder.eth, newTestExecutableData(), newTestBlock(10), nil, boostTypes.PublicKey{}, boostTypes.Address{}, nil, 25))
+	require.Equal(t, circuitOpen, builder.breaker.State())
+	testRelay.submitErr = fmt.Errorf("relay: %w", &RelayResponseError{StatusCode: http.StatusBadRequest})
+	builder.resubmitBuffered()
+	require.Nil(t, builder.retries.Take(time.Now()))
+}
@@ -0,0 +1,107 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrTxPolicy is returned for the built blocks including a denied transaction or missing a required one
+var ErrTxPolicy = errors.New("block violates the transaction policy")
+
+// txPolicy is the set of the transactions denied from a build, by hash or sender, and required in its block.
+type txPolicy struct {
+	required      []common.Hash
+	deniedTxs     map[common.Hash]struct{}
+	deniedSenders map[common.Address]struct{}
+}
+
+// newTxPolicy returns the build parameters' transaction policy, nil if they have none.
+func newTxPolicy(params *BuildParams) *txPolicy {
+	if params == nil || (len(params.RequiredTxs) == 0 && len(params.DeniedTxs) == 0 && len(params.DeniedSenders) == 0) {
+		return nil
+	}
+
+	policy := &txPolicy{
+		required:      params.RequiredTxs,
+		deniedTxs:     make(map[common.Hash]struct{}, len(params.DeniedTxs)),
+		deniedSenders: make(map[common.Address]struct{}, len(params.DeniedSenders)),
+	}
+	for _, hash := range params.DeniedTxs {
+		policy.deniedTxs[hash] = struct{}{}
+	}
+	for _, sender := range params.DeniedSenders {
+		policy.deniedSenders[sender] = struct{}{}
+	}
+	return policy
+}
+
+// filter drops the denied senders and transactions from the pending transactions, along with the account's later
+// transactions which can't be included without them.
+func (p *txPolicy) filter(pending map[common.Address]types.Transactions) map[common.Address]types.Transactions {
+	filtered := make(map[common.Address]types.Transactions, len(pending))
+	for account, txs := range pending {
+		if _, denied := p.deniedSenders[account]; denied {
+			continue
+		}
+		included := txs
+		for i, tx := range txs {
+			if _, denied := p.deniedTxs[tx.Hash()]; denied {
+				included = txs[:i]
+				break
+			}
+		}
+		if len(included) > 0 {
+			filtered[account] = included
+		}
+	}
+	return filtered
+}
+
+// checkBundle returns ErrTxPolicy if the bundle includes a denied transaction or a transaction of a denied sender.
+func (p *txPolicy) checkBundle(b *bundle) error {
+	for i, tx := range b.txs {
+		if _, denied := p.deniedTxs[tx.Hash()]; denied {
+			return fmt.Errorf("%w: denied transaction %d (%s) in the bundle", ErrTxPolicy, i, tx.Hash())
+		}
+		if _, denied := p.deniedSenders[b.nonces[i].sender]; denied {
+			return fmt.Errorf("%w: transaction %d (%s) of denied sender %s in the bundle", ErrTxPolicy, i, tx.Hash(), b.nonces[i].sender)
+		}
+	}
+	return nil
+}
+
+// check returns ErrTxPolicy if the block includes a denied transaction, besides the proposer payment, or misses a
+// required transaction.
+func (p *txPolicy) check(block *types.Block, signer types.Signer) error {
+	txs := block.Transactions()
+	if block.Profit != nil && block.Profit.Sign() > 0 && len(txs) > 0 {
+		txs = txs[:len(txs)-1]
+	}
+
+	included := make(map[common.Hash]struct{}, len(txs))
+	for i, tx := range txs {
+		included[tx.Hash()] = struct{}{}
+		if _, denied := p.deniedTxs[tx.Hash()]; denied {
+			return fmt.Errorf("%w: denied transaction %d (%s) in the block", ErrTxPolicy, i, tx.Hash())
+		}
+		if len(p.deniedSenders) == 0 {
+			continue
+		}
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			return fmt.Errorf("could not recover the sender of transaction %d (%s): %w", i, tx.Hash(), err)
+		}
+		if _, denied := p.deniedSenders[sender]; denied {
+			return fmt.Errorf("%w: transaction %d (%s) of denied sender %s in the block", ErrTxPolicy, i, tx.Hash(), sender)
+		}
+	}
+	for _, hash := range p.required {
+		if _, found := included[hash]; !found {
+			return fmt.Errorf("%w: required transaction %s missing from the block", ErrTxPolicy, hash)
+		}
+	}
+	return nil
+}
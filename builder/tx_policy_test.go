@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxPolicy(t *testing.T) {
+	require.Nil(t, newTxPolicy(nil))
+	require.Nil(t, newTxPolicy(&BuildParams{MaxTransactions: 1}))
+
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	keyA, _ := crypto.GenerateKey()
+	keyB, _ := crypto.GenerateKey()
+	accountA, accountB := crypto.PubkeyToAddress(keyA.PublicKey), crypto.PubkeyToAddress(keyB.PublicKey)
+	signTx := func(nonce uint64) *types.Transaction {
+		return types.MustSignNewTx(keyA, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: nonce, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(100)})
+	}
+	txA0, txA1, txA2 := signTx(0), signTx(1), signTx(2)
+	txB0 := types.MustSignNewTx(keyB, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(100)})
+	pending := map[common.Address]types.Transactions{
+		accountA: {txA0, txA1, txA2},
+		accountB: {txB0},
+	}
+
+	// Denying a transaction drops the sender's later transactions as well
+	policy := newTxPolicy(&BuildParams{DeniedTxs: []common.Hash{txA1.Hash()}, DeniedSenders: []common.Address{accountB}})
+	filtered := policy.filter(pending)
+	require.Equal(t, map[common.Address]types.Transactions{accountA: {txA0}}, filtered)
+	// The pending transactions are not modified
+	require.Len(t, pending[accountA], 3)
+
+	block := types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{txA0}, nil)
+	require.NoError(t, policy.check(block, signer))
+
+	block = types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{txA0, txA1}, nil)
+	require.ErrorIs(t, policy.check(block, signer), ErrTxPolicy)
+
+	block = types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{txA0, txB0}, nil)
+	require.ErrorIs(t, policy.check(block, signer), ErrTxPolicy)
+
+	// The proposer payment is not checked
+	block.Profit = big.NewInt(1)
+	require.NoError(t, policy.check(block, signer))
+
+	policy = newTxPolicy(&BuildParams{RequiredTxs: []common.Hash{txA0.Hash(), txB0.Hash()}})
+	block = types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{txA0, txB0}, nil)
+	require.NoError(t, policy.check(block, signer))
+
+	block = types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{txA0}, nil)
+	require.ErrorContains(t, policy.check(block, signer), "required transaction")
+}
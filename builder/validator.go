@@ -1,12 +1,14 @@
 package builder
 
 import (
+	"crypto/sha256"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	"github.com/flashbots/go-boost-utils/bls"
 	boostTypes "github.com/flashbots/go-boost-utils/types"
+	blst "github.com/supranational/blst/bindings/go"
 )
 
 type ValidatorPrivateData struct {
@@ -22,6 +24,19 @@ func NewRandomValidator() *ValidatorPrivateData {
 	return &ValidatorPrivateData{sk, pk.Compress()}
 }
 
+// NewTestSecretKey derives the secret key from the seed, for tests and reproducible environments which need the same
+// pubkey and signatures on every run. Anyone knowing the seed knows the key, production keys must be random.
+func NewTestSecretKey(seed []byte) *bls.SecretKey {
+	ikm := sha256.Sum256(seed)
+	return blst.KeyGen(ikm[:])
+}
+
+// NewTestValidator returns the validator with the key derived from the seed, see NewTestSecretKey.
+func NewTestValidator(seed []byte) *ValidatorPrivateData {
+	sk := NewTestSecretKey(seed)
+	return &ValidatorPrivateData{sk, bls.PublicKeyFromSecretKey(sk).Compress()}
+}
+
 func (v *ValidatorPrivateData) Sign(msg boostTypes.HashTreeRoot, d boostTypes.Domain) (boostTypes.Signature, error) {
 	return boostTypes.SignMessage(msg, d, v.sk)
 }
@@ -38,7 +53,7 @@ func (v *ValidatorPrivateData) PrepareRegistrationMessage(feeRecipientHex string
 	msg := &boostTypes.RegisterValidatorRequestMessage{
 		FeeRecipient: address,
 		GasLimit:     1000,
-		Timestamp:    uint64(time.Now().UnixMilli()),
+		Timestamp:    uint64(time.Now().Unix()),
 		Pubkey:       pubkey,
 	}
 	signature, err := v.Sign(msg, boostTypes.DomainBuilder)
@@ -0,0 +1,27 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTestSecretKey(t *testing.T) {
+	sk := NewTestSecretKey([]byte("builder"))
+	require.Equal(t, sk.Serialize(), NewTestSecretKey([]byte("builder")).Serialize())
+	require.NotEqual(t, sk.Serialize(), NewTestSecretKey([]byte("builder2")).Serialize())
+
+	var pubkey boostTypes.PublicKey
+	require.NoError(t, pubkey.FromSlice(bls.PublicKeyFromSecretKey(sk).Compress()))
+	require.Equal(t, "0xa7f236180b11563879cc1128c653cc8fd21e6f5f18560a6a4b285037ea84c8ef78454ef39e0f87aa7ec5692127d866b2", pubkey.String())
+
+	domain := boostTypes.ComputeDomain(boostTypes.DomainTypeAppBuilder, [4]byte{0x02, 0x0, 0x0, 0x0}, boostTypes.Hash{})
+	signature, err := boostTypes.SignMessage(&boostTypes.BidTrace{Slot: 1}, domain, sk)
+	require.NoError(t, err)
+	require.Equal(t, "0xb74e1307716c349213b25347f7b8b971ce287fc36aa7ab54732a23f1a29f9a9ec08a334497db7d727bea5901dba1d509119b8637fa71c25a46cbba176c4bb8736c18a3cad3c3e004521ee93c22662ced3a033a74533ca02d6dce45094415108d", signature.String())
+
+	validator := NewTestValidator([]byte("validator"))
+	require.Equal(t, "0x930202eb551ec84a7358d8d98040c027289ee0cb5e69675110ed9b558c62b8427a6dd24314067367bd62fb5b0549fe53", validator.Pk.String())
+}
@@ -0,0 +1,154 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+const (
+	// defaultWebhookQueueSize is the number of events queued for the webhook, later events are dropped
+	defaultWebhookQueueSize = 256
+	// webhookMaxAttempts is the number of times an event is posted before it is dropped
+	webhookMaxAttempts = 3
+	webhookTimeout     = 5 * time.Second
+)
+
+// SubmissionEvent is the outcome of a block submission posted to the webhook.
+type SubmissionEvent struct {
+	SubmissionID string             `json:"submissionId"`
+	Slot         uint64             `json:"slot"`
+	BlockHash    boostTypes.Hash    `json:"blockHash"`
+	Value        boostTypes.U256Str `json:"value"`
+	// Submitted is set if any relay accepted the block, Error tells why not otherwise
+	Submitted bool   `json:"submitted"`
+	Error     string `json:"error,omitempty"`
+	// AllRelaysRejected is set if no relay accepted the block and some rejected it
+	AllRelaysRejected bool                `json:"allRelaysRejected,omitempty"`
+	Relays            []RelayOutcomeEvent `json:"relays"`
+	Time              time.Time           `json:"time"`
+}
+
+// RelayOutcomeEvent is a relay's outcome of the submission, Error is empty if the relay accepted the block.
+type RelayOutcomeEvent struct {
+	Relay   string `json:"relay"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func newSubmissionEvent(submissionID string, req *boostTypes.BuilderSubmitBlockRequest, result SubmissionResult, err error) SubmissionEvent {
+	event := SubmissionEvent{
+		SubmissionID: submissionID,
+		Slot:         req.Message.Slot,
+		BlockHash:    req.Message.BlockHash,
+		Value:        req.Message.Value,
+		Submitted:    err == nil,
+		Relays:       make([]RelayOutcomeEvent, 0, len(result)),
+		Time:         time.Now(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+		event.AllRelaysRejected = result.Rejected()
+	}
+	for _, outcome := range result {
+		relayEvent := RelayOutcomeEvent{Relay: outcome.Relay, Skipped: outcome.Skipped}
+		if outcome.Err != nil {
+			relayEvent.Error = outcome.Err.Error()
+		}
+		event.Relays = append(event.Relays, relayEvent)
+	}
+	return event
+}
+
+// WebhookNotifier posts the submission events as JSON to a webhook. The events are queued and posted in the
+// background, retried up to webhookMaxAttempts times, so that the submissions never wait for the webhook.
+type WebhookNotifier struct {
+	url    string
+	client http.Client
+	queue  chan SubmissionEvent
+	// retryInterval is the wait before an event is posted again
+	retryInterval time.Duration
+}
+
+// NewWebhookNotifier returns a notifier posting to the url, queueing up to queueSize events or
+// defaultWebhookQueueSize if zero.
+func NewWebhookNotifier(url string, queueSize int) *WebhookNotifier {
+	if queueSize <= 0 {
+		queueSize = defaultWebhookQueueSize
+	}
+	n := &WebhookNotifier{
+		url:           url,
+		client:        http.Client{Timeout: webhookTimeout},
+		queue:         make(chan SubmissionEvent, queueSize),
+		retryInterval: time.Second,
+	}
+	go n.run()
+	return n
+}
+
+// Notify queues the event, dropping it if the queue is full.
+func (n *WebhookNotifier) Notify(event SubmissionEvent) {
+	select {
+	case n.queue <- event:
+	default:
+		webhookDroppedCounter.Inc(1)
+		log.Warn("webhook queue full, dropping submission event", "submission_id", event.SubmissionID, "slot", event.Slot)
+	}
+}
+
+func (n *WebhookNotifier) run() {
+	for event := range n.queue {
+		var err error
+		for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+			if err = n.post(event); err == nil {
+				break
+			}
+			if attempt < webhookMaxAttempts {
+				time.Sleep(n.retryInterval)
+			}
+		}
+		if err != nil {
+			webhookDroppedCounter.Inc(1)
+			log.Warn("could not post submission event to the webhook", "err", err, "submission_id", event.SubmissionID, "slot", event.Slot)
+		}
+	}
+}
+
+func (n *WebhookNotifier) post(event SubmissionEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// notifySubmission posts the submission's outcome to the webhook if set, and sends it to the subscribers.
+func (b *Builder) notifySubmission(submissionID string, req *boostTypes.BuilderSubmitBlockRequest, result SubmissionResult, err error) {
+	if b.webhook == nil && !b.submissions.active() {
+		return
+	}
+	event := newSubmissionEvent(submissionID, req, result, err)
+	if b.webhook != nil {
+		b.webhook.Notify(event)
+	}
+	b.submissions.send(event)
+}
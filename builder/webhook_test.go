@@ -0,0 +1,91 @@
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	events   []SubmissionEvent
+	failures int
+}
+
+func newWebhookServer(t *testing.T, failures int) *webhookServer {
+	s := &webhookServer{failures: failures}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.failures > 0 {
+			s.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var event SubmissionEvent
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		s.events = append(s.events, event)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookServer) received() []SubmissionEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SubmissionEvent(nil), s.events...)
+}
+
+func TestWebhookSubmissionEvents(t *testing.T) {
+	srv := newWebhookServer(t, 0)
+	webhook := NewWebhookNotifier(srv.URL, 0)
+	builder, testRelay := newTestBuilderWithOptions(t, newTestEthereumService(), BuilderOptions{SingleShot: true, Webhook: webhook})
+
+	require.NoError(t, builder.OnPayloadAttribute(newTestAttributes(25)))
+	require.Eventually(t, func() bool { return len(srv.received()) == 1 }, time.Second, 10*time.Millisecond)
+
+	event := srv.received()[0]
+	require.Equal(t, uint64(25), event.Slot)
+	require.Equal(t, testRelay.submittedMsg.Message.BlockHash, event.BlockHash)
+	require.Equal(t, testRelay.submittedMsg.Message.Value, event.Value)
+	require.True(t, event.Submitted)
+	require.Equal(t, []RelayOutcomeEvent{{Relay: "unknown"}}, event.Relays)
+
+	// The failed submissions are posted with the relays' errors
+	testRelay.submitErr = errors.New("relay unavailable")
+	builder.OnPayloadAttribute(newTestAttributes(26))
+	require.Eventually(t, func() bool { return len(srv.received()) >= 2 }, time.Second, 10*time.Millisecond)
+
+	event = srv.received()[1]
+	require.Equal(t, uint64(26), event.Slot)
+	require.False(t, event.Submitted)
+	require.Equal(t, "relay unavailable", event.Error)
+	require.Equal(t, []RelayOutcomeEvent{{Relay: "unknown", Error: "relay unavailable"}}, event.Relays)
+}
+
+func TestWebhookRetries(t *testing.T) {
+	srv := newWebhookServer(t, webhookMaxAttempts-1)
+	webhook := NewWebhookNotifier(srv.URL, 0)
+	webhook.retryInterval = time.Millisecond
+
+	webhook.Notify(SubmissionEvent{SubmissionID: "retried", BlockHash: boostTypes.Hash{0x01}})
+	require.Eventually(t, func() bool { return len(srv.received()) == 1 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, "retried", srv.received()[0].SubmissionID)
+}
+
+func TestWebhookQueueFull(t *testing.T) {
+	// Without a worker the queue is never drained
+	webhook := &WebhookNotifier{queue: make(chan SubmissionEvent, 1)}
+	webhook.Notify(SubmissionEvent{SubmissionID: "queued"})
+	webhook.Notify(SubmissionEvent{SubmissionID: "dropped"})
+	require.Len(t, webhook.queue, 1)
+	require.Equal(t, "queued", (<-webhook.queue).SubmissionID)
+}
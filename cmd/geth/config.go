@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/scwallet"
 	"github.com/ethereum/go-ethereum/accounts/usbwallet"
 	builder "github.com/ethereum/go-ethereum/builder"
+	"github.com/ethereum/go-ethereum/builder/controlapi"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
@@ -167,17 +168,94 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 	}
 
 	bpConfig := &builder.BuilderConfig{
-		Enabled:               ctx.IsSet(utils.BuilderEnabled.Name),
-		EnableValidatorChecks: ctx.IsSet(utils.BuilderEnableValidatorChecks.Name),
-		EnableLocalRelay:      ctx.IsSet(utils.BuilderEnableLocalRelay.Name),
-		BuilderSecretKey:      ctx.String(utils.BuilderSecretKey.Name),
-		RelaySecretKey:        ctx.String(utils.BuilderRelaySecretKey.Name),
-		ListenAddr:            ctx.String(utils.BuilderListenAddr.Name),
-		GenesisForkVersion:    ctx.String(utils.BuilderGenesisForkVersion.Name),
-		BellatrixForkVersion:  ctx.String(utils.BuilderBellatrixForkVersion.Name),
-		GenesisValidatorsRoot: ctx.String(utils.BuilderGenesisValidatorsRoot.Name),
-		BeaconEndpoint:        ctx.String(utils.BuilderBeaconEndpoint.Name),
-		RemoteRelayEndpoint:   ctx.String(utils.BuilderRemoteRelayEndpoint.Name),
+		Enabled:                    ctx.IsSet(utils.BuilderEnabled.Name),
+		EnableValidatorChecks:      ctx.IsSet(utils.BuilderEnableValidatorChecks.Name),
+		EnableLocalRelay:           ctx.IsSet(utils.BuilderEnableLocalRelay.Name),
+		BuilderSecretKey:           ctx.String(utils.BuilderSecretKey.Name),
+		RelaySecretKey:             ctx.String(utils.BuilderRelaySecretKey.Name),
+		ListenAddr:                 ctx.String(utils.BuilderListenAddr.Name),
+		GenesisForkVersion:         ctx.String(utils.BuilderGenesisForkVersion.Name),
+		BellatrixForkVersion:       ctx.String(utils.BuilderBellatrixForkVersion.Name),
+		GenesisValidatorsRoot:      ctx.String(utils.BuilderGenesisValidatorsRoot.Name),
+		BeaconEndpoint:             ctx.String(utils.BuilderBeaconEndpoint.Name),
+		RemoteRelayEndpoint:        ctx.String(utils.BuilderRemoteRelayEndpoint.Name),
+		BuildStrategy:              ctx.String(utils.BuilderBuildStrategy.Name),
+		AllowOverlappingBuilds:     ctx.IsSet(utils.BuilderAllowOverlappingBuilds.Name),
+		ProfitBreakdown:            ctx.IsSet(utils.BuilderProfitBreakdown.Name),
+		BidValueReserve:            ctx.String(utils.BuilderBidValueReserve.Name),
+		RecordAttributesPath:       ctx.String(utils.BuilderRecordAttributesPath.Name),
+		NotSyncedPolicy:            ctx.String(utils.BuilderNotSyncedPolicy.Name),
+		NotSyncedMaxWait:           ctx.Duration(utils.BuilderNotSyncedMaxWait.Name),
+		RegistrationCheckInterval:  ctx.Duration(utils.BuilderRegistrationCheckInterval.Name),
+		PinMempool:                 ctx.IsSet(utils.BuilderPinMempool.Name),
+		SingleShot:                 ctx.IsSet(utils.BuilderSingleShot.Name),
+		CheckRelayReachable:        ctx.IsSet(utils.BuilderCheckRelayReachable.Name),
+		Reconcile:                  ctx.IsSet(utils.BuilderReconcile.Name),
+		RemoteRelayHeaders:         ctx.StringSlice(utils.BuilderRemoteRelayHeaders.Name),
+		SelfDrivenBuilds:           ctx.IsSet(utils.BuilderSelfDrivenBuilds.Name),
+		SelfDrivenBuildDelay:       ctx.Duration(utils.BuilderSelfDrivenBuildDelay.Name),
+		MaxConcurrentBuilds:        ctx.Int(utils.BuilderMaxConcurrentBuilds.Name),
+		RelayLatencySLA:            ctx.Duration(utils.BuilderRelayLatencySLA.Name),
+		RelayLatencySLAWindow:      ctx.Duration(utils.BuilderRelayLatencySLAWindow.Name),
+		RemoteRelayCodec:           ctx.String(utils.BuilderRemoteRelayCodec.Name),
+		RemoteRelayProxy:           ctx.String(utils.BuilderRemoteRelayProxy.Name),
+		MaxRegistrationAge:         ctx.Duration(utils.BuilderMaxRegistrationAge.Name),
+		ValidatorConflictPolicy:    ctx.String(utils.BuilderValidatorConflictPolicy.Name),
+		SimulateBlocks:             ctx.IsSet(utils.BuilderSimulateBlocks.Name),
+		RemoteRelayCancelBids:      ctx.StringSlice(utils.BuilderRemoteRelayCancelBids.Name),
+		GasLimitSmoothing:          ctx.Float64(utils.BuilderGasLimitSmoothing.Name),
+		EnablePprof:                ctx.IsSet(utils.BuilderEnablePprof.Name),
+		PprofAddr:                  ctx.String(utils.BuilderPprofAddr.Name),
+		ResubmitWorkers:            ctx.Int(utils.BuilderResubmitWorkers.Name),
+		RemoteRelayElVersionHeader: ctx.IsSet(utils.BuilderRemoteRelayElVersionHeader.Name),
+		RemoteRelayMinValues:       ctx.StringSlice(utils.BuilderRemoteRelayMinValues.Name),
+		DumpDir:                    ctx.String(utils.BuilderDumpDir.Name),
+		DumpSlots:                  ctx.Uint64(utils.BuilderDumpSlots.Name),
+		ProposerPaymentKey:         ctx.String(utils.BuilderProposerPaymentKey.Name),
+		MissedSlotsThreshold:       ctx.Uint64(utils.BuilderMissedSlotsThreshold.Name),
+		RelaySecretKeys:            ctx.StringSlice(utils.BuilderRelaySecretKeys.Name),
+		SlotMismatchPolicy:         ctx.String(utils.BuilderSlotMismatchPolicy.Name),
+		SlotMismatchTolerance:      ctx.Uint64(utils.BuilderSlotMismatchTolerance.Name),
+		RemoteRelayStreams:         ctx.StringSlice(utils.BuilderRemoteRelayStreams.Name),
+		RelayFees:                  ctx.StringSlice(utils.BuilderRelayFees.Name),
+		RemoteRelaySubmitTimeout:   ctx.Duration(utils.BuilderRemoteRelaySubmitTimeout.Name),
+		RemoteRelayErrorBodyLimit:  ctx.Int(utils.BuilderRemoteRelayErrorBodyLimit.Name),
+		TimestampFlexibility:       ctx.Uint64(utils.BuilderTimestampFlexibility.Name),
+		FailoverRelayEndpoints:     ctx.String(utils.BuilderFailoverRelayEndpoints.Name),
+		RemoteRelayDisableHTTP2:    ctx.IsSet(utils.BuilderRemoteRelayDisableHTTP2.Name),
+		MaxSlotsAhead:              ctx.Uint64(utils.BuilderMaxSlotsAhead.Name),
+		InitialSubmissionDelay:     ctx.Duration(utils.BuilderInitialSubmissionDelay.Name),
+		Observer:                   ctx.IsSet(utils.BuilderObserver.Name),
+		SpeculativeBuilds:          ctx.IsSet(utils.BuilderSpeculativeBuilds.Name),
+		ExtraData:                  ctx.String(utils.BuilderExtraData.Name),
+		BeaconPolicy:               ctx.String(utils.BuilderBeaconPolicy.Name),
+		MaintenanceWindows:         ctx.String(utils.BuilderMaintenanceWindows.Name),
+		SubmissionWebhookURL:       ctx.String(utils.BuilderSubmissionWebhookURL.Name),
+		SubmissionBatchSize:        ctx.Int(utils.BuilderSubmissionBatchSize.Name),
+		SubmissionStagger:          ctx.Duration(utils.BuilderSubmissionStagger.Name),
+		ArchiveDir:                 ctx.String(utils.BuilderArchiveDir.Name),
+		AllRelaysRejectedPause:     ctx.Duration(utils.BuilderAllRelaysRejectedPause.Name),
+		MaxSubmitBytesPerSlot:      ctx.Uint64(utils.BuilderMaxSubmitBytesPerSlot.Name),
+		RemoteRelayRetry:           ctx.String(utils.BuilderRemoteRelayRetry.Name),
+		RemoteRelayRetries:         ctx.StringSlice(utils.BuilderRemoteRelayRetries.Name),
+		SigningDomainCheckInterval: ctx.Duration(utils.BuilderSigningDomainCheckInterval.Name),
+		MaxCandidateHeads:          ctx.Int(utils.BuilderMaxCandidateHeads.Name),
+		RemoteRelayDebugLog:        ctx.String(utils.BuilderRemoteRelayDebugLog.Name),
+		LazyBuild:                  ctx.IsSet(utils.BuilderLazyBuild.Name),
+		LazyBuildMaxDeficit:        ctx.String(utils.BuilderLazyBuildMaxDeficit.Name),
+		LazyBuildMaxDeficitBps:     ctx.Uint64(utils.BuilderLazyBuildMaxDeficitBps.Name),
+		DevPrevRandao:              ctx.String(utils.BuilderDevPrevRandao.Name),
+		DevChainIDs:                ctx.StringSlice(utils.BuilderDevChainIDs.Name),
+		CanarySubmissions:          ctx.IsSet(utils.BuilderCanarySubmissions.Name),
+		CircuitBreakerThreshold:    ctx.Int(utils.BuilderCircuitBreakerThreshold.Name),
+		CircuitBreakerCooldown:     ctx.Duration(utils.BuilderCircuitBreakerCooldown.Name),
+	}
+	bpConfig.RemoteRelayStreamMaxInFlight = ctx.Int(utils.BuilderRemoteRelayStreamMaxInFlight.Name)
+	bpConfig.RemoteRelayValidatorsTimeout = ctx.Duration(utils.BuilderRemoteRelayValidatorsTimeout.Name)
+	bpConfig.RemoteRelayRateLimitBackoff = ctx.Duration(utils.BuilderRemoteRelayRateLimitBackoff.Name)
+	bpConfig.AllRelaysRejectedWebhookURL = ctx.String(utils.BuilderAllRelaysRejectedWebhookURL.Name)
+	if addr := ctx.String(utils.BuilderGRPCAddr.Name); addr != "" {
+		bpConfig.Extensions = append(bpConfig.Extensions, controlapi.Extension(addr))
 	}
 
 	backend, eth := utils.RegisterEthService(stack, &cfg.Eth, bpConfig)
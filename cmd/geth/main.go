@@ -169,6 +169,81 @@ var (
 		utils.BuilderGenesisValidatorsRoot,
 		utils.BuilderBeaconEndpoint,
 		utils.BuilderRemoteRelayEndpoint,
+		utils.BuilderBuildStrategy,
+		utils.BuilderAllowOverlappingBuilds,
+		utils.BuilderProfitBreakdown,
+		utils.BuilderBidValueReserve,
+		utils.BuilderRecordAttributesPath,
+		utils.BuilderNotSyncedPolicy,
+		utils.BuilderNotSyncedMaxWait,
+		utils.BuilderRegistrationCheckInterval,
+		utils.BuilderPinMempool,
+		utils.BuilderSingleShot,
+		utils.BuilderCheckRelayReachable,
+		utils.BuilderReconcile,
+		utils.BuilderRemoteRelayHeaders,
+		utils.BuilderSelfDrivenBuilds,
+		utils.BuilderSelfDrivenBuildDelay,
+		utils.BuilderMaxConcurrentBuilds,
+		utils.BuilderRelayLatencySLA,
+		utils.BuilderRelayLatencySLAWindow,
+		utils.BuilderRemoteRelayCodec,
+		utils.BuilderRemoteRelayProxy,
+		utils.BuilderMaxRegistrationAge,
+		utils.BuilderValidatorConflictPolicy,
+		utils.BuilderSimulateBlocks,
+		utils.BuilderRemoteRelayCancelBids,
+		utils.BuilderGasLimitSmoothing,
+		utils.BuilderEnablePprof,
+		utils.BuilderPprofAddr,
+		utils.BuilderResubmitWorkers,
+		utils.BuilderRemoteRelayElVersionHeader,
+		utils.BuilderRemoteRelayMinValues,
+		utils.BuilderDumpDir,
+		utils.BuilderDumpSlots,
+		utils.BuilderProposerPaymentKey,
+		utils.BuilderMissedSlotsThreshold,
+		utils.BuilderRelaySecretKeys,
+		utils.BuilderSlotMismatchPolicy,
+		utils.BuilderSlotMismatchTolerance,
+		utils.BuilderRemoteRelayStreams,
+		utils.BuilderRemoteRelayStreamMaxInFlight,
+		utils.BuilderRelayFees,
+		utils.BuilderRemoteRelaySubmitTimeout,
+		utils.BuilderRemoteRelayValidatorsTimeout,
+		utils.BuilderRemoteRelayErrorBodyLimit,
+		utils.BuilderRemoteRelayRateLimitBackoff,
+		utils.BuilderTimestampFlexibility,
+		utils.BuilderFailoverRelayEndpoints,
+		utils.BuilderRemoteRelayDisableHTTP2,
+		utils.BuilderMaxSlotsAhead,
+		utils.BuilderInitialSubmissionDelay,
+		utils.BuilderObserver,
+		utils.BuilderSpeculativeBuilds,
+		utils.BuilderExtraData,
+		utils.BuilderBeaconPolicy,
+		utils.BuilderMaintenanceWindows,
+		utils.BuilderSubmissionWebhookURL,
+		utils.BuilderSubmissionBatchSize,
+		utils.BuilderSubmissionStagger,
+		utils.BuilderArchiveDir,
+		utils.BuilderAllRelaysRejectedPause,
+		utils.BuilderAllRelaysRejectedWebhookURL,
+		utils.BuilderMaxSubmitBytesPerSlot,
+		utils.BuilderGRPCAddr,
+		utils.BuilderRemoteRelayRetry,
+		utils.BuilderRemoteRelayRetries,
+		utils.BuilderSigningDomainCheckInterval,
+		utils.BuilderMaxCandidateHeads,
+		utils.BuilderRemoteRelayDebugLog,
+		utils.BuilderLazyBuild,
+		utils.BuilderLazyBuildMaxDeficit,
+		utils.BuilderLazyBuildMaxDeficitBps,
+		utils.BuilderDevPrevRandao,
+		utils.BuilderDevChainIDs,
+		utils.BuilderCanarySubmissions,
+		utils.BuilderCircuitBreakerThreshold,
+		utils.BuilderCircuitBreakerCooldown,
 	}
 
 	rpcFlags = []cli.Flag{
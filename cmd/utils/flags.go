@@ -718,10 +718,405 @@ var (
 	}
 	BuilderRemoteRelayEndpoint = &cli.StringFlag{
 		Name:    "builder.remote_relay_endpoint",
-		Usage:   "Relay endpoint to connect to for validator registration data, if not provided will expose validator registration locally",
+		Usage:   "Relay endpoint to connect to for validator registration data, comma separated to submit to several relays. If not provided will expose validator registration locally",
 		EnvVars: []string{"BUILDER_REMOTE_RELAY_ENDPOINT"},
 		Value:   "",
 	}
+	BuilderBuildStrategy = &cli.StringFlag{
+		Name:    "builder.build_strategy",
+		Usage:   "Block building strategy: getPayload builds a single block, custom builds a block with each prefix of the payload attributes' bundles and submits the most profitable one",
+		EnvVars: []string{"BUILDER_BUILD_STRATEGY"},
+		Value:   "getPayload",
+	}
+	BuilderAllowOverlappingBuilds = &cli.BoolFlag{
+		Name:  "builder.allow_overlapping_builds",
+		Usage: "Allow a new slot's build to start while the previous build is still running",
+	}
+	BuilderProfitBreakdown = &cli.BoolFlag{
+		Name:  "builder.profit_breakdown",
+		Usage: "Log and meter the breakdown of every submitted block's profit, requires an extra block execution",
+	}
+	BuilderBidValueReserve = &cli.StringFlag{
+		Name:    "builder.bid_value_reserve",
+		Usage:   "Amount in wei kept by the builder, the submitted bid value is the block profit minus the reserve",
+		EnvVars: []string{"BUILDER_BID_VALUE_RESERVE"},
+		Value:   "",
+	}
+	BuilderRecordAttributesPath = &cli.StringFlag{
+		Name:    "builder.record_attributes",
+		Usage:   "Path of a JSONL file all received payload attributes are appended to, for replaying them later",
+		EnvVars: []string{"BUILDER_RECORD_ATTRIBUTES"},
+		Value:   "",
+	}
+	BuilderNotSyncedPolicy = &cli.StringFlag{
+		Name:    "builder.not_synced_policy",
+		Usage:   "Behaviour when the node is not synced on new payload attributes: fail drops the slot, wait waits for the node to sync",
+		EnvVars: []string{"BUILDER_NOT_SYNCED_POLICY"},
+		Value:   "fail",
+	}
+	BuilderNotSyncedMaxWait = &cli.DurationFlag{
+		Name:    "builder.not_synced_max_wait",
+		Usage:   "Maximum time to wait for the node to sync with the wait not synced policy, bounded by the slot",
+		EnvVars: []string{"BUILDER_NOT_SYNCED_MAX_WAIT"},
+		Value:   2 * time.Second,
+	}
+	BuilderRegistrationCheckInterval = &cli.DurationFlag{
+		Name:    "builder.registration_check_interval",
+		Usage:   "Interval of the builder pubkey registration check with the remote relay, re-registering the builder if the relay lost it (0 disables)",
+		EnvVars: []string{"BUILDER_REGISTRATION_CHECK_INTERVAL"},
+		Value:   5 * time.Minute,
+	}
+	BuilderPinMempool = &cli.BoolFlag{
+		Name:    "builder.pin_mempool",
+		Usage:   "Build all blocks of a slot from the txpool snapshot taken at its first build, missing transactions arriving later in the slot",
+		EnvVars: []string{"BUILDER_PIN_MEMPOOL"},
+	}
+	BuilderSingleShot = &cli.BoolFlag{
+		Name:    "builder.single_shot",
+		Usage:   "Build and submit a single block per slot instead of resubmitting improved blocks until the slot deadline",
+		EnvVars: []string{"BUILDER_SINGLE_SHOT"},
+	}
+	BuilderCheckRelayReachable = &cli.BoolFlag{
+		Name:    "builder.check_relay_reachable",
+		Usage:   "Fail at startup if the remote relay's status endpoint is unreachable",
+		EnvVars: []string{"BUILDER_CHECK_RELAY_REACHABLE"},
+	}
+	BuilderReconcile = &cli.BoolFlag{
+		Name:    "builder.reconcile",
+		Usage:   "Check whether the won blocks landed on-chain, logging and metering the won blocks which did not",
+		EnvVars: []string{"BUILDER_RECONCILE"},
+	}
+	BuilderRemoteRelayHeaders = &cli.StringSliceFlag{
+		Name:    "builder.remote_relay_headers",
+		Usage:   "Extra headers set on every request to the remote relay, as Name=Value pairs",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_HEADERS"},
+	}
+	BuilderSelfDrivenBuilds = &cli.BoolFlag{
+		Name:    "builder.self_driven_builds",
+		Usage:   "Build on the head for the next slot if no payload attributes were received by the self-driven build delay into the current slot",
+		EnvVars: []string{"BUILDER_SELF_DRIVEN_BUILDS"},
+	}
+	BuilderSelfDrivenBuildDelay = &cli.DurationFlag{
+		Name:    "builder.self_driven_build_delay",
+		Usage:   "Time into the slot after which the next slot is built on the head if no payload attributes were received (default: half the slot)",
+		EnvVars: []string{"BUILDER_SELF_DRIVEN_BUILD_DELAY"},
+	}
+	BuilderMaxConcurrentBuilds = &cli.IntFlag{
+		Name:    "builder.max_concurrent_builds",
+		Usage:   "Maximum number of blocks built at once across slots, builds over the limit wait until the slot deadline and are dropped after (0 is unbounded)",
+		EnvVars: []string{"BUILDER_MAX_CONCURRENT_BUILDS"},
+	}
+	BuilderRelayLatencySLA = &cli.DurationFlag{
+		Name:    "builder.relay_latency_sla",
+		Usage:   "Disable the relay once its p99 submission latency exceeds the SLA for the SLA window, probing it once per slot until it recovers (0 disables)",
+		EnvVars: []string{"BUILDER_RELAY_LATENCY_SLA"},
+	}
+	BuilderRelayLatencySLAWindow = &cli.DurationFlag{
+		Name:    "builder.relay_latency_sla_window",
+		Usage:   "Window of the relay latency SLA",
+		EnvVars: []string{"BUILDER_RELAY_LATENCY_SLA_WINDOW"},
+		Value:   time.Minute,
+	}
+	BuilderRemoteRelayCodec = &cli.StringFlag{
+		Name:    "builder.remote_relay_codec",
+		Usage:   "Encoding of the block submissions to the remote relay: json or ssz, falling back to json if the relay does not support ssz",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_CODEC"},
+		Value:   "json",
+	}
+	BuilderRemoteRelayProxy = &cli.StringFlag{
+		Name:    "builder.remote_relay_proxy",
+		Usage:   "Proxy all requests to the remote relay go through: http(s)://host:port or socks5://host:port",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_PROXY"},
+	}
+	BuilderMaxRegistrationAge = &cli.DurationFlag{
+		Name:    "builder.max_registration_age",
+		Usage:   "Drop the slots whose validator registration is older (0 disables)",
+		EnvVars: []string{"BUILDER_MAX_REGISTRATION_AGE"},
+	}
+	BuilderValidatorConflictPolicy = &cli.StringFlag{
+		Name:    "builder.validator_conflict_policy",
+		Usage:   "Behaviour when remote relays disagree on the slot's validator registration: recent builds with the most recent registration, fail drops the slot",
+		EnvVars: []string{"BUILDER_VALIDATOR_CONFLICT_POLICY"},
+		Value:   "recent",
+	}
+	BuilderSimulateBlocks = &cli.BoolFlag{
+		Name:    "builder.simulate_blocks",
+		Usage:   "Simulate every built block on the EL before submitting it, dropping the blocks failing the state transition (adds a block execution per submission)",
+		EnvVars: []string{"BUILDER_SIMULATE_BLOCKS"},
+	}
+	BuilderRemoteRelayCancelBids = &cli.StringSliceFlag{
+		Name:    "builder.remote_relay_cancel_bids",
+		Usage:   "Hosts of the remote relays the blocks are submitted to with cancellations, the relays taking the builder's latest submission for a slot as its bid even if lower",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_CANCEL_BIDS"},
+	}
+	BuilderGasLimitSmoothing = &cli.Float64Flag{
+		Name:    "builder.gas_limit_smoothing",
+		Usage:   "Fraction of the gap between the parent and validator gas limits the block gas limit moves by every block (0 builds with the validator gas limit)",
+		EnvVars: []string{"BUILDER_GAS_LIMIT_SMOOTHING"},
+	}
+	BuilderEnablePprof = &cli.BoolFlag{
+		Name:    "builder.pprof",
+		Usage:   "Enable the builder pprof HTTP server, exposing the Go profiles of the process",
+		EnvVars: []string{"BUILDER_PPROF"},
+	}
+	BuilderPprofAddr = &cli.StringFlag{
+		Name:    "builder.pprof_addr",
+		Usage:   "Listening address of the builder pprof HTTP server",
+		EnvVars: []string{"BUILDER_PPROF_ADDR"},
+		Value:   "127.0.0.1:6061",
+	}
+	BuilderResubmitWorkers = &cli.IntFlag{
+		Name:    "builder.resubmit_workers",
+		Usage:   "Number of workers resubmitting the blocks of the slots until their deadline",
+		EnvVars: []string{"BUILDER_RESUBMIT_WORKERS"},
+		Value:   4,
+	}
+	BuilderRemoteRelayElVersionHeader = &cli.BoolFlag{
+		Name:    "builder.remote_relay_el_version_header",
+		Usage:   "Send the version of the EL which built the block to the remote relay with the submissions",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_EL_VERSION_HEADER"},
+	}
+	BuilderRemoteRelayMinValues = &cli.StringSliceFlag{
+		Name:    "builder.remote_relay_min_values",
+		Usage:   "Lowest bid values submitted to the remote relays, as host=wei pairs raising the minimum value published by the relay",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_MIN_VALUES"},
+	}
+	BuilderDumpDir = &cli.StringFlag{
+		Name:    "builder.dump_dir",
+		Usage:   "Directory the failed block submissions are dumped to, as the JSON submission and the RLP block named after the slot and block hash",
+		EnvVars: []string{"BUILDER_DUMP_DIR"},
+	}
+	BuilderDumpSlots = &cli.Uint64Flag{
+		Name:    "builder.dump_slots",
+		Usage:   "Number of recent slots whose block submissions are all dumped to the dump directory, older dumps are removed",
+		EnvVars: []string{"BUILDER_DUMP_SLOTS"},
+	}
+	BuilderProposerPaymentKey = &cli.StringFlag{
+		Name:    "builder.proposer_payment_key",
+		Usage:   "Hex private key of the etherbase, with which the miner pays the proposer in the blocks, replacing BUILDER_TX_SIGNING_KEY",
+		EnvVars: []string{"BUILDER_PROPOSER_PAYMENT_KEY"},
+	}
+	BuilderMissedSlotsThreshold = &cli.Uint64Flag{
+		Name:    "builder.missed_slots_threshold",
+		Usage:   "Number of consecutive slots without payload attributes after which the missed slots are logged and counted, disabled if zero",
+		EnvVars: []string{"BUILDER_MISSED_SLOTS_THRESHOLD"},
+	}
+	BuilderRelaySecretKeys = &cli.StringSliceFlag{
+		Name:    "builder.relay_secret_keys",
+		Usage:   "Builder secret keys the submissions to some remote relays are signed with instead of the builder key, as host=key pairs. The keys must be registered with the relays",
+		EnvVars: []string{"BUILDER_RELAY_SECRET_KEYS"},
+	}
+	BuilderSlotMismatchPolicy = &cli.StringFlag{
+		Name:    "builder.slot_mismatch_policy",
+		Usage:   "Behaviour when the payload attributes slot does not match the slot of their timestamp: warn logs the mismatch, reject drops the attributes",
+		EnvVars: []string{"BUILDER_SLOT_MISMATCH_POLICY"},
+		Value:   "warn",
+	}
+	BuilderSlotMismatchTolerance = &cli.Uint64Flag{
+		Name:    "builder.slot_mismatch_tolerance",
+		Usage:   "Number of slots the payload attributes slot may differ from the slot of their timestamp",
+		EnvVars: []string{"BUILDER_SLOT_MISMATCH_TOLERANCE"},
+	}
+	BuilderRemoteRelayStreams = &cli.StringSliceFlag{
+		Name:    "builder.remote_relay_streams",
+		Usage:   "Comma separated host=url pairs of the relays to stream the block submissions to over a WebSocket connection, e.g. relay.example.com=wss://relay.example.com/relay/v1/builder/blocks/stream",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_STREAMS"},
+	}
+	BuilderRemoteRelayStreamMaxInFlight = &cli.IntFlag{
+		Name:    "builder.remote_relay_stream_max_in_flight",
+		Usage:   "Number of submissions streamed to a relay without an ack before further submissions wait",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_STREAM_MAX_IN_FLIGHT"},
+		Value:   16,
+	}
+	BuilderRelayFees = &cli.StringSliceFlag{
+		Name:    "builder.relay_fees",
+		Usage:   "Comma separated relay=bps:wei pairs of the fees the relays deduct from the bid value, in basis points of the value plus a fixed fee in wei. The relay minimum values apply to the value net of the fees",
+		EnvVars: []string{"BUILDER_RELAY_FEES"},
+	}
+	BuilderRemoteRelaySubmitTimeout = &cli.DurationFlag{
+		Name:    "builder.remote_relay_submit_timeout",
+		Usage:   "Timeout of each block submission to the remote relays",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_SUBMIT_TIMEOUT"},
+		Value:   6 * time.Second,
+	}
+	BuilderRemoteRelayValidatorsTimeout = &cli.DurationFlag{
+		Name:    "builder.remote_relay_validators_timeout",
+		Usage:   "Timeout of each validators fetch from the remote relays, which are looked up before the builds",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_VALIDATORS_TIMEOUT"},
+		Value:   3 * time.Second,
+	}
+	BuilderRemoteRelayErrorBodyLimit = &cli.IntFlag{
+		Name:    "builder.remote_relay_error_body_limit",
+		Usage:   "Number of bytes of the relay's response body kept in the errors and logs of rejected block submissions",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_ERROR_BODY_LIMIT"},
+		Value:   1024,
+	}
+	BuilderRemoteRelayRateLimitBackoff = &cli.DurationFlag{
+		Name:    "builder.remote_relay_rate_limit_backoff",
+		Usage:   "How long the submissions to a relay are suppressed after a 429 response without a valid Retry-After header",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_RATE_LIMIT_BACKOFF"},
+		Value:   time.Second,
+	}
+	BuilderTimestampFlexibility = &cli.Uint64Flag{
+		Name:    "builder.timestamp_flexibility",
+		Usage:   "Number of seconds past the slot's start the relays accept as the block timestamp, the blocks are built at the latest of them. Zero keeps the payload attributes timestamp. For development networks only, see builder.dev_chain_ids",
+		EnvVars: []string{"BUILDER_TIMESTAMP_FLEXIBILITY"},
+	}
+	BuilderFailoverRelayEndpoints = &cli.StringFlag{
+		Name:    "builder.failover_relay_endpoints",
+		Usage:   "Comma separated endpoints of the relays the signed block of a failed submission to the remote relays is re-submitted to, in order until one accepts it",
+		EnvVars: []string{"BUILDER_FAILOVER_RELAY_ENDPOINTS"},
+	}
+	BuilderRemoteRelayDisableHTTP2 = &cli.BoolFlag{
+		Name:    "builder.remote_relay_disable_http2",
+		Usage:   "Submit to the remote relays over HTTP/1.1, for relays or proxies not supporting HTTP/2 which is otherwise negotiated with https relays",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_DISABLE_HTTP2"},
+	}
+	BuilderMaxSlotsAhead = &cli.Uint64Flag{
+		Name:    "builder.max_slots_ahead",
+		Usage:   "Number of slots past the current slot the payload attributes may be for, attributes of later slots are dropped. Zero disables the check",
+		EnvVars: []string{"BUILDER_MAX_SLOTS_AHEAD"},
+		Value:   0,
+	}
+	BuilderInitialSubmissionDelay = &cli.DurationFlag{
+		Name:    "builder.initial_submission_delay",
+		Usage:   "Delay before the slot's first build, letting transactions accumulate on networks with sparse mempools. At most half the slot duration",
+		EnvVars: []string{"BUILDER_INITIAL_SUBMISSION_DELAY"},
+	}
+	BuilderObserver = &cli.BoolFlag{
+		Name:    "builder.observer",
+		Usage:   "Run the builder as an observer, polling the relays' top bids of the slots without building nor submitting blocks",
+		EnvVars: []string{"BUILDER_OBSERVER"},
+	}
+	BuilderSpeculativeBuilds = &cli.BoolFlag{
+		Name:    "builder.speculative_builds",
+		Usage:   "Build a block for the next slot on the current head, submitted right away if the next slot's attributes match, as when the current slot is missed",
+		EnvVars: []string{"BUILDER_SPECULATIVE_BUILDS"},
+	}
+	BuilderExtraData = &cli.StringFlag{
+		Name:    "builder.extra_data",
+		Usage:   "Comma separated extra data values of the built blocks, rotated by slot. {slot} is replaced by the slot number",
+		EnvVars: []string{"BUILDER_EXTRA_DATA"},
+	}
+	BuilderBeaconPolicy = &cli.StringFlag{
+		Name:    "builder.beacon_policy",
+		Usage:   "Behaviour when the beacon node can't be reached for the proposer cross-check or the prevRandao of the builds without attributes: lenient warns and builds with the relay's data or the head's prevRandao, strict drops the slot",
+		EnvVars: []string{"BUILDER_BEACON_POLICY"},
+		Value:   "lenient",
+	}
+	BuilderMaintenanceWindows = &cli.StringFlag{
+		Name:    "builder.maintenance_windows",
+		Usage:   "Comma separated maintenance windows the builder is paused during, as start/end pairs of RFC 3339 times",
+		EnvVars: []string{"BUILDER_MAINTENANCE_WINDOWS"},
+	}
+	BuilderSubmissionWebhookURL = &cli.StringFlag{
+		Name:    "builder.submission_webhook_url",
+		Usage:   "URL the outcome of every block submission is posted to as JSON",
+		EnvVars: []string{"BUILDER_SUBMISSION_WEBHOOK_URL"},
+	}
+	BuilderSubmissionBatchSize = &cli.IntFlag{
+		Name:    "builder.submission_batch_size",
+		Usage:   "Number of remote relays a block is submitted to at once, the submissions go out in waves of this many relays. All relays at once if zero",
+		EnvVars: []string{"BUILDER_SUBMISSION_BATCH_SIZE"},
+	}
+	BuilderSubmissionStagger = &cli.DurationFlag{
+		Name:    "builder.submission_stagger",
+		Usage:   "Wait between two waves of submissions to the remote relays, skipped if it would end past the slot's start",
+		EnvVars: []string{"BUILDER_SUBMISSION_STAGGER"},
+	}
+	BuilderArchiveDir = &cli.StringFlag{
+		Name:    "builder.archive_dir",
+		Usage:   "Directory every block submission is archived to, whether the relays accepted it or not",
+		EnvVars: []string{"BUILDER_ARCHIVE_DIR"},
+	}
+	BuilderAllRelaysRejectedPause = &cli.DurationFlag{
+		Name:    "builder.all_relays_rejected_pause",
+		Usage:   "Pause the builder for the duration after all relays rejected a submission, 0 to not pause",
+		EnvVars: []string{"BUILDER_ALL_RELAYS_REJECTED_PAUSE"},
+	}
+	BuilderAllRelaysRejectedWebhookURL = &cli.StringFlag{
+		Name:    "builder.all_relays_rejected_webhook_url",
+		Usage:   "Webhook posted the submissions all relays rejected, for alerting",
+		EnvVars: []string{"BUILDER_ALL_RELAYS_REJECTED_WEBHOOK_URL"},
+	}
+	BuilderMaxSubmitBytesPerSlot = &cli.Uint64Flag{
+		Name:    "builder.max_submit_bytes_per_slot",
+		Usage:   "Maximum bytes submitted per slot summed across the relays, the relays are prioritized in their configured order. 0 for unbounded",
+		EnvVars: []string{"BUILDER_MAX_SUBMIT_BYTES_PER_SLOT"},
+	}
+	BuilderGRPCAddr = &cli.StringFlag{
+		Name:    "builder.grpc_addr",
+		Usage:   "Listen address of the unauthenticated gRPC control API, disabled if empty",
+		EnvVars: []string{"BUILDER_GRPC_ADDR"},
+	}
+	BuilderRemoteRelayRetry = &cli.StringFlag{
+		Name:    "builder.remote_relay_retry",
+		Usage:   "Retry policy of the failed submissions to the relays without one in builder.remote_relay_retries, as attempts:backoff with the backoff doubled before every retry, e.g. 3:50ms. Not retried if empty",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_RETRY"},
+	}
+	BuilderRemoteRelayRetries = &cli.StringSliceFlag{
+		Name:    "builder.remote_relay_retries",
+		Usage:   "Comma separated host=attempts:backoff pairs of the relays' retry policies",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_RETRIES"},
+	}
+	BuilderSigningDomainCheckInterval = &cli.DurationFlag{
+		Name:    "builder.signing_domain_check_interval",
+		Usage:   "Interval of the comparison of the builder signing domain with the domain of the beacon node's genesis fork version, a mismatch getting every submission rejected (0 disables)",
+		EnvVars: []string{"BUILDER_SIGNING_DOMAIN_CHECK_INTERVAL"},
+		Value:   10 * time.Minute,
+	}
+	BuilderMaxCandidateHeads = &cli.IntFlag{
+		Name:    "builder.max_candidate_heads",
+		Usage:   "Number of heads the blocks are built on during reorg uncertainty, the attributes' head and their candidate heads, submitting the most profitable block (below 2 builds on the head only)",
+		EnvVars: []string{"BUILDER_MAX_CANDIDATE_HEADS"},
+	}
+	BuilderRemoteRelayDebugLog = &cli.StringFlag{
+		Name:    "builder.remote_relay_debug_log",
+		Usage:   "Debug only: logs every request to the remote relays and its response, with the bodies' hashes (hashed) or the full bodies (full), redacting the credential and extra headers. Off by default",
+		EnvVars: []string{"BUILDER_REMOTE_RELAY_DEBUG_LOG"},
+		Value:   "off",
+	}
+	BuilderLazyBuild = &cli.BoolFlag{
+		Name:    "builder.lazy_build",
+		Usage:   "Builds the blocks of a slot after its first only when the relays' top bid looks beatable, instead of at every resubmission",
+		EnvVars: []string{"BUILDER_LAZY_BUILD"},
+	}
+	BuilderLazyBuildMaxDeficit = &cli.StringFlag{
+		Name:    "builder.lazy_build_max_deficit",
+		Usage:   "Wei a competitor's top bid may exceed the last block's profit by and still be competed with in lazy build mode",
+		EnvVars: []string{"BUILDER_LAZY_BUILD_MAX_DEFICIT"},
+	}
+	BuilderLazyBuildMaxDeficitBps = &cli.Uint64Flag{
+		Name:    "builder.lazy_build_max_deficit_bps",
+		Usage:   "Basis points of the last block's profit a competitor's top bid may exceed it by, in addition to builder.lazy_build_max_deficit, and still be competed with in lazy build mode",
+		EnvVars: []string{"BUILDER_LAZY_BUILD_MAX_DEFICIT_BPS"},
+	}
+	BuilderDevPrevRandao = &cli.StringFlag{
+		Name:    "builder.dev_prev_randao",
+		Usage:   "Overrides the prevRandao of the built blocks with this 32 bytes hex value, on the development networks only, see --builder.dev_chain_ids",
+		EnvVars: []string{"BUILDER_DEV_PREV_RANDAO"},
+	}
+	BuilderDevChainIDs = &cli.StringSliceFlag{
+		Name:    "builder.dev_chain_ids",
+		Usage:   "Chain IDs of the development networks without a real beacon chain, on which the test features such as builder.timestamp_flexibility are allowed, besides the --dev chain",
+		EnvVars: []string{"BUILDER_DEV_CHAIN_IDS"},
+	}
+	BuilderCanarySubmissions = &cli.BoolFlag{
+		Name:    "builder.canary_submissions",
+		Usage:   "Allow the builder_submitCanary calls submitting a minimal block to a relay, as a real bid for the next slot",
+		EnvVars: []string{"BUILDER_CANARY_SUBMISSIONS"},
+	}
+	BuilderCircuitBreakerThreshold = &cli.IntFlag{
+		Name:    "builder.circuit_breaker_threshold",
+		Usage:   "Number of consecutive failed relay submissions opening the circuit breaker, which stops submitting until the cooldown (0 disables)",
+		EnvVars: []string{"BUILDER_CIRCUIT_BREAKER_THRESHOLD"},
+	}
+	BuilderCircuitBreakerCooldown = &cli.DurationFlag{
+		Name:    "builder.circuit_breaker_cooldown",
+		Usage:   "Time the relay circuit breaker stays open before the relay is retried",
+		EnvVars: []string{"BUILDER_CIRCUIT_BREAKER_COOLDOWN"},
+		Value:   2 * time.Second,
+	}
 	// RPC settings
 	IPCDisabledFlag = &cli.BoolFlag{
 		Name:     "ipcdisable",
@@ -213,6 +213,13 @@ func (miner *Miner) SetEtherbase(addr common.Address) {
 	miner.worker.setEtherbase(addr)
 }
 
+// SetBuilderTxSigningKey sets the key of the etherbase signing the proposer payouts of
+// the sealing blocks, replacing the one from BUILDER_TX_SIGNING_KEY. The payouts are
+// not made if nil.
+func (miner *Miner) SetBuilderTxSigningKey(key *ecdsa.PrivateKey) {
+	miner.worker.setBuilderTxSigningKey(key)
+}
+
 // SetGasCeil sets the gaslimit to strive for when mining blocks post 1559.
 // For pre-1559 blocks, it sets the ceiling.
 func (miner *Miner) SetGasCeil(ceil uint64) {
@@ -265,3 +272,26 @@ func (miner *Miner) GetSealingBlockSync(parent common.Hash, timestamp uint64, co
 	}
 	return <-resCh, <-errCh
 }
+
+// GetSealingBlockWithPending creates a sealing block like GetSealingBlockSync,
+// filling it with the given pending transactions instead of the txpool's.
+// The given transactions are not modified, so they can be reused across calls.
+func (miner *Miner) GetSealingBlockWithPending(parent common.Hash, timestamp uint64, coinbase common.Address, gasLimit uint64, random common.Hash, pending map[common.Address]types.Transactions) (*types.Block, error) {
+	resCh, errCh, err := miner.worker.getSealingBlockWithPending(parent, timestamp, coinbase, gasLimit, random, pending, nil)
+	if err != nil {
+		return nil, err
+	}
+	return <-resCh, <-errCh
+}
+
+// GetSealingBlockWithBundles creates a sealing block like GetSealingBlockWithPending,
+// including the given bundles at the top of the block first. Each bundle's transactions
+// are included in order and contiguously, or not at all if any of them fails or reverts.
+// The txpool's transactions fill the block if pending is nil.
+func (miner *Miner) GetSealingBlockWithBundles(parent common.Hash, timestamp uint64, coinbase common.Address, gasLimit uint64, random common.Hash, pending map[common.Address]types.Transactions, bundles []types.Transactions) (*types.Block, error) {
+	resCh, errCh, err := miner.worker.getSealingBlockWithPending(parent, timestamp, coinbase, gasLimit, random, pending, bundles)
+	if err != nil {
+		return nil, err
+	}
+	return <-resCh, <-errCh
+}
@@ -87,6 +87,9 @@ const (
 var (
 	errBlockInterruptedByNewHead  = errors.New("new head arrived while building block")
 	errBlockInterruptedByRecommit = errors.New("recommit interrupt while building block")
+
+	// ErrProposerPayout is returned for the sealing blocks in which the proposer could not be paid.
+	ErrProposerPayout = errors.New("proposer payout failed")
 )
 
 // environment is the worker's current environment and holds all
@@ -105,6 +108,7 @@ type environment struct {
 	txs      []*types.Transaction
 	receipts []*types.Receipt
 	uncles   map[common.Hash]*types.Header
+	payout   *types.Transaction // proposer payout, the last transaction if committed
 }
 
 // copy creates a deep copy of environment.
@@ -118,6 +122,7 @@ func (env *environment) copy() *environment {
 		coinbase:  env.coinbase,
 		header:    types.CopyHeader(env.header),
 		receipts:  copyReceipts(env.receipts),
+		payout:    env.payout,
 	}
 	if env.gasPool != nil {
 		gasPool := *env.gasPool
@@ -337,6 +342,12 @@ func (w *worker) setEtherbase(addr common.Address) {
 	w.coinbase = addr
 }
 
+func (w *worker) setBuilderTxSigningKey(key *ecdsa.PrivateKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.config.BuilderTxSigningKey = key
+}
+
 func (w *worker) setGasCeil(ceil uint64) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -864,6 +875,44 @@ func (w *worker) commitTransaction(env *environment, tx *types.Transaction) ([]*
 	return receipt.Logs, nil
 }
 
+// commitBundle applies the bundle's transactions in order, one after the other. If any of
+// them fails or reverts, the whole bundle is reverted and none of its transactions included.
+func (w *worker) commitBundle(env *environment, bundle types.Transactions) error {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	// The state journal is cleared after each transaction, so the state is copied rather than snapshotted
+	var (
+		state    = env.state.Copy()
+		gas      = env.gasPool.Gas()
+		gasUsed  = env.header.GasUsed
+		tcount   = env.tcount
+		txs      = len(env.txs)
+		receipts = len(env.receipts)
+	)
+	revert := func() {
+		env.state = state
+		env.gasPool = new(core.GasPool).AddGas(gas)
+		env.header.GasUsed = gasUsed
+		env.tcount = tcount
+		env.txs = env.txs[:txs]
+		env.receipts = env.receipts[:receipts]
+	}
+	for _, tx := range bundle {
+		env.state.Prepare(tx.Hash(), env.tcount)
+		if _, err := w.commitTransaction(env, tx); err != nil {
+			revert()
+			return fmt.Errorf("transaction %s failed: %w", tx.Hash(), err)
+		}
+		if receipt := env.receipts[len(env.receipts)-1]; receipt.Status != types.ReceiptStatusSuccessful {
+			revert()
+			return fmt.Errorf("transaction %s reverted", tx.Hash())
+		}
+		env.tcount++
+	}
+	return nil
+}
+
 func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByPriceAndNonce, interrupt *int32) error {
 	gasLimit := env.header.GasLimit
 	if env.gasPool == nil {
@@ -989,6 +1038,9 @@ type generateParams struct {
 	noUncle    bool           // Flag whether the uncle block inclusion is allowed
 	noExtra    bool           // Flag whether the extra field assignment is allowed
 	noTxs      bool           // Flag whether an empty block without any transaction is expected
+
+	pending map[common.Address]types.Transactions // Pending transactions to fill the block with, the txpool's if nil
+	bundles []types.Transactions                  // Bundles to include at the top of the block, each atomically
 }
 
 // prepareWork constructs the sealing task according to the given parameters,
@@ -1081,10 +1133,19 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 // into the given sealing block. The transaction selection and ordering strategy can
 // be customized with the plugin in the future.
 
-func (w *worker) fillTransactions(interrupt *int32, env *environment, validatorCoinbase *common.Address) error {
+func (w *worker) fillTransactions(interrupt *int32, env *environment, validatorCoinbase *common.Address, pinned map[common.Address]types.Transactions, bundles []types.Transactions) error {
 	// Split the pending transactions into locals and remotes
-	// Fill the block with all available pending transactions.
-	pending := w.eth.TxPool().Pending(true)
+	// Fill the block with all available pending transactions, or the given ones if pinned.
+	var pending map[common.Address]types.Transactions
+	if pinned != nil {
+		// The maps are consumed below, copy to keep the pinned transactions reusable
+		pending = make(map[common.Address]types.Transactions, len(pinned))
+		for account, txs := range pinned {
+			pending[account] = txs
+		}
+	} else {
+		pending = w.eth.TxPool().Pending(true)
+	}
 	localTxs, remoteTxs := make(map[common.Address]types.Transactions), pending
 	for _, account := range w.eth.TxPool().Locals() {
 		if txs := remoteTxs[account]; len(txs) > 0 {
@@ -1102,6 +1163,11 @@ func (w *worker) fillTransactions(interrupt *int32, env *environment, validatorC
 			return err
 		}
 	}
+	for i, bundle := range bundles {
+		if err := w.commitBundle(env, bundle); err != nil {
+			log.Debug("Bundle skipped", "bundle", i, "err", err)
+		}
+	}
 	if len(localTxs) > 0 {
 		txs := types.NewTransactionsByPriceAndNonce(env.signer, localTxs, env.header.BaseFee)
 		if err := w.commitTransactions(env, txs, interrupt); err != nil {
@@ -1120,30 +1186,27 @@ func (w *worker) fillTransactions(interrupt *int32, env *environment, validatorC
 
 		profit := new(big.Int).Sub(builderCoinbaseBalanceAfter, builderCoinbaseBalanceBefore)
 		env.gasPool.AddGas(paymentTxGas)
-		if profit.Sign() == 1 {
-			tx, err := w.createProposerPayoutTx(env, validatorCoinbase, profit)
-			if err != nil {
-				log.Error("Proposer payout create tx failed", "err", err)
-				return fmt.Errorf("proposer payout create tx failed - %v", err)
-			}
-			if tx != nil {
-				log.Info("Proposer payout create tx succeeded, proceeding to commit tx")
-				env.state.Prepare(tx.Hash(), env.tcount)
-				_, err = w.commitTransaction(env, tx)
-				if err != nil {
-					log.Error("Proposer payout commit tx failed", "hash", tx.Hash().String(), "err", err)
-					return fmt.Errorf("proposer payout commit tx failed - %v", err)
-				}
-				log.Info("Proposer payout commit tx succeeded", "hash", tx.Hash().String())
-				env.tcount++
-			} else {
-				return errors.New("proposer payout create tx failed due to tx is nil")
-			}
-		} else {
-			log.Warn("Proposer payout create tx failed due to not enough balance", "profit", profit.String())
-			return errors.New("proposer payout create tx failed due to not enough balance")
+		fee := new(big.Int).Mul(big.NewInt(paymentTxGas), env.header.BaseFee)
+		if profit.Cmp(fee) <= 0 {
+			// Nothing to pay out, the block is kept without the payout and a zero profit
+			log.Debug("Proposer payout skipped, the profit does not cover the payout fee", "profit", profit.String(), "fee", fee.String())
+			return nil
 		}
-
+		tx, err := w.createProposerPayoutTx(env, validatorCoinbase, profit)
+		if err != nil {
+			log.Error("Proposer payout create tx failed", "err", err)
+			return fmt.Errorf("%w: create tx: %v", ErrProposerPayout, err)
+		}
+		log.Info("Proposer payout create tx succeeded, proceeding to commit tx")
+		env.state.Prepare(tx.Hash(), env.tcount)
+		_, err = w.commitTransaction(env, tx)
+		if err != nil {
+			log.Error("Proposer payout commit tx failed", "hash", tx.Hash().String(), "err", err)
+			return fmt.Errorf("%w: commit tx %s: %v", ErrProposerPayout, tx.Hash(), err)
+		}
+		log.Info("Proposer payout commit tx succeeded", "hash", tx.Hash().String())
+		env.tcount++
+		env.payout = tx
 	}
 	return nil
 }
@@ -1161,7 +1224,7 @@ func (w *worker) generateWork(params *generateParams) (*types.Block, error) {
 	defer work.discard()
 
 	if !params.noTxs {
-		if err := w.fillTransactions(nil, work, &validatorCoinbase); err != nil {
+		if err := w.fillTransactions(nil, work, &validatorCoinbase, params.pending, params.bundles); err != nil {
 			return nil, err
 		}
 	}
@@ -1172,30 +1235,21 @@ func (w *worker) generateWork(params *generateParams) (*types.Block, error) {
 
 	block.Profit = big.NewInt(0)
 
-	if w.config.BuilderTxSigningKey == nil {
+	// No payout without a signing key, or if the profit did not cover the payout fee
+	if params.noTxs || work.payout == nil {
 		return block, nil
 	}
 
-	if params.noTxs {
-		return block, nil
-	}
-
-	if len(work.txs) == 0 {
-		return nil, errors.New("no proposer payment tx")
-	} else if len(work.receipts) == 0 {
-		return nil, errors.New("no proposer payment receipt")
-	}
-
 	lastTx := work.txs[len(work.txs)-1]
 	receipt := work.receipts[len(work.receipts)-1]
-	if receipt.TxHash != lastTx.Hash() || receipt.Status != types.ReceiptStatusSuccessful {
+	if lastTx.Hash() != work.payout.Hash() || receipt.TxHash != lastTx.Hash() || receipt.Status != types.ReceiptStatusSuccessful {
 		log.Error("proposer payment not successful!", "lastTx", lastTx, "receipt", receipt)
-		return nil, errors.New("last transaction is not proposer payment")
+		return nil, fmt.Errorf("%w: last transaction is not proposer payment", ErrProposerPayout)
 	}
 	lastTxTo := lastTx.To()
 	if lastTxTo == nil || *lastTxTo != validatorCoinbase {
-		log.Error("last transaction is not to the proposer!", "err", err, "lastTx", lastTx)
-		return nil, errors.New("last transaction is not proposer payment")
+		log.Error("last transaction is not to the proposer!", "lastTx", lastTx)
+		return nil, fmt.Errorf("%w: last transaction is not to the proposer", ErrProposerPayout)
 	}
 
 	block.Profit.Set(lastTx.Value())
@@ -1232,7 +1286,7 @@ func (w *worker) commitWork(interrupt *int32, noempty bool, timestamp int64) {
 	}
 
 	// Fill pending transactions from the txpool
-	err = w.fillTransactions(interrupt, work, nil)
+	err = w.fillTransactions(interrupt, work, nil, nil, nil)
 	if errors.Is(err, errBlockInterruptedByNewHead) {
 		work.discard()
 		return
@@ -1288,22 +1342,42 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 // The generation result will be passed back via the given channel no matter
 // the generation itself succeeds or not.
 func (w *worker) getSealingBlock(parent common.Hash, timestamp uint64, coinbase common.Address, gasLimit uint64, random common.Hash, noTxs bool, noExtra bool) (chan *types.Block, chan error, error) {
+	return w.requestSealingBlock(&generateParams{
+		timestamp:  timestamp,
+		forceTime:  true,
+		parentHash: parent,
+		coinbase:   coinbase,
+		gasLimit:   gasLimit,
+		random:     random,
+		noUncle:    true,
+		noExtra:    noExtra,
+		noTxs:      noTxs,
+	})
+}
+
+// getSealingBlockWithPending is getSealingBlock filling the block with the given bundles,
+// then the given pending transactions.
+func (w *worker) getSealingBlockWithPending(parent common.Hash, timestamp uint64, coinbase common.Address, gasLimit uint64, random common.Hash, pending map[common.Address]types.Transactions, bundles []types.Transactions) (chan *types.Block, chan error, error) {
+	return w.requestSealingBlock(&generateParams{
+		timestamp:  timestamp,
+		forceTime:  true,
+		parentHash: parent,
+		coinbase:   coinbase,
+		gasLimit:   gasLimit,
+		random:     random,
+		noUncle:    true,
+		pending:    pending,
+		bundles:    bundles,
+	})
+}
+
+func (w *worker) requestSealingBlock(params *generateParams) (chan *types.Block, chan error, error) {
 	var (
 		resCh = make(chan *types.Block, 1)
 		errCh = make(chan error, 1)
 	)
 	req := &getWorkReq{
-		params: &generateParams{
-			timestamp:  timestamp,
-			forceTime:  true,
-			parentHash: parent,
-			coinbase:   coinbase,
-			gasLimit:   gasLimit,
-			random:     random,
-			noUncle:    true,
-			noExtra:    noExtra,
-			noTxs:      noTxs,
-		},
+		params: params,
 		result: resCh,
 		err:    errCh,
 	}